@@ -0,0 +1,145 @@
+package activity
+
+import "time"
+
+// Session is practice time recorded at a moment
+type Session struct {
+	Date    int64
+	Minutes int64
+}
+
+// Day is one day of the calendar. Date is midnight UTC on that day in the user's timezone, so days
+// can be compared and stepped through without daylight saving getting in the way.
+type Day struct {
+	Date      time.Time
+	Minutes   int64
+	Practiced bool
+}
+
+// Calendar is practice time grouped by day in the user's timezone
+type Calendar struct {
+	loc  *time.Location
+	days map[int64]Day
+}
+
+// DayNumber is the number of days since the unix epoch for the date of t in loc
+func DayNumber(t time.Time, loc *time.Location) int64 {
+	y, m, d := t.In(loc).Date()
+	return time.Date(y, m, d, 0, 0, 0, 0, time.UTC).Unix() / (24 * 60 * 60)
+}
+
+// DayDate is midnight UTC on day n, the same way Day.Date is stored
+func DayDate(n int64) time.Time {
+	return time.Unix(n*24*60*60, 0).UTC()
+}
+
+// New groups practice sessions and other times something was practiced into days. Times in practiced
+// mark the day as practiced without adding minutes.
+func New(loc *time.Location, sessions []Session, practiced []int64) Calendar {
+	c := Calendar{loc: loc, days: make(map[int64]Day)}
+	for _, session := range sessions {
+		n := DayNumber(time.Unix(session.Date, 0), loc)
+		day := c.day(n)
+		day.Minutes += session.Minutes
+		day.Practiced = true
+		c.days[n] = day
+	}
+	for _, t := range practiced {
+		n := DayNumber(time.Unix(t, 0), loc)
+		day := c.day(n)
+		day.Practiced = true
+		c.days[n] = day
+	}
+	return c
+}
+
+func (c Calendar) day(n int64) Day {
+	if day, ok := c.days[n]; ok {
+		return day
+	}
+	return Day{Date: DayDate(n)}
+}
+
+// Days lists every day from the date of from through the date of to, empty days included
+func (c Calendar) Days(from time.Time, to time.Time) []Day {
+	first, last := DayNumber(from, c.loc), DayNumber(to, c.loc)
+	days := make([]Day, 0, max(last-first+1, 0))
+	for n := first; n <= last; n++ {
+		days = append(days, c.day(n))
+	}
+	return days
+}
+
+// Streaks finds the current and longest runs of practiced days. The current streak isn't broken
+// until a whole day goes by without practice, so it counts through yesterday if today hasn't been
+// practiced yet.
+func (c Calendar) Streaks(now time.Time) (current int, longest int) {
+	today := DayNumber(now, c.loc)
+	n := today
+	if !c.day(n).Practiced {
+		n--
+	}
+	for ; c.day(n).Practiced; n-- {
+		current++
+	}
+
+	for n := range c.days {
+		// only count from the start of each streak
+		if !c.days[n].Practiced || c.day(n-1).Practiced {
+			continue
+		}
+		length := 0
+		for c.day(n + int64(length)).Practiced {
+			length++
+		}
+		longest = max(longest, length)
+	}
+	return current, longest
+}
+
+// WeekStart is the most recent Sunday on or before now, in the user's timezone
+func (c Calendar) WeekStart(now time.Time) time.Time {
+	local := now.In(c.loc)
+	return local.AddDate(0, 0, -int(local.Weekday()))
+}
+
+// WeekMinutes is the time practiced since the start of this week
+func (c Calendar) WeekMinutes(now time.Time) int64 {
+	var minutes int64
+	for _, day := range c.Days(c.WeekStart(now), now) {
+		minutes += day.Minutes
+	}
+	return minutes
+}
+
+// WeekdayAverage is how practice went on one day of the week
+type WeekdayAverage struct {
+	Weekday time.Weekday
+	// Minutes is the average over every one of these days, practiced or not
+	Minutes   int64
+	Practiced int
+	Days      int
+}
+
+// WeekdayAverages sums up days by the day of the week, starting with Sunday
+func WeekdayAverages(days []Day) [7]WeekdayAverage {
+	var averages [7]WeekdayAverage
+	var totals [7]int64
+	for i := range averages {
+		averages[i].Weekday = time.Weekday(i)
+	}
+	for _, day := range days {
+		weekday := day.Date.Weekday()
+		totals[weekday] += day.Minutes
+		averages[weekday].Days++
+		if day.Practiced {
+			averages[weekday].Practiced++
+		}
+	}
+	for i := range averages {
+		if averages[i].Days > 0 {
+			averages[i].Minutes = totals[i] / int64(averages[i].Days)
+		}
+	}
+	return averages
+}
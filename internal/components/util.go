@@ -3,6 +3,7 @@ package components
 import (
 	"context"
 	"fmt"
+	"strings"
 )
 
 func HxCsrfHeader(csrf string) string {
@@ -63,7 +64,7 @@ func getReadingCardClass(completed bool) string {
 }
 
 func getSessionsFromIntensity(intensity string) string {
-	switch intensity {
+	switch strings.ToLower(intensity) {
 	case "light":
 		return "1"
 	case "medium":
@@ -20,16 +20,89 @@ const (
 	MEDIUM_SIGHT_READING = 2
 	HEAVY_SIGHT_READING  = 3
 
+	// plans can be resumed for the rest of the day they were made, or this long after, so practice
+	// that goes past midnight isn't cut off
 	RESUME_PLAN_TIME_LIMIT = 12 * time.Hour
-	INFREQUENT_SPOT_OFFSET = 12 * time.Hour
 
 	INTERLEAVE_SPOT_MIN_DAYS = 5
 	INTERLEAVE_SPOT_MAX_DAYS = 12
 
+	// new spots that have failed repeat practice this many times are pointed out when a plan is made
+	REPEAT_FAILURE_WARNING = 3
+
 	MAX_ALLOWED_RANDOM_SPOTS = 20
 	MAX_PDF_SPOTS_AT_ONCE    = 150
+	MAX_GENERATED_SPOTS      = 150
 	MAX_UPLOAD_SIZE          = 1024 * 1024 // 1MiB
 
+	// rows of an uploaded spreadsheet shown while picking its columns
+	CSV_PREVIEW_ROWS = 5
+
+	// longer than any real piece, anything past this is a mistake or a bad file
+	MAX_PIECE_MEASURES = 5000
+
+	// account archives hold every upload, so they can be much bigger
+	MAX_ARCHIVE_SIZE = 512 * 1024 * 1024 // 512MiB
+
+	// limits for pieces imported from a link, the size limit matches uploaded files
+	IMPORT_FETCH_TIMEOUT       = 10 * time.Second
+	IMPORT_FETCH_MAX_REDIRECTS = 5
+
+	// score files are much bigger than piece exports, and compressed ones grow a lot more when opened
+	MAX_SCORE_UPLOAD_SIZE = 10 * 1024 * 1024 // 10MiB
+	MAX_SCORE_SIZE        = 50 * 1024 * 1024 // 50MiB
+	MAX_SCORE_SPOTS       = 150
+
 	// 30 minutes of practicing plus a 3 minute break
 	TIME_BETWEEN_BREAKS = 33 * time.Minute
+
+	// plans with this intensity are filled up to a time budget instead of using a profile
+	TIME_BUDGET_INTENSITY = "time"
+	MIN_TIME_BUDGET       = 10
+	MAX_TIME_BUDGET       = 240
+
+	// starting estimates for time budgeted plans. the timed practice types, repeat, random spots and
+	// starting point, are replaced by practice history once there is enough
+	ESTIMATE_NEW_SPOT             = 5 * time.Minute
+	ESTIMATE_EXTRA_REPEAT_SPOT    = 2 * time.Minute
+	ESTIMATE_INTERLEAVE_SPOT      = 1 * time.Minute
+	ESTIMATE_INFREQUENT_SPOT      = 1 * time.Minute
+	ESTIMATE_RANDOM_SPOTS_PIECE   = 10 * time.Minute
+	ESTIMATE_STARTING_POINT_PIECE = 10 * time.Minute
+	ESTIMATE_SCALE                = 5 * time.Minute
+	ESTIMATE_READING              = 5 * time.Minute
+	ESTIMATE_MIN_SESSIONS         = 3
+	ESTIMATE_HISTORY_WINDOW       = 60 * 24 * time.Hour
+
+	// measures drilled with random starting points more recently than this are shown as fresh on the
+	// piece page, the rest are neglected. Only the last few sessions are listed, and sessions older than
+	// the history window aren't counted at all.
+	STARTING_POINT_RECENT_DAYS  = 7
+	STARTING_POINT_HISTORY_DAYS = 60
+	MAX_STARTING_POINT_SESSIONS = 5
+
+	// tempo ladder defaults, the step and fallback can be changed on the ladder page. Runs since the
+	// session window started count toward the current climb, and spots without a tempo start slow.
+	TEMPO_LADDER_STEP           = 4
+	TEMPO_LADDER_FALLBACK_STEPS = 2
+	TEMPO_LADDER_START          = 60
+	TEMPO_LADDER_SESSION        = 3 * time.Hour
+	MAX_TEMPO_CHART_POINTS      = 50
+
+	// the step size and fallback the ladder page accepts
+	TEMPO_LADDER_MIN_STEP           = 1
+	TEMPO_LADDER_MAX_STEP           = 40
+	TEMPO_LADDER_MIN_FALLBACK_STEPS = 0
+	TEMPO_LADDER_MAX_FALLBACK_STEPS = 10
+
+	// weeks of practice shown on the stats calendar
+	STATS_CALENDAR_WEEKS = 52
+
+	// the readiness forecast projects from how fast spots moved through the stages over the last few
+	// weeks, and the chart shows about the last few months
+	READINESS_RATE_DAYS  = 28
+	READINESS_CHART_DAYS = 90
+
+	// results shown for each kind of item in a library search
+	SEARCH_RESULT_LIMIT = 10
 )
@@ -18,6 +18,21 @@ type Credential struct {
 	UserID          string `json:"userId"`
 }
 
+type DataMigration struct {
+	Name    string `json:"name"`
+	Applied int64  `json:"applied"`
+}
+
+type IntensityProfile struct {
+	ID                 string `json:"id"`
+	UserID             string `json:"userId"`
+	Name               string `json:"name"`
+	MaxNewSpots        int64  `json:"maxNewSpots"`
+	MaxInterleaveSpots int64  `json:"maxInterleaveSpots"`
+	MaxInfrequentSpots int64  `json:"maxInfrequentSpots"`
+	MaxSightReading    int64  `json:"maxSightReading"`
+}
+
 type Piece struct {
 	ID              string         `json:"id"`
 	Title           string         `json:"title"`
@@ -75,6 +90,19 @@ type PracticePlanSpot struct {
 	Idx            int64          `json:"idx"`
 }
 
+type PracticeSession struct {
+	ID              string         `json:"id"`
+	UserID          string         `json:"userId"`
+	PracticeType    string         `json:"practiceType"`
+	DurationMinutes int64          `json:"durationMinutes"`
+	Date            int64          `json:"date"`
+	SpotID          sql.NullString `json:"spotId"`
+	PieceID         sql.NullString `json:"pieceId"`
+	UserScaleID     sql.NullString `json:"userScaleId"`
+	ReadingID       sql.NullString `json:"readingId"`
+	PracticePlanID  sql.NullString `json:"practicePlanId"`
+}
+
 type Reading struct {
 	ID        string         `json:"id"`
 	Title     string         `json:"title"`
@@ -104,10 +132,12 @@ type ScaleMode struct {
 }
 
 type Section struct {
-	ID          string `json:"id"`
-	Name        string `json:"name"`
-	Description string `json:"description"`
-	PieceID     string `json:"pieceId"`
+	ID           string        `json:"id"`
+	Name         string        `json:"name"`
+	Description  string        `json:"description"`
+	PieceID      string        `json:"pieceId"`
+	StartMeasure sql.NullInt64 `json:"startMeasure"`
+	EndMeasure   sql.NullInt64 `json:"endMeasure"`
 }
 
 type Spot struct {
@@ -126,6 +156,35 @@ type Spot struct {
 	SkipDays       int64          `json:"skipDays"`
 	Priority       int64          `json:"priority"`
 	SectionID      sql.NullString `json:"sectionId"`
+	MeasuresStart  sql.NullInt64  `json:"measuresStart"`
+	MeasuresEnd    sql.NullInt64  `json:"measuresEnd"`
+}
+
+type SpotEvent struct {
+	ID             string         `json:"id"`
+	SpotID         string         `json:"spotId"`
+	UserID         string         `json:"userId"`
+	EventType      string         `json:"eventType"`
+	PracticeType   sql.NullString `json:"practiceType"`
+	Evaluation     sql.NullString `json:"evaluation"`
+	Success        sql.NullBool   `json:"success"`
+	FromStage      sql.NullString `json:"fromStage"`
+	ToStage        sql.NullString `json:"toStage"`
+	PracticePlanID sql.NullString `json:"practicePlanId"`
+	Date           int64          `json:"date"`
+	Attempts       sql.NullInt64  `json:"attempts"`
+	Successes      sql.NullInt64  `json:"successes"`
+	Resets         sql.NullInt64  `json:"resets"`
+}
+
+type SpotTempo struct {
+	ID      string       `json:"id"`
+	SpotID  string       `json:"spotId"`
+	UserID  string       `json:"userId"`
+	Tempo   int64        `json:"tempo"`
+	Source  string       `json:"source"`
+	Success sql.NullBool `json:"success"`
+	Date    int64        `json:"date"`
 }
 
 type SpotsSection struct {
@@ -134,6 +193,32 @@ type SpotsSection struct {
 	PieceID   string `json:"pieceId"`
 }
 
+type StageRule struct {
+	UserID                     string `json:"userId"`
+	RandomPromoteExcellent     int64  `json:"randomPromoteExcellent"`
+	RandomPromoteMinDays       int64  `json:"randomPromoteMinDays"`
+	RandomDemotePoor           int64  `json:"randomDemotePoor"`
+	RandomDemoteStaleDays      int64  `json:"randomDemoteStaleDays"`
+	RandomDemoteTo             string `json:"randomDemoteTo"`
+	InterleavePromoteExcellent int64  `json:"interleavePromoteExcellent"`
+	InterleavePromoteMinDays   int64  `json:"interleavePromoteMinDays"`
+	InterleaveStaleDays        int64  `json:"interleaveStaleDays"`
+	InterleaveDemoteTo         string `json:"interleaveDemoteTo"`
+	InfrequentDemoteTo         string `json:"infrequentDemoteTo"`
+}
+
+type StartingPointSession struct {
+	ID                string         `json:"id"`
+	UserID            string         `json:"userId"`
+	PieceID           string         `json:"pieceId"`
+	Date              int64          `json:"date"`
+	MeasuresPracticed string         `json:"measuresPracticed"`
+	Rating            sql.NullString `json:"rating"`
+	TroubleMeasures   string         `json:"troubleMeasures"`
+	TroubleConverted  bool           `json:"troubleConverted"`
+	PracticePlanID    sql.NullString `json:"practicePlanId"`
+}
+
 type User struct {
 	ID                         string         `json:"id"`
 	Fullname                   string         `json:"fullname"`
@@ -143,6 +228,9 @@ type User struct {
 	ActivePracticePlanStarted  sql.NullInt64  `json:"activePracticePlanStarted"`
 	ConfigDefaultPlanIntensity string         `json:"configDefaultPlanIntensity"`
 	ConfigTimeBetweenBreaks    int64          `json:"configTimeBetweenBreaks"`
+	ConfigDefaultTimeBudget    int64          `json:"configDefaultTimeBudget"`
+	ConfigScheduler            string         `json:"configScheduler"`
+	ConfigTimezone             string         `json:"configTimezone"`
 }
 
 type UserScale struct {
@@ -129,7 +129,9 @@ SELECT
     spots.text_prompt AS spot_text_prompt,
     spots.current_tempo AS spot_current_tempo,
     spots.measures AS spot_measures,
-    spots.last_practiced AS spot_last_practiced
+    spots.last_practiced AS spot_last_practiced,
+    spots.measures_start AS spot_measures_start,
+    spots.measures_end AS spot_measures_end
 FROM pieces
 LEFT JOIN spots ON pieces.id = spots.piece_id
 LEFT JOIN scale_keys ON pieces.key_id = scale_keys.id
@@ -165,6 +167,8 @@ type GetPieceByIDRow struct {
 	SpotCurrentTempo   sql.NullInt64  `json:"spotCurrentTempo"`
 	SpotMeasures       sql.NullString `json:"spotMeasures"`
 	SpotLastPracticed  sql.NullInt64  `json:"spotLastPracticed"`
+	SpotMeasuresStart  sql.NullInt64  `json:"spotMeasuresStart"`
+	SpotMeasuresEnd    sql.NullInt64  `json:"spotMeasuresEnd"`
 }
 
 func (q *Queries) GetPieceByID(ctx context.Context, arg GetPieceByIDParams) ([]GetPieceByIDRow, error) {
@@ -198,6 +202,8 @@ func (q *Queries) GetPieceByID(ctx context.Context, arg GetPieceByIDParams) ([]G
 			&i.SpotCurrentTempo,
 			&i.SpotMeasures,
 			&i.SpotLastPracticed,
+			&i.SpotMeasuresStart,
+			&i.SpotMeasuresEnd,
 		); err != nil {
 			return nil, err
 		}
@@ -226,7 +232,8 @@ SELECT
     spots.name AS spot_name,
     spots.stage AS spot_stage,
     spots.last_practiced AS spot_last_practiced,
-    spots.skip_days AS spot_skip_days
+    spots.skip_days AS spot_skip_days,
+    spots.priority AS spot_priority
 FROM pieces
 LEFT JOIN spots ON pieces.id = spots.piece_id
 WHERE pieces.id = ?1 AND pieces.user_id = ?2
@@ -251,6 +258,7 @@ type GetPieceForPlanRow struct {
 	SpotStage         sql.NullString `json:"spotStage"`
 	SpotLastPracticed sql.NullInt64  `json:"spotLastPracticed"`
 	SpotSkipDays      sql.NullInt64  `json:"spotSkipDays"`
+	SpotPriority      sql.NullInt64  `json:"spotPriority"`
 }
 
 func (q *Queries) GetPieceForPlan(ctx context.Context, arg GetPieceForPlanParams) ([]GetPieceForPlanRow, error) {
@@ -276,6 +284,7 @@ func (q *Queries) GetPieceForPlan(ctx context.Context, arg GetPieceForPlanParams
 			&i.SpotStage,
 			&i.SpotLastPracticed,
 			&i.SpotSkipDays,
+			&i.SpotPriority,
 		); err != nil {
 			return nil, err
 		}
@@ -493,7 +493,7 @@ func (q *Queries) GetMaxSpotIdx(ctx context.Context, arg GetMaxSpotIdxParams) (i
 }
 
 const getNextInfrequentSpot = `-- name: GetNextInfrequentSpot :one
-SELECT spots.id, spots.piece_id, spots.name, spots.stage, spots.measures, spots.audio_prompt_url, spots.image_prompt_url, spots.notes_prompt, spots.text_prompt, spots.current_tempo, spots.last_practiced, spots.stage_started, spots.skip_days, spots.priority, spots.section_id,
+SELECT spots.id, spots.piece_id, spots.name, spots.stage, spots.measures, spots.audio_prompt_url, spots.image_prompt_url, spots.notes_prompt, spots.text_prompt, spots.current_tempo, spots.last_practiced, spots.stage_started, spots.skip_days, spots.priority, spots.section_id, spots.measures_start, spots.measures_end,
     (SELECT pieces.title FROM pieces WHERE pieces.id = spots.piece_id LIMIT 1) AS piece_title
 FROM practice_plan_spots
 INNER JOIN spots ON practice_plan_spots.spot_id = spots.id
@@ -525,6 +525,8 @@ type GetNextInfrequentSpotRow struct {
 	SkipDays       int64          `json:"skipDays"`
 	Priority       int64          `json:"priority"`
 	SectionID      sql.NullString `json:"sectionId"`
+	MeasuresStart  sql.NullInt64  `json:"measuresStart"`
+	MeasuresEnd    sql.NullInt64  `json:"measuresEnd"`
 	PieceTitle     string         `json:"pieceTitle"`
 }
 
@@ -547,6 +549,8 @@ func (q *Queries) GetNextInfrequentSpot(ctx context.Context, arg GetNextInfreque
 		&i.SkipDays,
 		&i.Priority,
 		&i.SectionID,
+		&i.MeasuresStart,
+		&i.MeasuresEnd,
 		&i.PieceTitle,
 	)
 	return i, err
@@ -655,7 +659,11 @@ func (q *Queries) GetPracticePlanEvaluatedInterleaveSpots(ctx context.Context, a
 }
 
 const getPracticePlanFailedNewSpots = `-- name: GetPracticePlanFailedNewSpots :many
-SELECT practice_plan_spots.practice_plan_id, practice_plan_spots.spot_id, practice_plan_spots.practice_type, practice_plan_spots.evaluation, practice_plan_spots.completed, practice_plan_spots.idx
+SELECT practice_plan_spots.practice_plan_id, practice_plan_spots.spot_id, practice_plan_spots.practice_type, practice_plan_spots.evaluation, practice_plan_spots.completed, practice_plan_spots.idx,
+    spots.name AS spot_name,
+    (SELECT COUNT(spot_events.id) FROM spot_events WHERE spot_events.spot_id = spots.id AND spot_events.event_type = 'repeat' AND spot_events.success = 0) AS repeat_failures,
+    CAST(COALESCE((SELECT SUM(spot_events.attempts) FROM spot_events WHERE spot_events.spot_id = spots.id AND spot_events.event_type = 'repeat'), 0) AS INTEGER) AS repeat_attempts,
+    CAST(COALESCE((SELECT SUM(spot_events.resets) FROM spot_events WHERE spot_events.spot_id = spots.id AND spot_events.event_type = 'repeat'), 0) AS INTEGER) AS repeat_resets
 FROM practice_plan_spots
 INNER JOIN spots ON practice_plan_spots.spot_id = spots.id
 WHERE practice_plan_spots.practice_type = 'new'
@@ -670,7 +678,20 @@ type GetPracticePlanFailedNewSpotsParams struct {
 	PieceIDs []string `json:"pieceIDs"`
 }
 
-func (q *Queries) GetPracticePlanFailedNewSpots(ctx context.Context, arg GetPracticePlanFailedNewSpotsParams) ([]PracticePlanSpot, error) {
+type GetPracticePlanFailedNewSpotsRow struct {
+	PracticePlanID string         `json:"practicePlanId"`
+	SpotID         string         `json:"spotId"`
+	PracticeType   string         `json:"practiceType"`
+	Evaluation     sql.NullString `json:"evaluation"`
+	Completed      bool           `json:"completed"`
+	Idx            int64          `json:"idx"`
+	SpotName       string         `json:"spotName"`
+	RepeatFailures int64          `json:"repeatFailures"`
+	RepeatAttempts int64          `json:"repeatAttempts"`
+	RepeatResets   int64          `json:"repeatResets"`
+}
+
+func (q *Queries) GetPracticePlanFailedNewSpots(ctx context.Context, arg GetPracticePlanFailedNewSpotsParams) ([]GetPracticePlanFailedNewSpotsRow, error) {
 	query := getPracticePlanFailedNewSpots
 	var queryParams []interface{}
 	queryParams = append(queryParams, arg.UserID)
@@ -687,9 +708,9 @@ func (q *Queries) GetPracticePlanFailedNewSpots(ctx context.Context, arg GetPrac
 		return nil, err
 	}
 	defer rows.Close()
-	var items []PracticePlanSpot
+	var items []GetPracticePlanFailedNewSpotsRow
 	for rows.Next() {
-		var i PracticePlanSpot
+		var i GetPracticePlanFailedNewSpotsRow
 		if err := rows.Scan(
 			&i.PracticePlanID,
 			&i.SpotID,
@@ -697,6 +718,10 @@ func (q *Queries) GetPracticePlanFailedNewSpots(ctx context.Context, arg GetPrac
 			&i.Evaluation,
 			&i.Completed,
 			&i.Idx,
+			&i.SpotName,
+			&i.RepeatFailures,
+			&i.RepeatAttempts,
+			&i.RepeatResets,
 		); err != nil {
 			return nil, err
 		}
@@ -1572,6 +1597,7 @@ SELECT
     spots.stage AS spot_stage,
     spots.skip_days AS spot_skip_days,
     spots.stage_started AS spot_stage_started,
+    spots.priority AS spot_priority,
     (SELECT pieces.title FROM pieces WHERE pieces.id = spots.piece_id LIMIT 1) AS spot_piece_title
 FROM practice_plans
 INNER JOIN practice_plan_spots ON practice_plans.id = practice_plan_spots.practice_plan_id
@@ -1602,6 +1628,7 @@ type GetPracticePlanWithSpotsRow struct {
 	SpotStage        sql.NullString `json:"spotStage"`
 	SpotSkipDays     sql.NullInt64  `json:"spotSkipDays"`
 	SpotStageStarted sql.NullInt64  `json:"spotStageStarted"`
+	SpotPriority     sql.NullInt64  `json:"spotPriority"`
 	SpotPieceTitle   string         `json:"spotPieceTitle"`
 }
 
@@ -1631,6 +1658,7 @@ func (q *Queries) GetPracticePlanWithSpots(ctx context.Context, arg GetPracticeP
 			&i.SpotStage,
 			&i.SpotSkipDays,
 			&i.SpotStageStarted,
+			&i.SpotPriority,
 			&i.SpotPieceTitle,
 		); err != nil {
 			return nil, err
@@ -11,6 +11,33 @@ import (
 	"strings"
 )
 
+const changeSpotStage = `-- name: ChangeSpotStage :exec
+UPDATE spots
+SET
+    stage = CASE WHEN stage = ?1 THEN ?2 ELSE stage END,
+    stage_started = CASE WHEN stage = ?1 THEN unixepoch('now') ELSE stage_started END,
+    skip_days = CASE WHEN stage = ?1 THEN 1 ELSE skip_days END,
+    last_practiced = unixepoch('now')
+WHERE spots.id = ?3 AND piece_id IN (SELECT pieces.id FROM pieces WHERE pieces.user_id = ?4)
+`
+
+type ChangeSpotStageParams struct {
+	FromStage string `json:"fromStage"`
+	ToStage   string `json:"toStage"`
+	SpotID    string `json:"spotId"`
+	UserID    string `json:"userId"`
+}
+
+func (q *Queries) ChangeSpotStage(ctx context.Context, arg ChangeSpotStageParams) error {
+	_, err := q.db.ExecContext(ctx, changeSpotStage,
+		arg.FromStage,
+		arg.ToStage,
+		arg.SpotID,
+		arg.UserID,
+	)
+	return err
+}
+
 const createSpot = `-- name: CreateSpot :one
 INSERT INTO spots (
     piece_id,
@@ -23,13 +50,15 @@ INSERT INTO spots (
     text_prompt,
     current_tempo,
     measures,
+    measures_start,
+    measures_end,
     stage_started
 ) VALUES (
     (SELECT pieces.id FROM pieces WHERE pieces.user_id = ? AND pieces.id = ? LIMIT 1),
-    ?, ?, ?, ?, ?, ?, ?, ?, ?,
+    ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?,
     unixepoch('now')
 )
-RETURNING id, piece_id, name, stage, measures, audio_prompt_url, image_prompt_url, notes_prompt, text_prompt, current_tempo, last_practiced, stage_started, skip_days, priority, section_id
+RETURNING id, piece_id, name, stage, measures, audio_prompt_url, image_prompt_url, notes_prompt, text_prompt, current_tempo, last_practiced, stage_started, skip_days, priority, section_id, measures_start, measures_end
 `
 
 type CreateSpotParams struct {
@@ -44,6 +73,8 @@ type CreateSpotParams struct {
 	TextPrompt     string         `json:"textPrompt"`
 	CurrentTempo   sql.NullInt64  `json:"currentTempo"`
 	Measures       sql.NullString `json:"measures"`
+	MeasuresStart  sql.NullInt64  `json:"measuresStart"`
+	MeasuresEnd    sql.NullInt64  `json:"measuresEnd"`
 }
 
 func (q *Queries) CreateSpot(ctx context.Context, arg CreateSpotParams) (Spot, error) {
@@ -59,6 +90,8 @@ func (q *Queries) CreateSpot(ctx context.Context, arg CreateSpotParams) (Spot, e
 		arg.TextPrompt,
 		arg.CurrentTempo,
 		arg.Measures,
+		arg.MeasuresStart,
+		arg.MeasuresEnd,
 	)
 	var i Spot
 	err := row.Scan(
@@ -77,6 +110,8 @@ func (q *Queries) CreateSpot(ctx context.Context, arg CreateSpotParams) (Spot, e
 		&i.SkipDays,
 		&i.Priority,
 		&i.SectionID,
+		&i.MeasuresStart,
+		&i.MeasuresEnd,
 	)
 	return i, err
 }
@@ -127,65 +162,6 @@ func (q *Queries) DeleteSpotsExcept(ctx context.Context, arg DeleteSpotsExceptPa
 	return err
 }
 
-const demoteSpotToExtraRepeat = `-- name: DemoteSpotToExtraRepeat :exec
-UPDATE spots
-SET
-    stage = CASE WHEN stage != 'extra_repeat' AND stage != 'repeat' THEN 'extra_repeat' ELSE stage END,
-    stage_started = CASE WHEN stage != 'extra_repeat' AND stage != 'repeat' THEN unixepoch('now') ELSE stage_started END,
-    last_practiced = unixepoch('now')
-WHERE spots.id = ?1 AND piece_id = (SELECT pieces.id FROM pieces WHERE pieces.user_id = ?2 AND pieces.id = ?3 LIMIT 1)
-`
-
-type DemoteSpotToExtraRepeatParams struct {
-	SpotID  string `json:"spotId"`
-	UserID  string `json:"userId"`
-	PieceID string `json:"pieceId"`
-}
-
-func (q *Queries) DemoteSpotToExtraRepeat(ctx context.Context, arg DemoteSpotToExtraRepeatParams) error {
-	_, err := q.db.ExecContext(ctx, demoteSpotToExtraRepeat, arg.SpotID, arg.UserID, arg.PieceID)
-	return err
-}
-
-const demoteSpotToInterleave = `-- name: DemoteSpotToInterleave :exec
-UPDATE spots
-SET
-    stage = CASE WHEN stage = 'interleave_days' THEN 'interleave' ELSE stage END,
-    stage_started = CASE WHEN stage = 'interleave_days' THEN unixepoch('now') ELSE stage_started END,
-    skip_days = 1,
-    last_practiced = unixepoch('now')
-WHERE spots.id = ?1 AND piece_id IN (SELECT pieces.id FROM pieces WHERE pieces.user_id = ?2)
-`
-
-type DemoteSpotToInterleaveParams struct {
-	SpotID string `json:"spotId"`
-	UserID string `json:"userId"`
-}
-
-func (q *Queries) DemoteSpotToInterleave(ctx context.Context, arg DemoteSpotToInterleaveParams) error {
-	_, err := q.db.ExecContext(ctx, demoteSpotToInterleave, arg.SpotID, arg.UserID)
-	return err
-}
-
-const demoteSpotToRandom = `-- name: DemoteSpotToRandom :exec
-UPDATE spots
-SET
-    stage = CASE WHEN stage = 'interleave' THEN 'random' ELSE stage END,
-    stage_started = CASE WHEN stage = 'interleave' THEN unixepoch('now') ELSE stage_started END,
-    last_practiced = unixepoch('now')
-WHERE spots.id = ?1 AND piece_id IN (SELECT pieces.id FROM pieces WHERE pieces.user_id = ?2)
-`
-
-type DemoteSpotToRandomParams struct {
-	SpotID string `json:"spotId"`
-	UserID string `json:"userId"`
-}
-
-func (q *Queries) DemoteSpotToRandom(ctx context.Context, arg DemoteSpotToRandomParams) error {
-	_, err := q.db.ExecContext(ctx, demoteSpotToRandom, arg.SpotID, arg.UserID)
-	return err
-}
-
 const fixSpotStageStarted = `-- name: FixSpotStageStarted :exec
 UPDATE spots
 SET
@@ -205,8 +181,9 @@ func (q *Queries) FixSpotStageStarted(ctx context.Context, arg FixSpotStageStart
 
 const getSpot = `-- name: GetSpot :one
 SELECT
-    spots.id, spots.piece_id, spots.name, spots.stage, spots.measures, spots.audio_prompt_url, spots.image_prompt_url, spots.notes_prompt, spots.text_prompt, spots.current_tempo, spots.last_practiced, spots.stage_started, spots.skip_days, spots.priority, spots.section_id,
-    pieces.title AS piece_title
+    spots.id, spots.piece_id, spots.name, spots.stage, spots.measures, spots.audio_prompt_url, spots.image_prompt_url, spots.notes_prompt, spots.text_prompt, spots.current_tempo, spots.last_practiced, spots.stage_started, spots.skip_days, spots.priority, spots.section_id, spots.measures_start, spots.measures_end,
+    pieces.title AS piece_title,
+    pieces.goal_tempo AS piece_goal_tempo
 FROM spots
 INNER JOIN pieces ON pieces.id = spots.piece_id
 WHERE spots.id = ?1 AND spots.piece_id = (SELECT pieces.id FROM pieces WHERE pieces.user_id = ?2 AND pieces.id = ?3 LIMIT 1)
@@ -234,7 +211,10 @@ type GetSpotRow struct {
 	SkipDays       int64          `json:"skipDays"`
 	Priority       int64          `json:"priority"`
 	SectionID      sql.NullString `json:"sectionId"`
+	MeasuresStart  sql.NullInt64  `json:"measuresStart"`
+	MeasuresEnd    sql.NullInt64  `json:"measuresEnd"`
 	PieceTitle     string         `json:"pieceTitle"`
+	PieceGoalTempo sql.NullInt64  `json:"pieceGoalTempo"`
 }
 
 func (q *Queries) GetSpot(ctx context.Context, arg GetSpotParams) (GetSpotRow, error) {
@@ -256,7 +236,10 @@ func (q *Queries) GetSpot(ctx context.Context, arg GetSpotParams) (GetSpotRow, e
 		&i.SkipDays,
 		&i.Priority,
 		&i.SectionID,
+		&i.MeasuresStart,
+		&i.MeasuresEnd,
 		&i.PieceTitle,
+		&i.PieceGoalTempo,
 	)
 	return i, err
 }
@@ -290,7 +273,7 @@ func (q *Queries) GetSpotStageStarted(ctx context.Context, arg GetSpotStageStart
 
 const listHighPrioritySpots = `-- name: ListHighPrioritySpots :many
 SELECT
-    spots.id, spots.piece_id, spots.name, spots.stage, spots.measures, spots.audio_prompt_url, spots.image_prompt_url, spots.notes_prompt, spots.text_prompt, spots.current_tempo, spots.last_practiced, spots.stage_started, spots.skip_days, spots.priority, spots.section_id,
+    spots.id, spots.piece_id, spots.name, spots.stage, spots.measures, spots.audio_prompt_url, spots.image_prompt_url, spots.notes_prompt, spots.text_prompt, spots.current_tempo, spots.last_practiced, spots.stage_started, spots.skip_days, spots.priority, spots.section_id, spots.measures_start, spots.measures_end,
     pieces.title AS piece_title
 FROM spots
 INNER JOIN pieces ON pieces.id = spots.piece_id
@@ -314,6 +297,8 @@ type ListHighPrioritySpotsRow struct {
 	SkipDays       int64          `json:"skipDays"`
 	Priority       int64          `json:"priority"`
 	SectionID      sql.NullString `json:"sectionId"`
+	MeasuresStart  sql.NullInt64  `json:"measuresStart"`
+	MeasuresEnd    sql.NullInt64  `json:"measuresEnd"`
 	PieceTitle     string         `json:"pieceTitle"`
 }
 
@@ -342,6 +327,8 @@ func (q *Queries) ListHighPrioritySpots(ctx context.Context, userID string) ([]L
 			&i.SkipDays,
 			&i.Priority,
 			&i.SectionID,
+			&i.MeasuresStart,
+			&i.MeasuresEnd,
 			&i.PieceTitle,
 		); err != nil {
 			return nil, err
@@ -359,7 +346,7 @@ func (q *Queries) ListHighPrioritySpots(ctx context.Context, userID string) ([]L
 
 const listPieceSpots = `-- name: ListPieceSpots :many
 SELECT
-    spots.id, spots.piece_id, spots.name, spots.stage, spots.measures, spots.audio_prompt_url, spots.image_prompt_url, spots.notes_prompt, spots.text_prompt, spots.current_tempo, spots.last_practiced, spots.stage_started, spots.skip_days, spots.priority, spots.section_id,
+    spots.id, spots.piece_id, spots.name, spots.stage, spots.measures, spots.audio_prompt_url, spots.image_prompt_url, spots.notes_prompt, spots.text_prompt, spots.current_tempo, spots.last_practiced, spots.stage_started, spots.skip_days, spots.priority, spots.section_id, spots.measures_start, spots.measures_end,
     pieces.title AS piece_title
 FROM spots
 INNER JOIN pieces ON pieces.id = spots.piece_id
@@ -388,6 +375,8 @@ type ListPieceSpotsRow struct {
 	SkipDays       int64          `json:"skipDays"`
 	Priority       int64          `json:"priority"`
 	SectionID      sql.NullString `json:"sectionId"`
+	MeasuresStart  sql.NullInt64  `json:"measuresStart"`
+	MeasuresEnd    sql.NullInt64  `json:"measuresEnd"`
 	PieceTitle     string         `json:"pieceTitle"`
 }
 
@@ -416,6 +405,8 @@ func (q *Queries) ListPieceSpots(ctx context.Context, arg ListPieceSpotsParams)
 			&i.SkipDays,
 			&i.Priority,
 			&i.SectionID,
+			&i.MeasuresStart,
+			&i.MeasuresEnd,
 			&i.PieceTitle,
 		); err != nil {
 			return nil, err
@@ -433,7 +424,7 @@ func (q *Queries) ListPieceSpots(ctx context.Context, arg ListPieceSpotsParams)
 
 const listPieceSpotsInStage = `-- name: ListPieceSpotsInStage :many
 SELECT
-    spots.id, spots.piece_id, spots.name, spots.stage, spots.measures, spots.audio_prompt_url, spots.image_prompt_url, spots.notes_prompt, spots.text_prompt, spots.current_tempo, spots.last_practiced, spots.stage_started, spots.skip_days, spots.priority, spots.section_id,
+    spots.id, spots.piece_id, spots.name, spots.stage, spots.measures, spots.audio_prompt_url, spots.image_prompt_url, spots.notes_prompt, spots.text_prompt, spots.current_tempo, spots.last_practiced, spots.stage_started, spots.skip_days, spots.priority, spots.section_id, spots.measures_start, spots.measures_end,
     pieces.title AS piece_title
 FROM spots
 INNER JOIN pieces ON pieces.id = spots.piece_id
@@ -464,6 +455,8 @@ type ListPieceSpotsInStageRow struct {
 	SkipDays       int64          `json:"skipDays"`
 	Priority       int64          `json:"priority"`
 	SectionID      sql.NullString `json:"sectionId"`
+	MeasuresStart  sql.NullInt64  `json:"measuresStart"`
+	MeasuresEnd    sql.NullInt64  `json:"measuresEnd"`
 	PieceTitle     string         `json:"pieceTitle"`
 }
 
@@ -492,6 +485,8 @@ func (q *Queries) ListPieceSpotsInStage(ctx context.Context, arg ListPieceSpotsI
 			&i.SkipDays,
 			&i.Priority,
 			&i.SectionID,
+			&i.MeasuresStart,
+			&i.MeasuresEnd,
 			&i.PieceTitle,
 		); err != nil {
 			return nil, err
@@ -509,7 +504,7 @@ func (q *Queries) ListPieceSpotsInStage(ctx context.Context, arg ListPieceSpotsI
 
 const listPieceSpotsInStageForPlan = `-- name: ListPieceSpotsInStageForPlan :many
 SELECT
-    spots.id, spots.piece_id, spots.name, spots.stage, spots.measures, spots.audio_prompt_url, spots.image_prompt_url, spots.notes_prompt, spots.text_prompt, spots.current_tempo, spots.last_practiced, spots.stage_started, spots.skip_days, spots.priority, spots.section_id,
+    spots.id, spots.piece_id, spots.name, spots.stage, spots.measures, spots.audio_prompt_url, spots.image_prompt_url, spots.notes_prompt, spots.text_prompt, spots.current_tempo, spots.last_practiced, spots.stage_started, spots.skip_days, spots.priority, spots.section_id, spots.measures_start, spots.measures_end,
     pieces.title AS piece_title
 FROM spots
 INNER JOIN pieces ON pieces.id = spots.piece_id
@@ -542,6 +537,8 @@ type ListPieceSpotsInStageForPlanRow struct {
 	SkipDays       int64          `json:"skipDays"`
 	Priority       int64          `json:"priority"`
 	SectionID      sql.NullString `json:"sectionId"`
+	MeasuresStart  sql.NullInt64  `json:"measuresStart"`
+	MeasuresEnd    sql.NullInt64  `json:"measuresEnd"`
 	PieceTitle     string         `json:"pieceTitle"`
 }
 
@@ -575,6 +572,8 @@ func (q *Queries) ListPieceSpotsInStageForPlan(ctx context.Context, arg ListPiec
 			&i.SkipDays,
 			&i.Priority,
 			&i.SectionID,
+			&i.MeasuresStart,
+			&i.MeasuresEnd,
 			&i.PieceTitle,
 		); err != nil {
 			return nil, err
@@ -592,7 +591,7 @@ func (q *Queries) ListPieceSpotsInStageForPlan(ctx context.Context, arg ListPiec
 
 const listSpotsForPlanStage = `-- name: ListSpotsForPlanStage :many
 SELECT
-    spots.id, spots.piece_id, spots.name, spots.stage, spots.measures, spots.audio_prompt_url, spots.image_prompt_url, spots.notes_prompt, spots.text_prompt, spots.current_tempo, spots.last_practiced, spots.stage_started, spots.skip_days, spots.priority, spots.section_id,
+    spots.id, spots.piece_id, spots.name, spots.stage, spots.measures, spots.audio_prompt_url, spots.image_prompt_url, spots.notes_prompt, spots.text_prompt, spots.current_tempo, spots.last_practiced, spots.stage_started, spots.skip_days, spots.priority, spots.section_id, spots.measures_start, spots.measures_end,
     pieces.title AS piece_title
 FROM spots
 INNER JOIN pieces on pieces.id = spots.piece_id
@@ -623,6 +622,8 @@ type ListSpotsForPlanStageRow struct {
 	SkipDays       int64          `json:"skipDays"`
 	Priority       int64          `json:"priority"`
 	SectionID      sql.NullString `json:"sectionId"`
+	MeasuresStart  sql.NullInt64  `json:"measuresStart"`
+	MeasuresEnd    sql.NullInt64  `json:"measuresEnd"`
 	PieceTitle     string         `json:"pieceTitle"`
 }
 
@@ -651,6 +652,8 @@ func (q *Queries) ListSpotsForPlanStage(ctx context.Context, arg ListSpotsForPla
 			&i.SkipDays,
 			&i.Priority,
 			&i.SectionID,
+			&i.MeasuresStart,
+			&i.MeasuresEnd,
 			&i.PieceTitle,
 		); err != nil {
 			return nil, err
@@ -666,122 +669,62 @@ func (q *Queries) ListSpotsForPlanStage(ctx context.Context, arg ListSpotsForPla
 	return items, nil
 }
 
-const promoteSpotToCompleted = `-- name: PromoteSpotToCompleted :one
-UPDATE spots
-SET
-    stage = 'completed',
-    stage_started = unixepoch('now'),
-    skip_days = 1,
-    last_practiced = unixepoch('now')
-WHERE spots.id = ?1 AND piece_id IN (SELECT pieces.id FROM pieces WHERE pieces.user_id = ?2)
-RETURNING id, piece_id, name, stage, measures, audio_prompt_url, image_prompt_url, notes_prompt, text_prompt, current_tempo, last_practiced, stage_started, skip_days, priority, section_id
-`
-
-type PromoteSpotToCompletedParams struct {
-	SpotID string `json:"spotId"`
-	UserID string `json:"userId"`
-}
-
-func (q *Queries) PromoteSpotToCompleted(ctx context.Context, arg PromoteSpotToCompletedParams) (Spot, error) {
-	row := q.db.QueryRowContext(ctx, promoteSpotToCompleted, arg.SpotID, arg.UserID)
-	var i Spot
-	err := row.Scan(
-		&i.ID,
-		&i.PieceID,
-		&i.Name,
-		&i.Stage,
-		&i.Measures,
-		&i.AudioPromptUrl,
-		&i.ImagePromptUrl,
-		&i.NotesPrompt,
-		&i.TextPrompt,
-		&i.CurrentTempo,
-		&i.LastPracticed,
-		&i.StageStarted,
-		&i.SkipDays,
-		&i.Priority,
-		&i.SectionID,
-	)
-	return i, err
-}
-
-const promoteSpotToExtraRepeat = `-- name: PromoteSpotToExtraRepeat :exec
-UPDATE spots
-SET
-    stage = CASE WHEN stage = 'repeat' THEN 'extra_repeat' ELSE stage END,
-    stage_started = CASE WHEN stage = 'repeat' THEN unixepoch('now') ELSE stage_started END,
-    last_practiced = unixepoch('now')
-WHERE spots.id = ?1 AND piece_id = (SELECT pieces.id FROM pieces WHERE pieces.user_id = ?2 AND pieces.id = ?3 LIMIT 1)
-`
-
-type PromoteSpotToExtraRepeatParams struct {
-	SpotID  string `json:"spotId"`
-	UserID  string `json:"userId"`
-	PieceID string `json:"pieceId"`
-}
-
-func (q *Queries) PromoteSpotToExtraRepeat(ctx context.Context, arg PromoteSpotToExtraRepeatParams) error {
-	_, err := q.db.ExecContext(ctx, promoteSpotToExtraRepeat, arg.SpotID, arg.UserID, arg.PieceID)
-	return err
-}
-
-const promoteSpotToInterleave = `-- name: PromoteSpotToInterleave :exec
-UPDATE spots
-SET
-    stage = CASE WHEN stage = 'random' THEN 'interleave' ELSE stage END,
-    stage_started = CASE WHEN stage = 'random' THEN unixepoch('now') ELSE stage_started END,
-    last_practiced = unixepoch('now')
-WHERE spots.id = ?1 AND piece_id = (SELECT pieces.id FROM pieces WHERE pieces.user_id = ?2 AND pieces.id = ?3 LIMIT 1)
-`
-
-type PromoteSpotToInterleaveParams struct {
-	SpotID  string `json:"spotId"`
-	UserID  string `json:"userId"`
-	PieceID string `json:"pieceId"`
-}
-
-func (q *Queries) PromoteSpotToInterleave(ctx context.Context, arg PromoteSpotToInterleaveParams) error {
-	_, err := q.db.ExecContext(ctx, promoteSpotToInterleave, arg.SpotID, arg.UserID, arg.PieceID)
-	return err
-}
-
-const promoteSpotToInterleaveDays = `-- name: PromoteSpotToInterleaveDays :exec
-UPDATE spots
-SET
-    stage = CASE WHEN stage = 'interleave' THEN 'interleave_days' ELSE stage END,
-    stage_started = CASE WHEN stage = 'interleave' THEN unixepoch('now') ELSE stage_started END,
-    skip_days = 1,
-    last_practiced = unixepoch('now')
-WHERE spots.id = ?1 AND piece_id IN (SELECT pieces.id FROM pieces WHERE pieces.user_id = ?2)
+const listSpotsWithoutMeasureRange = `-- name: ListSpotsWithoutMeasureRange :many
+SELECT id, measures
+FROM spots
+WHERE measures IS NOT NULL AND measures != '' AND measures_start IS NULL
 `
 
-type PromoteSpotToInterleaveDaysParams struct {
-	SpotID string `json:"spotId"`
-	UserID string `json:"userId"`
+type ListSpotsWithoutMeasureRangeRow struct {
+	ID       string         `json:"id"`
+	Measures sql.NullString `json:"measures"`
 }
 
-func (q *Queries) PromoteSpotToInterleaveDays(ctx context.Context, arg PromoteSpotToInterleaveDaysParams) error {
-	_, err := q.db.ExecContext(ctx, promoteSpotToInterleaveDays, arg.SpotID, arg.UserID)
-	return err
+func (q *Queries) ListSpotsWithoutMeasureRange(ctx context.Context) ([]ListSpotsWithoutMeasureRangeRow, error) {
+	rows, err := q.db.QueryContext(ctx, listSpotsWithoutMeasureRange)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ListSpotsWithoutMeasureRangeRow
+	for rows.Next() {
+		var i ListSpotsWithoutMeasureRangeRow
+		if err := rows.Scan(&i.ID, &i.Measures); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
 }
 
-const promoteSpotToRandom = `-- name: PromoteSpotToRandom :exec
+const raiseSpotTempo = `-- name: RaiseSpotTempo :exec
 UPDATE spots
-SET
-    stage = CASE WHEN stage = 'repeat' OR stage = 'extra_repeat' THEN 'random' ELSE stage END,
-    stage_started = CASE WHEN stage = 'repeat' OR stage = 'extra_repeat' THEN unixepoch('now') ELSE stage_started END,
-    last_practiced = unixepoch('now')
-WHERE spots.id = ?1 AND piece_id = (SELECT pieces.id FROM pieces WHERE pieces.user_id = ?2 AND pieces.id = ?3 LIMIT 1)
+SET current_tempo = ?1
+WHERE spots.id = ?2
+    AND spots.piece_id = (SELECT pieces.id FROM pieces WHERE pieces.user_id = ?3 AND pieces.id = ?4 LIMIT 1)
+    AND (spots.current_tempo IS NULL OR spots.current_tempo < ?1)
 `
 
-type PromoteSpotToRandomParams struct {
-	SpotID  string `json:"spotId"`
-	UserID  string `json:"userId"`
-	PieceID string `json:"pieceId"`
+type RaiseSpotTempoParams struct {
+	Tempo   sql.NullInt64 `json:"tempo"`
+	SpotID  string        `json:"spotId"`
+	UserID  string        `json:"userId"`
+	PieceID string        `json:"pieceId"`
 }
 
-func (q *Queries) PromoteSpotToRandom(ctx context.Context, arg PromoteSpotToRandomParams) error {
-	_, err := q.db.ExecContext(ctx, promoteSpotToRandom, arg.SpotID, arg.UserID, arg.PieceID)
+func (q *Queries) RaiseSpotTempo(ctx context.Context, arg RaiseSpotTempoParams) error {
+	_, err := q.db.ExecContext(ctx, raiseSpotTempo,
+		arg.Tempo,
+		arg.SpotID,
+		arg.UserID,
+		arg.PieceID,
+	)
 	return err
 }
 
@@ -838,7 +781,7 @@ UPDATE spots
 SET
     notes_prompt = ?
 WHERE spots.id = ? AND piece_id = (SELECT pieces.id FROM pieces WHERE pieces.user_id = ? AND pieces.id = ? LIMIT 1)
-RETURNING id, piece_id, name, stage, measures, audio_prompt_url, image_prompt_url, notes_prompt, text_prompt, current_tempo, last_practiced, stage_started, skip_days, priority, section_id
+RETURNING id, piece_id, name, stage, measures, audio_prompt_url, image_prompt_url, notes_prompt, text_prompt, current_tempo, last_practiced, stage_started, skip_days, priority, section_id, measures_start, measures_end
 `
 
 type UpdateNotesPromptParams struct {
@@ -872,6 +815,8 @@ func (q *Queries) UpdateNotesPrompt(ctx context.Context, arg UpdateNotesPromptPa
 		&i.SkipDays,
 		&i.Priority,
 		&i.SectionID,
+		&i.MeasuresStart,
+		&i.MeasuresEnd,
 	)
 	return i, err
 }
@@ -881,18 +826,22 @@ UPDATE spots
 SET
     name = ?,
     current_tempo = ?,
-    measures = ?
+    measures = ?,
+    measures_start = ?,
+    measures_end = ?
 WHERE spots.id = ? AND piece_id = (SELECT pieces.id FROM pieces WHERE pieces.user_id = ? AND pieces.id = ? LIMIT 1)
-RETURNING id, piece_id, name, stage, measures, audio_prompt_url, image_prompt_url, notes_prompt, text_prompt, current_tempo, last_practiced, stage_started, skip_days, priority, section_id
+RETURNING id, piece_id, name, stage, measures, audio_prompt_url, image_prompt_url, notes_prompt, text_prompt, current_tempo, last_practiced, stage_started, skip_days, priority, section_id, measures_start, measures_end
 `
 
 type UpdatePartialSpotParams struct {
-	Name         string         `json:"name"`
-	CurrentTempo sql.NullInt64  `json:"currentTempo"`
-	Measures     sql.NullString `json:"measures"`
-	SpotID       string         `json:"spotId"`
-	UserID       string         `json:"userId"`
-	PieceID      string         `json:"pieceId"`
+	Name          string         `json:"name"`
+	CurrentTempo  sql.NullInt64  `json:"currentTempo"`
+	Measures      sql.NullString `json:"measures"`
+	MeasuresStart sql.NullInt64  `json:"measuresStart"`
+	MeasuresEnd   sql.NullInt64  `json:"measuresEnd"`
+	SpotID        string         `json:"spotId"`
+	UserID        string         `json:"userId"`
+	PieceID       string         `json:"pieceId"`
 }
 
 func (q *Queries) UpdatePartialSpot(ctx context.Context, arg UpdatePartialSpotParams) (Spot, error) {
@@ -900,6 +849,8 @@ func (q *Queries) UpdatePartialSpot(ctx context.Context, arg UpdatePartialSpotPa
 		arg.Name,
 		arg.CurrentTempo,
 		arg.Measures,
+		arg.MeasuresStart,
+		arg.MeasuresEnd,
 		arg.SpotID,
 		arg.UserID,
 		arg.PieceID,
@@ -921,6 +872,8 @@ func (q *Queries) UpdatePartialSpot(ctx context.Context, arg UpdatePartialSpotPa
 		&i.SkipDays,
 		&i.Priority,
 		&i.SectionID,
+		&i.MeasuresStart,
+		&i.MeasuresEnd,
 	)
 	return i, err
 }
@@ -936,7 +889,9 @@ SET
     notes_prompt = ?,
     text_prompt = ?,
     current_tempo = ?,
-    measures = ?
+    measures = ?,
+    measures_start = ?,
+    measures_end = ?
 WHERE spots.id = ? AND piece_id = (SELECT pieces.id FROM pieces WHERE pieces.user_id = ? AND pieces.id = ? LIMIT 1)
 `
 
@@ -950,6 +905,8 @@ type UpdateSpotParams struct {
 	TextPrompt     string         `json:"textPrompt"`
 	CurrentTempo   sql.NullInt64  `json:"currentTempo"`
 	Measures       sql.NullString `json:"measures"`
+	MeasuresStart  sql.NullInt64  `json:"measuresStart"`
+	MeasuresEnd    sql.NullInt64  `json:"measuresEnd"`
 	SpotID         string         `json:"spotId"`
 	UserID         string         `json:"userId"`
 	PieceID        string         `json:"pieceId"`
@@ -966,6 +923,8 @@ func (q *Queries) UpdateSpot(ctx context.Context, arg UpdateSpotParams) error {
 		arg.TextPrompt,
 		arg.CurrentTempo,
 		arg.Measures,
+		arg.MeasuresStart,
+		arg.MeasuresEnd,
 		arg.SpotID,
 		arg.UserID,
 		arg.PieceID,
@@ -973,6 +932,25 @@ func (q *Queries) UpdateSpot(ctx context.Context, arg UpdateSpotParams) error {
 	return err
 }
 
+const updateSpotMeasureRange = `-- name: UpdateSpotMeasureRange :exec
+UPDATE spots
+SET
+    measures_start = ?1,
+    measures_end = ?2
+WHERE spots.id = ?3
+`
+
+type UpdateSpotMeasureRangeParams struct {
+	MeasuresStart sql.NullInt64 `json:"measuresStart"`
+	MeasuresEnd   sql.NullInt64 `json:"measuresEnd"`
+	SpotID        string        `json:"spotId"`
+}
+
+func (q *Queries) UpdateSpotMeasureRange(ctx context.Context, arg UpdateSpotMeasureRangeParams) error {
+	_, err := q.db.ExecContext(ctx, updateSpotMeasureRange, arg.MeasuresStart, arg.MeasuresEnd, arg.SpotID)
+	return err
+}
+
 const updateSpotPracticed = `-- name: UpdateSpotPracticed :exec
 UPDATE spots
 SET last_practiced = unixepoch('now')
@@ -1055,7 +1033,7 @@ UPDATE spots
 SET
     text_prompt = ?
 WHERE spots.id = ? AND piece_id = (SELECT pieces.id FROM pieces WHERE pieces.user_id = ? AND pieces.id = ? LIMIT 1)
-RETURNING id, piece_id, name, stage, measures, audio_prompt_url, image_prompt_url, notes_prompt, text_prompt, current_tempo, last_practiced, stage_started, skip_days, priority, section_id
+RETURNING id, piece_id, name, stage, measures, audio_prompt_url, image_prompt_url, notes_prompt, text_prompt, current_tempo, last_practiced, stage_started, skip_days, priority, section_id, measures_start, measures_end
 `
 
 type UpdateTextPromptParams struct {
@@ -1089,6 +1067,8 @@ func (q *Queries) UpdateTextPrompt(ctx context.Context, arg UpdateTextPromptPara
 		&i.SkipDays,
 		&i.Priority,
 		&i.SectionID,
+		&i.MeasuresStart,
+		&i.MeasuresEnd,
 	)
 	return i, err
 }
@@ -80,7 +80,7 @@ func (q *Queries) CreateCredential(ctx context.Context, arg CreateCredentialPara
 
 const createUser = `-- name: CreateUser :one
 INSERT INTO users (id, fullname, email) VALUES (?, ?, ?)
-RETURNING id, fullname, email, email_verified, active_practice_plan_id, active_practice_plan_started, config_default_plan_intensity, config_time_between_breaks
+RETURNING id, fullname, email, email_verified, active_practice_plan_id, active_practice_plan_started, config_default_plan_intensity, config_time_between_breaks, config_default_time_budget, config_scheduler, config_timezone
 `
 
 type CreateUserParams struct {
@@ -101,6 +101,9 @@ func (q *Queries) CreateUser(ctx context.Context, arg CreateUserParams) (User, e
 		&i.ActivePracticePlanStarted,
 		&i.ConfigDefaultPlanIntensity,
 		&i.ConfigTimeBetweenBreaks,
+		&i.ConfigDefaultTimeBudget,
+		&i.ConfigScheduler,
+		&i.ConfigTimezone,
 	)
 	return i, err
 }
@@ -124,7 +127,7 @@ func (q *Queries) DeleteUserCredentials(ctx context.Context, userID string) erro
 }
 
 const getUserByEmail = `-- name: GetUserByEmail :one
-SELECT id, fullname, email, email_verified, active_practice_plan_id, active_practice_plan_started, config_default_plan_intensity, config_time_between_breaks
+SELECT id, fullname, email, email_verified, active_practice_plan_id, active_practice_plan_started, config_default_plan_intensity, config_time_between_breaks, config_default_time_budget, config_scheduler, config_timezone
 FROM users
 WHERE email = LOWER(?1)
 `
@@ -141,12 +144,15 @@ func (q *Queries) GetUserByEmail(ctx context.Context, email string) (User, error
 		&i.ActivePracticePlanStarted,
 		&i.ConfigDefaultPlanIntensity,
 		&i.ConfigTimeBetweenBreaks,
+		&i.ConfigDefaultTimeBudget,
+		&i.ConfigScheduler,
+		&i.ConfigTimezone,
 	)
 	return i, err
 }
 
 const getUserByID = `-- name: GetUserByID :one
-SELECT id, fullname, email, email_verified, active_practice_plan_id, active_practice_plan_started, config_default_plan_intensity, config_time_between_breaks
+SELECT id, fullname, email, email_verified, active_practice_plan_id, active_practice_plan_started, config_default_plan_intensity, config_time_between_breaks, config_default_time_budget, config_scheduler, config_timezone
 FROM users
 WHERE id = ?1
 `
@@ -163,6 +169,9 @@ func (q *Queries) GetUserByID(ctx context.Context, userID string) (User, error)
 		&i.ActivePracticePlanStarted,
 		&i.ConfigDefaultPlanIntensity,
 		&i.ConfigTimeBetweenBreaks,
+		&i.ConfigDefaultTimeBudget,
+		&i.ConfigScheduler,
+		&i.ConfigTimezone,
 	)
 	return i, err
 }
@@ -255,7 +264,7 @@ func (q *Queries) SetActivePracticePlan(ctx context.Context, arg SetActivePracti
 
 const setEmailVerified = `-- name: SetEmailVerified :exec
 UPDATE users SET email_verified = 1 WHERE id = ?
-RETURNING id, fullname, email, email_verified, active_practice_plan_id, active_practice_plan_started, config_default_plan_intensity, config_time_between_breaks
+RETURNING id, fullname, email, email_verified, active_practice_plan_id, active_practice_plan_started, config_default_plan_intensity, config_time_between_breaks, config_default_time_budget, config_scheduler, config_timezone
 `
 
 func (q *Queries) SetEmailVerified(ctx context.Context, id string) error {
@@ -263,13 +272,46 @@ func (q *Queries) SetEmailVerified(ctx context.Context, id string) error {
 	return err
 }
 
+const setUserDefaultPlanIntensity = `-- name: SetUserDefaultPlanIntensity :exec
+UPDATE users
+SET config_default_plan_intensity = ?
+WHERE id = ? AND config_default_plan_intensity = ?
+`
+
+type SetUserDefaultPlanIntensityParams struct {
+	ConfigDefaultPlanIntensity string `json:"configDefaultPlanIntensity"`
+	UserID                     string `json:"userId"`
+	PreviousIntensity          string `json:"previousIntensity"`
+}
+
+func (q *Queries) SetUserDefaultPlanIntensity(ctx context.Context, arg SetUserDefaultPlanIntensityParams) error {
+	_, err := q.db.ExecContext(ctx, setUserDefaultPlanIntensity, arg.ConfigDefaultPlanIntensity, arg.UserID, arg.PreviousIntensity)
+	return err
+}
+
+const setUserTimezone = `-- name: SetUserTimezone :exec
+UPDATE users
+SET config_timezone = ?
+WHERE id = ? AND config_timezone = ''
+`
+
+type SetUserTimezoneParams struct {
+	ConfigTimezone string `json:"configTimezone"`
+	UserID         string `json:"userId"`
+}
+
+func (q *Queries) SetUserTimezone(ctx context.Context, arg SetUserTimezoneParams) error {
+	_, err := q.db.ExecContext(ctx, setUserTimezone, arg.ConfigTimezone, arg.UserID)
+	return err
+}
+
 const updateUser = `-- name: UpdateUser :one
 UPDATE users
 SET fullname = COALESCE(?, fullname),
     email = COALESCE(?, email),
     email_verified = COALESCE(?, email_verified)
 WHERE id = ?
-RETURNING id, fullname, email, email_verified, active_practice_plan_id, active_practice_plan_started, config_default_plan_intensity, config_time_between_breaks
+RETURNING id, fullname, email, email_verified, active_practice_plan_id, active_practice_plan_started, config_default_plan_intensity, config_time_between_breaks, config_default_time_budget, config_scheduler, config_timezone
 `
 
 type UpdateUserParams struct {
@@ -296,6 +338,9 @@ func (q *Queries) UpdateUser(ctx context.Context, arg UpdateUserParams) (User, e
 		&i.ActivePracticePlanStarted,
 		&i.ConfigDefaultPlanIntensity,
 		&i.ConfigTimeBetweenBreaks,
+		&i.ConfigDefaultTimeBudget,
+		&i.ConfigScheduler,
+		&i.ConfigTimezone,
 	)
 	return i, err
 }
@@ -304,19 +349,32 @@ const updateUserSettings = `-- name: UpdateUserSettings :one
 UPDATE users
 SET
     config_default_plan_intensity = COALESCE(?, config_default_plan_intensity),
-    config_time_between_breaks = COALESCE(?, config_time_between_breaks)
+    config_time_between_breaks = COALESCE(?, config_time_between_breaks),
+    config_default_time_budget = COALESCE(?, config_default_time_budget),
+    config_scheduler = COALESCE(?, config_scheduler),
+    config_timezone = COALESCE(?, config_timezone)
 WHERE id = ?
-RETURNING id, fullname, email, email_verified, active_practice_plan_id, active_practice_plan_started, config_default_plan_intensity, config_time_between_breaks
+RETURNING id, fullname, email, email_verified, active_practice_plan_id, active_practice_plan_started, config_default_plan_intensity, config_time_between_breaks, config_default_time_budget, config_scheduler, config_timezone
 `
 
 type UpdateUserSettingsParams struct {
 	ConfigDefaultPlanIntensity string `json:"configDefaultPlanIntensity"`
 	ConfigTimeBetweenBreaks    int64  `json:"configTimeBetweenBreaks"`
+	ConfigDefaultTimeBudget    int64  `json:"configDefaultTimeBudget"`
+	ConfigScheduler            string `json:"configScheduler"`
+	ConfigTimezone             string `json:"configTimezone"`
 	ID                         string `json:"id"`
 }
 
 func (q *Queries) UpdateUserSettings(ctx context.Context, arg UpdateUserSettingsParams) (User, error) {
-	row := q.db.QueryRowContext(ctx, updateUserSettings, arg.ConfigDefaultPlanIntensity, arg.ConfigTimeBetweenBreaks, arg.ID)
+	row := q.db.QueryRowContext(ctx, updateUserSettings,
+		arg.ConfigDefaultPlanIntensity,
+		arg.ConfigTimeBetweenBreaks,
+		arg.ConfigDefaultTimeBudget,
+		arg.ConfigScheduler,
+		arg.ConfigTimezone,
+		arg.ID,
+	)
 	var i User
 	err := row.Scan(
 		&i.ID,
@@ -327,6 +385,9 @@ func (q *Queries) UpdateUserSettings(ctx context.Context, arg UpdateUserSettings
 		&i.ActivePracticePlanStarted,
 		&i.ConfigDefaultPlanIntensity,
 		&i.ConfigTimeBetweenBreaks,
+		&i.ConfigDefaultTimeBudget,
+		&i.ConfigScheduler,
+		&i.ConfigTimezone,
 	)
 	return i, err
 }
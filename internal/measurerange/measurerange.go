@@ -0,0 +1,135 @@
+package measurerange
+
+import (
+	"errors"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// Range is a span of measures, both ends included
+type Range struct {
+	Start int
+	End   int
+}
+
+// String is how the range is written in a spot's measures field
+func (r Range) String() string {
+	if r.Start == r.End {
+		return strconv.Itoa(r.Start)
+	}
+	return strconv.Itoa(r.Start) + "-" + strconv.Itoa(r.End)
+}
+
+// Name is the default name for a spot covering the range
+func (r Range) Name() string {
+	if r.Start == r.End {
+		return "Measure " + r.String()
+	}
+	return "Measures " + r.String()
+}
+
+// Chunks splits measures 1 through total into ranges of size measures, the last one may be shorter
+func Chunks(total int, size int) []Range {
+	if size < 1 || total < 1 {
+		return nil
+	}
+	// a bigger size is one spot anyway, and keeps start from overflowing
+	size = min(size, total)
+	ranges := make([]Range, 0, total/size+1)
+	for start := 1; start <= total; start += size {
+		ranges = append(ranges, Range{Start: start, End: min(start+size-1, total)})
+	}
+	return ranges
+}
+
+// ParseList reads a list of ranges like "1-8, 9-16, 17" separated by commas, semicolons or new lines
+func ParseList(text string) ([]Range, error) {
+	items := strings.FieldsFunc(text, func(r rune) bool {
+		return r == ',' || r == ';' || r == '\n'
+	})
+	ranges := make([]Range, 0, len(items))
+	for _, item := range items {
+		item = strings.TrimSpace(item)
+		if item == "" {
+			continue
+		}
+		r, err := Parse(item)
+		if err != nil {
+			return nil, err
+		}
+		ranges = append(ranges, r)
+	}
+	if len(ranges) == 0 {
+		return nil, errors.New("Enter at least one range of measures")
+	}
+	return ranges, nil
+}
+
+// Parse reads a single range like "9-16" or a single measure like "17". En dashes and "to" work too.
+func Parse(text string) (Range, error) {
+	text = strings.TrimSpace(text)
+	text = strings.NewReplacer("–", "-", "—", "-", " to ", "-").Replace(text)
+	start, end, found := strings.Cut(text, "-")
+	if !found {
+		end = start
+	}
+	r := Range{}
+	var err error
+	if r.Start, err = strconv.Atoi(strings.TrimSpace(start)); err != nil {
+		return Range{}, errors.New("\"" + text + "\" is not a range of measures")
+	}
+	if r.End, err = strconv.Atoi(strings.TrimSpace(end)); err != nil {
+		return Range{}, errors.New("\"" + text + "\" is not a range of measures")
+	}
+	if r.Start < 1 {
+		return Range{}, errors.New("Measures start at 1")
+	}
+	if r.End < r.Start {
+		return Range{}, errors.New("\"" + text + "\" ends before it starts")
+	}
+	return r, nil
+}
+
+var (
+	spotMeasureWords = regexp.MustCompile(`\b(mm|m|bars?|measures?|meas)\b\.?`)
+	spotMeasureItem  = regexp.MustCompile(`^(\d+)[a-z]*(?:\s*-\s*(\d+)[a-z]*)?$`)
+)
+
+// ParseSpot reads the measures people write on a spot, like "12-16", "m. 3-4, 7" or "34b-40", and
+// returns the first and last measure it covers. Letters after a measure number are ignored, so
+// "34b" counts as measure 34. It returns false if the text doesn't look like measure numbers.
+func ParseSpot(text string) (Range, bool) {
+	text = strings.ToLower(text)
+	text = strings.NewReplacer("–", "-", "—", "-", " to ", "-", "&", ",", " and ", ",").Replace(text)
+	text = spotMeasureWords.ReplaceAllString(text, "")
+	items := strings.FieldsFunc(text, func(r rune) bool {
+		return r == ',' || r == ';' || r == '\n'
+	})
+	span := Range{}
+	for _, item := range items {
+		item = strings.TrimSpace(item)
+		if item == "" {
+			continue
+		}
+		match := spotMeasureItem.FindStringSubmatch(item)
+		if match == nil {
+			return Range{}, false
+		}
+		start, err := strconv.Atoi(match[1])
+		if err != nil || start < 1 {
+			return Range{}, false
+		}
+		end := start
+		if match[2] != "" {
+			if end, err = strconv.Atoi(match[2]); err != nil || end < start {
+				return Range{}, false
+			}
+		}
+		if span.Start == 0 || start < span.Start {
+			span.Start = start
+		}
+		span.End = max(span.End, end)
+	}
+	return span, span.Start > 0
+}
@@ -0,0 +1,86 @@
+package planner
+
+import (
+	"practicebetter/internal/config"
+	"practicebetter/internal/db"
+	"time"
+)
+
+// Estimates are how long a single item of each kind takes to practice
+type Estimates struct {
+	NewSpot            time.Duration
+	ExtraRepeatSpot    time.Duration
+	InterleaveSpot     time.Duration
+	InfrequentSpot     time.Duration
+	RandomSpotsPiece   time.Duration
+	StartingPointPiece time.Duration
+	Scale              time.Duration
+	Reading            time.Duration
+}
+
+func DefaultEstimates() Estimates {
+	return Estimates{
+		NewSpot:            config.ESTIMATE_NEW_SPOT,
+		ExtraRepeatSpot:    config.ESTIMATE_EXTRA_REPEAT_SPOT,
+		InterleaveSpot:     config.ESTIMATE_INTERLEAVE_SPOT,
+		InfrequentSpot:     config.ESTIMATE_INFREQUENT_SPOT,
+		RandomSpotsPiece:   config.ESTIMATE_RANDOM_SPOTS_PIECE,
+		StartingPointPiece: config.ESTIMATE_STARTING_POINT_PIECE,
+		Scale:              config.ESTIMATE_SCALE,
+		Reading:            config.ESTIMATE_READING,
+	}
+}
+
+// WithHistory replaces the estimate for each practice type that has enough recorded sessions
+// with the user's average session length. Only repeat, random spots and starting point practice
+// record how long they took, everything else keeps its default.
+func (e Estimates) WithHistory(averages []db.ListUserPracticeSessionAveragesRow) Estimates {
+	for _, average := range averages {
+		if average.SessionCount < config.ESTIMATE_MIN_SESSIONS || average.AverageMinutes <= 0 {
+			continue
+		}
+		estimate := time.Duration(average.AverageMinutes * float64(time.Minute))
+		switch average.PracticeType {
+		case "repeat":
+			e.NewSpot = estimate
+		case "random_spots":
+			e.RandomSpotsPiece = estimate
+		case "starting_point":
+			e.StartingPointPiece = estimate
+		}
+	}
+	return e
+}
+
+// fitToBudget trims the plan to fit in the time budget. Items are taken one from each category at a
+// time so that a long category can't crowd out the others, and each list keeps its original order.
+func fitToBudget(plan *Plan, budget time.Duration, estimates Estimates) {
+	categories := []struct {
+		ids  *[]string
+		each time.Duration
+	}{
+		{&plan.ExtraRepeatSpotIDs, estimates.ExtraRepeatSpot},
+		{&plan.NewSpotIDs, estimates.NewSpot},
+		{&plan.InfrequentSpotIDs, estimates.InfrequentSpot},
+		{&plan.InterleaveSpotIDs, estimates.InterleaveSpot},
+		{&plan.RandomSpotPieceIDs, estimates.RandomSpotsPiece},
+		{&plan.StartingPointPieceIDs, estimates.StartingPointPiece},
+		{&plan.ReadingIDs, estimates.Reading},
+	}
+	kept := make([]int, len(categories))
+	remaining := budget
+	for added := true; added; {
+		added = false
+		for i, category := range categories {
+			if kept[i] < len(*category.ids) && category.each <= remaining {
+				kept[i]++
+				remaining -= category.each
+				added = true
+			}
+		}
+	}
+	for i, category := range categories {
+		*category.ids = (*category.ids)[:kept[i]]
+	}
+	plan.EstimatedDuration = budget - remaining
+}
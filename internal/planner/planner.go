@@ -0,0 +1,321 @@
+package planner
+
+import (
+	"cmp"
+	"database/sql"
+	"math"
+	"math/rand"
+	"practicebetter/internal/config"
+	"practicebetter/internal/db"
+	"practicebetter/internal/scheduler"
+	"slices"
+	"time"
+)
+
+// Settings are the caps and options chosen on the create plan form
+type Settings struct {
+	MaxNewSpots           int
+	MaxInfrequentSpots    int
+	MaxInterleaveSpots    int
+	MaxSightReading       int
+	PracticeNew           bool
+	PracticeInterleave    bool
+	PracticeRandomSpots   bool
+	PracticeStartingPoint bool
+	// when set, the max values are ignored and the plan is filled up to the budget instead
+	TimeBudget time.Duration
+	Estimates  Estimates
+	// decides when infrequent spots are due, the classic scheduler is used if this is nil
+	Scheduler scheduler.Scheduler
+}
+
+// Piece is a piece selected for the plan along with the rows from GetPieceForPlan
+type Piece struct {
+	ID   string
+	Rows []db.GetPieceForPlanRow
+}
+
+type Input struct {
+	Settings Settings
+	Pieces   []Piece
+	// new spots from the last plan that are still in the repeat stage, in their original order
+	FailedNewSpotIDs []string
+	// incomplete reading items, only set if reading was requested
+	ReadingIDs []string
+	// infrequent spot evaluations by spot id, oldest first
+	Reviews map[string][]scheduler.Review
+	// Now is in the user's timezone, which decides when infrequent spots are due
+	Now time.Time
+}
+
+// Plan is everything that should be saved for a new practice plan, in order
+type Plan struct {
+	ExtraRepeatSpotIDs    []string
+	InterleaveSpotIDs     []string
+	InfrequentSpotIDs     []string
+	NewSpotIDs            []string
+	RandomSpotPieceIDs    []string
+	StartingPointPieceIDs []string
+	ReadingIDs            []string
+	// infrequent spots that are missing skip days and need to be fixed
+	MissingSkipDaysSpotIDs []string
+	// only set for time budgeted plans
+	EstimatedDuration time.Duration
+}
+
+type PotentialInfrequentSpot struct {
+	ID string
+	// how far through its interval the spot is, 1 is exactly due and 2 is a whole interval late
+	Overdue  float64
+	Priority int64
+}
+
+// overdue is used to order infrequent spots, how overdue they are scaled by priority
+func (s PotentialInfrequentSpot) overdue() float64 {
+	return s.Overdue * PriorityWeight(s.Priority)
+}
+
+type PieceInfo struct {
+	NewSpotIDs               []string
+	ExtraRepeatSpotIDs       []string
+	InterleaveSpotIDs        []string
+	PotentialInfrequentSpots []PotentialInfrequentSpot
+	MissingSkipDaysSpotIDs   []string
+	RandomSpotCount          int
+	ExtraRepeatSpotCount     int
+	CompletedSpotCount       int
+}
+
+// GeneratePieceInfo sorts the spots of a piece into the categories used for planning
+func GeneratePieceInfo(rows []db.GetPieceForPlanRow, failedNewSpotIDs map[string]struct{}, sched scheduler.Scheduler, reviews map[string][]scheduler.Review, now time.Time) PieceInfo {
+	if sched == nil {
+		sched = scheduler.Get(scheduler.Classic)
+	}
+	info := PieceInfo{
+		NewSpotIDs:               make([]string, 0, len(rows)/2),
+		ExtraRepeatSpotIDs:       make([]string, 0, len(rows)/4),
+		InterleaveSpotIDs:        make([]string, 0, len(rows)/4),
+		PotentialInfrequentSpots: make([]PotentialInfrequentSpot, 0, len(rows)/4),
+	}
+	for _, row := range rows {
+		if !row.SpotStage.Valid || !row.SpotID.Valid {
+			continue
+		}
+		switch row.SpotStage.String {
+		case "repeat":
+			// we're going to combine the lists later, so make need to prevent duplicates
+			if _, ok := failedNewSpotIDs[row.SpotID.String]; !ok {
+				info.NewSpotIDs = append(info.NewSpotIDs, row.SpotID.String)
+			}
+		case "extra_repeat":
+			info.ExtraRepeatSpotIDs = append(info.ExtraRepeatSpotIDs, row.SpotID.String)
+			info.ExtraRepeatSpotCount += 1
+		case "interleave":
+			info.InterleaveSpotIDs = append(info.InterleaveSpotIDs, row.SpotID.String)
+		case "interleave_days":
+			if !row.SpotSkipDays.Valid {
+				info.MissingSkipDaysSpotIDs = append(info.MissingSkipDaysSpotIDs, row.SpotID.String)
+			}
+
+			spot := scheduler.NewSpot(row.SpotSkipDays.Int64, row.SpotLastPracticed, sql.NullInt64{}, reviews[row.SpotID.String])
+			due := sched.Due(spot, now.Location())
+			if !due.After(now) {
+				info.PotentialInfrequentSpots = append(info.PotentialInfrequentSpots, PotentialInfrequentSpot{
+					ID:       row.SpotID.String,
+					Overdue:  overdue(spot, due, now),
+					Priority: row.SpotPriority.Int64,
+				})
+			}
+		case "random":
+			info.RandomSpotCount += 1
+		case "completed":
+			info.CompletedSpotCount += 1
+		default:
+			continue
+		}
+	}
+	return info
+}
+
+// overdue compares the time since the spot was practiced to its interval, so that a spot that
+// comes back every day and is a day late is more urgent than one that comes back every month
+func overdue(spot scheduler.Spot, due time.Time, now time.Time) float64 {
+	if spot.LastPracticed.IsZero() {
+		return math.MaxFloat64
+	}
+	interval := due.Sub(spot.LastPracticed)
+	if interval <= 0 {
+		return 1
+	}
+	return float64(now.Sub(spot.LastPracticed)) / float64(interval)
+}
+
+type Planner struct {
+	rng *rand.Rand
+}
+
+// New creates a planner, plans generated with the same seed and input are identical
+func New(seed int64) *Planner {
+	return &Planner{
+		rng: rand.New(rand.NewSource(seed)),
+	}
+}
+
+func (p *Planner) shuffle(ids []string) {
+	p.rng.Shuffle(len(ids), func(i, j int) {
+		ids[i], ids[j] = ids[j], ids[i]
+	})
+}
+
+// PickScale chooses one of the scale ids at random
+func (p *Planner) PickScale(scaleIDs []int64) (int64, bool) {
+	if len(scaleIDs) == 0 {
+		return 0, false
+	}
+	return scaleIDs[p.rng.Intn(len(scaleIDs))], true
+}
+
+func (p *Planner) Generate(in Input) Plan {
+	settings := in.Settings
+	if settings.TimeBudget > 0 {
+		// everything is a candidate, the budget decides what is kept at the end
+		settings.MaxNewSpots = math.MaxInt
+		settings.MaxInterleaveSpots = math.MaxInt
+		settings.MaxInfrequentSpots = math.MaxInt
+		settings.MaxSightReading = math.MaxInt
+	}
+	plan := Plan{
+		ExtraRepeatSpotIDs:    make([]string, 0, len(in.Pieces)*10),
+		InterleaveSpotIDs:     make([]string, 0, min(settings.MaxInterleaveSpots, len(in.Pieces)*10)),
+		InfrequentSpotIDs:     make([]string, 0, min(settings.MaxInfrequentSpots, len(in.Pieces)*10)),
+		NewSpotIDs:            make([]string, 0, min(settings.MaxNewSpots, len(in.Pieces)*10)),
+		RandomSpotPieceIDs:    make([]string, 0, len(in.Pieces)),
+		StartingPointPieceIDs: make([]string, 0, len(in.Pieces)),
+		ReadingIDs:            make([]string, 0, min(settings.MaxSightReading, len(in.ReadingIDs))),
+	}
+
+	failedNewSpotIDs := make([]string, 0, len(in.FailedNewSpotIDs))
+	failedNewSpotSet := make(map[string]struct{}, len(in.FailedNewSpotIDs))
+	for _, spotID := range in.FailedNewSpotIDs {
+		if _, ok := failedNewSpotSet[spotID]; ok {
+			continue
+		}
+		failedNewSpotSet[spotID] = struct{}{}
+		failedNewSpotIDs = append(failedNewSpotIDs, spotID)
+	}
+
+	readingIDs := slices.Clone(in.ReadingIDs)
+	p.shuffle(readingIDs)
+	for i := 0; i < len(readingIDs) && i < settings.MaxSightReading; i++ {
+		plan.ReadingIDs = append(plan.ReadingIDs, readingIDs[i])
+	}
+
+	priorities := make(map[string]int64)
+	for _, piece := range in.Pieces {
+		for _, row := range piece.Rows {
+			if row.SpotID.Valid && row.SpotPriority.Valid {
+				priorities[row.SpotID.String] = row.SpotPriority.Int64
+			}
+		}
+	}
+
+	maybeNewSpotLists := make([][]string, 0, len(in.Pieces))
+	potentialInfrequentSpots := make([]PotentialInfrequentSpot, 0, len(in.Pieces)*10)
+	interleaveSpotIDs := make([]string, 0, len(in.Pieces)*10)
+
+	for _, piece := range in.Pieces {
+		pieceInfo := GeneratePieceInfo(piece.Rows, failedNewSpotSet, settings.Scheduler, in.Reviews, in.Now)
+
+		plan.ExtraRepeatSpotIDs = append(plan.ExtraRepeatSpotIDs, pieceInfo.ExtraRepeatSpotIDs...)
+		plan.MissingSkipDaysSpotIDs = append(plan.MissingSkipDaysSpotIDs, pieceInfo.MissingSkipDaysSpotIDs...)
+		interleaveSpotIDs = append(interleaveSpotIDs, pieceInfo.InterleaveSpotIDs...)
+		potentialInfrequentSpots = append(potentialInfrequentSpots, pieceInfo.PotentialInfrequentSpots...)
+
+		// Only new spots if there aren't too many extra repeat/random spots.
+		if (pieceInfo.ExtraRepeatSpotCount + pieceInfo.RandomSpotCount) < config.MAX_ALLOWED_RANDOM_SPOTS {
+			maybeNewSpotLists = append(maybeNewSpotLists, pieceInfo.NewSpotIDs)
+		}
+
+		if settings.PracticeRandomSpots && pieceInfo.RandomSpotCount > 2 {
+			plan.RandomSpotPieceIDs = append(plan.RandomSpotPieceIDs, piece.ID)
+		}
+
+		if settings.PracticeStartingPoint && pieceInfo.CompletedSpotCount > 5 {
+			plan.StartingPointPieceIDs = append(plan.StartingPointPieceIDs, piece.ID)
+		}
+	}
+
+	// extra repeat spots are always included
+	p.weightedShuffle(plan.ExtraRepeatSpotIDs, priorities)
+
+	if settings.PracticeInterleave {
+		p.weightedShuffle(interleaveSpotIDs, priorities)
+		for i, spotID := range interleaveSpotIDs {
+			if i >= settings.MaxInterleaveSpots {
+				break
+			}
+			plan.InterleaveSpotIDs = append(plan.InterleaveSpotIDs, spotID)
+		}
+
+		// prioritize infrequent spots with the most overdue spots first, adjusted for their priority
+		slices.SortStableFunc(potentialInfrequentSpots, func(a, b PotentialInfrequentSpot) int {
+			return cmp.Compare(b.overdue(), a.overdue())
+		})
+		for i, spot := range potentialInfrequentSpots {
+			if i >= settings.MaxInfrequentSpots {
+				break
+			}
+			plan.InfrequentSpotIDs = append(plan.InfrequentSpotIDs, spot.ID)
+		}
+	}
+
+	if settings.PracticeNew {
+		plan.NewSpotIDs = p.chooseNewSpots(failedNewSpotIDs, maybeNewSpotLists, settings.MaxNewSpots, priorities)
+	}
+
+	p.shuffle(plan.RandomSpotPieceIDs)
+	p.shuffle(plan.StartingPointPieceIDs)
+
+	if settings.TimeBudget > 0 {
+		fitToBudget(&plan, settings.TimeBudget, settings.Estimates)
+	}
+
+	return plan
+}
+
+// all the failed spots from the previous plan come first, then one spot from each piece, then
+// the rest are filled randomly from the remaining spots. Higher priority spots are more likely to be picked.
+func (p *Planner) chooseNewSpots(failedNewSpotIDs []string, pieceSpotLists [][]string, maxNewSpots int, priorities map[string]int64) []string {
+	newSpotIDs := make([]string, 0, min(maxNewSpots, len(failedNewSpotIDs)+len(pieceSpotLists)*10))
+	newSpotIDs = append(newSpotIDs, failedNewSpotIDs...)
+
+	additionalNewSpots := make([]string, 0, len(pieceSpotLists)*10)
+	for _, pieceSpotList := range pieceSpotLists {
+		if len(newSpotIDs) >= maxNewSpots {
+			break
+		}
+		pieceSpotList = slices.Clone(pieceSpotList)
+		p.weightedShuffle(pieceSpotList, priorities)
+		for i, spotID := range pieceSpotList {
+			if len(newSpotIDs) >= maxNewSpots {
+				break
+			}
+			if i == 0 {
+				newSpotIDs = append(newSpotIDs, spotID)
+			} else {
+				additionalNewSpots = append(additionalNewSpots, spotID)
+			}
+		}
+	}
+
+	if len(newSpotIDs) < maxNewSpots {
+		p.weightedShuffle(additionalNewSpots, priorities)
+		for _, spotID := range additionalNewSpots {
+			if len(newSpotIDs) >= maxNewSpots {
+				break
+			}
+			newSpotIDs = append(newSpotIDs, spotID)
+		}
+	}
+	return newSpotIDs
+}
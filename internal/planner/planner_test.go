@@ -0,0 +1,324 @@
+package planner_test
+
+import (
+	"database/sql"
+	"fmt"
+	"practicebetter/internal/config"
+	"practicebetter/internal/db"
+	"practicebetter/internal/planner"
+	"slices"
+	"testing"
+	"time"
+)
+
+var now = time.Date(2024, time.August, 14, 18, 0, 0, 0, time.UTC)
+
+type testSpot struct {
+	id       string
+	stage    string
+	priority int64
+	// days since the spot was last practiced, only used for infrequent spots
+	daysAgo  int
+	skipDays int64
+}
+
+func testPiece(id string, spots ...testSpot) planner.Piece {
+	piece := planner.Piece{ID: id}
+	for _, spot := range spots {
+		row := db.GetPieceForPlanRow{
+			ID:           id,
+			SpotID:       sql.NullString{String: spot.id, Valid: true},
+			SpotStage:    sql.NullString{String: spot.stage, Valid: true},
+			SpotPriority: sql.NullInt64{Int64: spot.priority, Valid: true},
+		}
+		if spot.stage == "interleave_days" {
+			row.SpotSkipDays = sql.NullInt64{Int64: spot.skipDays, Valid: true}
+			row.SpotLastPracticed = sql.NullInt64{Int64: now.AddDate(0, 0, -spot.daysAgo).Unix(), Valid: true}
+		}
+		piece.Rows = append(piece.Rows, row)
+	}
+	return piece
+}
+
+// spots makes count spots in a stage with ids like prefix1, prefix2...
+func spots(prefix string, stage string, count int) []testSpot {
+	result := make([]testSpot, 0, count)
+	for i := 1; i <= count; i++ {
+		result = append(result, testSpot{id: fmt.Sprintf("%s%d", prefix, i), stage: stage})
+	}
+	return result
+}
+
+func TestFailedNewSpotCarryover(t *testing.T) {
+	piece := testPiece("p1", spots("n", "repeat", 5)...)
+	tests := []struct {
+		name    string
+		seed    int64
+		failed  []string
+		maxNew  int
+		wantNew []string
+	}{
+		{
+			name:    "no failed spots",
+			seed:    1,
+			maxNew:  3,
+			wantNew: []string{"n2", "n3", "n4"},
+		},
+		{
+			name:    "failed spots come first in order",
+			seed:    1,
+			failed:  []string{"n4", "n2"},
+			maxNew:  4,
+			wantNew: []string{"n4", "n2", "n3", "n5"},
+		},
+		{
+			name:    "duplicate failed spots are only added once",
+			seed:    2,
+			failed:  []string{"n4", "n2", "n4"},
+			maxNew:  3,
+			wantNew: []string{"n4", "n2", "n3"},
+		},
+		{
+			name:    "failed spots fill the whole plan",
+			seed:    3,
+			failed:  []string{"n3", "n1"},
+			maxNew:  2,
+			wantNew: []string{"n3", "n1"},
+		},
+		{
+			name:    "failed spots go past the max",
+			seed:    3,
+			failed:  []string{"n3", "n1", "n5"},
+			maxNew:  2,
+			wantNew: []string{"n3", "n1", "n5"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			plan := planner.New(tt.seed).Generate(planner.Input{
+				Settings:         planner.Settings{MaxNewSpots: tt.maxNew, PracticeNew: true},
+				Pieces:           []planner.Piece{piece},
+				FailedNewSpotIDs: tt.failed,
+				Now:              now,
+			})
+			if !slices.Equal(plan.NewSpotIDs, tt.wantNew) {
+				t.Errorf("new spots = %v, want %v", plan.NewSpotIDs, tt.wantNew)
+			}
+		})
+	}
+}
+
+func TestMaxAllowedRandomSpots(t *testing.T) {
+	tests := []struct {
+		name        string
+		extraRepeat int
+		random      int
+		wantNew     []string
+	}{
+		{
+			name:    "no random spots",
+			wantNew: []string{"a2", "b1", "b2", "a1"},
+		},
+		{
+			name:        "just under the limit",
+			extraRepeat: 4,
+			random:      config.MAX_ALLOWED_RANDOM_SPOTS - 5,
+			wantNew:     []string{"a2", "b2", "b1", "a1"},
+		},
+		{
+			name:    "random spots at the limit",
+			random:  config.MAX_ALLOWED_RANDOM_SPOTS,
+			wantNew: []string{"b2", "b1"},
+		},
+		{
+			name:        "extra repeat and random spots add up to the limit",
+			extraRepeat: 5,
+			random:      config.MAX_ALLOWED_RANDOM_SPOTS - 5,
+			wantNew:     []string{"b1", "b2"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			full := spots("a", "repeat", 2)
+			full = append(full, spots("x", "extra_repeat", tt.extraRepeat)...)
+			full = append(full, spots("r", "random", tt.random)...)
+			plan := planner.New(1).Generate(planner.Input{
+				Settings: planner.Settings{MaxNewSpots: 10, PracticeNew: true},
+				Pieces:   []planner.Piece{testPiece("full", full...), testPiece("open", spots("b", "repeat", 2)...)},
+				Now:      now,
+			})
+			if !slices.Equal(plan.NewSpotIDs, tt.wantNew) {
+				t.Errorf("new spots = %v, want %v", plan.NewSpotIDs, tt.wantNew)
+			}
+		})
+	}
+}
+
+func TestInfrequentSpotOrder(t *testing.T) {
+	tests := []struct {
+		name          string
+		spots         []testSpot
+		maxInfrequent int
+		want          []string
+	}{
+		{
+			name: "most overdue first",
+			spots: []testSpot{
+				{id: "i1", stage: "interleave_days", skipDays: 1, daysAgo: 2},
+				{id: "i2", stage: "interleave_days", skipDays: 1, daysAgo: 6},
+				{id: "i3", stage: "interleave_days", skipDays: 1, daysAgo: 4},
+			},
+			maxInfrequent: 5,
+			want:          []string{"i2", "i3", "i1"},
+		},
+		{
+			name: "overdue compared to the interval",
+			spots: []testSpot{
+				// a week late on a long interval is less urgent than a day late on a short one
+				{id: "long", stage: "interleave_days", skipDays: 20, daysAgo: 28},
+				{id: "short", stage: "interleave_days", skipDays: 1, daysAgo: 3},
+			},
+			maxInfrequent: 5,
+			want:          []string{"short", "long"},
+		},
+		{
+			name: "spots that aren't due are left out",
+			spots: []testSpot{
+				{id: "due", stage: "interleave_days", skipDays: 2, daysAgo: 3},
+				{id: "early", stage: "interleave_days", skipDays: 5, daysAgo: 3},
+			},
+			maxInfrequent: 5,
+			want:          []string{"due"},
+		},
+		{
+			name: "high priority spots move up",
+			spots: []testSpot{
+				{id: "normal", stage: "interleave_days", skipDays: 1, daysAgo: 3},
+				{id: "high", stage: "interleave_days", skipDays: 1, daysAgo: 2, priority: -1},
+				{id: "low", stage: "interleave_days", skipDays: 1, daysAgo: 4, priority: 1},
+			},
+			maxInfrequent: 5,
+			want:          []string{"high", "normal", "low"},
+		},
+		{
+			name: "max infrequent spots",
+			spots: []testSpot{
+				{id: "i1", stage: "interleave_days", skipDays: 1, daysAgo: 2},
+				{id: "i2", stage: "interleave_days", skipDays: 1, daysAgo: 6},
+				{id: "i3", stage: "interleave_days", skipDays: 1, daysAgo: 4},
+			},
+			maxInfrequent: 2,
+			want:          []string{"i2", "i3"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// the order shouldn't depend on the seed at all
+			for seed := int64(1); seed <= 3; seed++ {
+				plan := planner.New(seed).Generate(planner.Input{
+					Settings: planner.Settings{MaxInfrequentSpots: tt.maxInfrequent, PracticeInterleave: true},
+					Pieces:   []planner.Piece{testPiece("p1", tt.spots...)},
+					Now:      now,
+				})
+				if !slices.Equal(plan.InfrequentSpotIDs, tt.want) {
+					t.Errorf("seed %d: infrequent spots = %v, want %v", seed, plan.InfrequentSpotIDs, tt.want)
+				}
+			}
+		})
+	}
+}
+
+func TestSameSeedSamePlan(t *testing.T) {
+	input := planner.Input{
+		Settings: planner.Settings{MaxNewSpots: 5, MaxInterleaveSpots: 5, PracticeNew: true, PracticeInterleave: true},
+		Pieces: []planner.Piece{
+			testPiece("p1", append(spots("n", "repeat", 10), spots("l", "interleave", 10)...)...),
+		},
+		Now: now,
+	}
+	first := planner.New(42).Generate(input)
+	second := planner.New(42).Generate(input)
+	if !slices.Equal(first.NewSpotIDs, second.NewSpotIDs) || !slices.Equal(first.InterleaveSpotIDs, second.InterleaveSpotIDs) {
+		t.Errorf("plans with the same seed are different: %v %v, %v %v", first.NewSpotIDs, first.InterleaveSpotIDs, second.NewSpotIDs, second.InterleaveSpotIDs)
+	}
+}
+
+func TestTimeBudget(t *testing.T) {
+	estimates := planner.Estimates{NewSpot: 5 * time.Minute, ExtraRepeatSpot: 2 * time.Minute, InterleaveSpot: time.Minute}
+	plan := planner.New(1).Generate(planner.Input{
+		Settings: planner.Settings{
+			PracticeNew:        true,
+			PracticeInterleave: true,
+			TimeBudget:         15 * time.Minute,
+			Estimates:          estimates,
+		},
+		Pieces: []planner.Piece{
+			testPiece("p1", append(append(spots("n", "repeat", 4), spots("x", "extra_repeat", 4)...), spots("l", "interleave", 4)...)...),
+		},
+		Now: now,
+	})
+	// one of each kind at a time: 2+5+1 for the first round, then 2+5 uses up the rest
+	if len(plan.ExtraRepeatSpotIDs) != 2 || len(plan.NewSpotIDs) != 2 || len(plan.InterleaveSpotIDs) != 1 {
+		t.Errorf("kept %d extra repeat, %d new and %d interleave spots, want 2, 2 and 1",
+			len(plan.ExtraRepeatSpotIDs), len(plan.NewSpotIDs), len(plan.InterleaveSpotIDs))
+	}
+	if plan.EstimatedDuration != 15*time.Minute {
+		t.Errorf("estimated duration = %v, want 15m", plan.EstimatedDuration)
+	}
+}
+
+func TestPriorityWeight(t *testing.T) {
+	tests := []struct {
+		priority int64
+		want     float64
+	}{
+		{-2, 4},
+		{-1, 2},
+		{0, 1},
+		{1, 0.5},
+		{2, 0.25},
+	}
+	for _, tt := range tests {
+		if got := planner.PriorityWeight(tt.priority); got != tt.want {
+			t.Errorf("PriorityWeight(%d) = %v, want %v", tt.priority, got, tt.want)
+		}
+	}
+}
+
+func TestWeightedSelection(t *testing.T) {
+	// over many plans a high priority spot should be picked first far more often than a low one
+	input := planner.Input{
+		Settings: planner.Settings{MaxNewSpots: 1, PracticeNew: true},
+		Pieces: []planner.Piece{testPiece("p1",
+			testSpot{id: "high", stage: "repeat", priority: -2},
+			testSpot{id: "low", stage: "repeat", priority: 2},
+		)},
+		Now: now,
+	}
+	high := 0
+	for seed := int64(0); seed < 500; seed++ {
+		if planner.New(seed).Generate(input).NewSpotIDs[0] == "high" {
+			high++
+		}
+	}
+	// the weights are 4 and 1/4, so high should win about 16 out of 17 times
+	if high < 430 {
+		t.Errorf("high priority spot picked %d out of 500 times", high)
+	}
+}
+
+func TestEstimatesWithHistory(t *testing.T) {
+	estimates := planner.DefaultEstimates().WithHistory([]db.ListUserPracticeSessionAveragesRow{
+		{PracticeType: "repeat", AverageMinutes: 3, SessionCount: config.ESTIMATE_MIN_SESSIONS},
+		{PracticeType: "random_spots", AverageMinutes: 20, SessionCount: config.ESTIMATE_MIN_SESSIONS - 1},
+		{PracticeType: "starting_point", AverageMinutes: 7.5, SessionCount: 10},
+		// these aren't timed, a stray session shouldn't change the estimate
+		{PracticeType: "interleave", AverageMinutes: 10, SessionCount: 10},
+	})
+	want := planner.DefaultEstimates()
+	want.NewSpot = 3 * time.Minute
+	want.StartingPointPiece = 7*time.Minute + 30*time.Second
+	if estimates != want {
+		t.Errorf("estimates = %+v, want %+v", estimates, want)
+	}
+}
@@ -0,0 +1,27 @@
+package planner
+
+import (
+	"cmp"
+	"math"
+	"slices"
+)
+
+// PriorityWeight is how likely a spot is to be chosen compared to a normal priority spot. Lower
+// numbers are higher priority (see ListHighPrioritySpots) and each step doubles the weight, so
+// priority -2 is four times as likely and 2 is a quarter as likely.
+func PriorityWeight(priority int64) float64 {
+	return math.Pow(2, float64(-priority))
+}
+
+// weightedShuffle randomly orders the spots so that higher priority spots tend to come first.
+// Each spot gets a key of u^(1/weight) for a random u between 0 and 1 and the spots are sorted by
+// key, which is the same as repeatedly drawing spots with a probability proportional to their weight.
+func (p *Planner) weightedShuffle(spotIDs []string, priorities map[string]int64) {
+	keys := make(map[string]float64, len(spotIDs))
+	for _, spotID := range spotIDs {
+		keys[spotID] = math.Pow(p.rng.Float64(), 1/PriorityWeight(priorities[spotID]))
+	}
+	slices.SortStableFunc(spotIDs, func(a, b string) int {
+		return cmp.Compare(keys[b], keys[a])
+	})
+}
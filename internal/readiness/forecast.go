@@ -0,0 +1,162 @@
+package readiness
+
+import (
+	"math"
+	"practicebetter/internal/activity"
+	"practicebetter/internal/stages"
+	"slices"
+	"sort"
+	"time"
+)
+
+// Spot is a spot as it is now. Since is the earliest time the spot is known to exist, spots don't
+// keep a creation date so this is when its stage started or its first stage change.
+type Spot struct {
+	ID    string
+	Stage string
+	Since int64
+}
+
+// Change is a stage_change event
+type Change struct {
+	SpotID string
+	From   string
+	To     string
+	Date   int64
+}
+
+// Day is how many spots were in each stage at the end of a day, in the order of stages.All. Date is
+// midnight UTC on that day in the user's timezone.
+type Day struct {
+	Date   time.Time
+	Counts [6]int
+	// Steps is how many stages spots moved forward that day, less how many they moved back
+	Steps int
+}
+
+// Total is how many spots the piece had that day
+func (d Day) Total() int {
+	total := 0
+	for _, count := range d.Counts {
+		total += count
+	}
+	return total
+}
+
+// remaining is how many stage steps the spots still have to take to all be completed
+func (d Day) remaining() int {
+	remaining := 0
+	for i, count := range d.Counts {
+		remaining += (len(d.Counts) - 1 - i) * count
+	}
+	return remaining
+}
+
+func stageIndex(stage string) int {
+	return max(slices.Index(stages.All, stage), 0)
+}
+
+// Series replays the stage changes to find the stage counts at the end of every day from when the
+// first spot showed up through today. Each spot starts in the stage its first change moved it from,
+// or the stage it's in now if it has never changed.
+func Series(loc *time.Location, spots []Spot, changes []Change, now time.Time) []Day {
+	if len(spots) == 0 {
+		return nil
+	}
+	changes = slices.Clone(changes)
+	sort.SliceStable(changes, func(i, j int) bool {
+		return changes[i].Date < changes[j].Date
+	})
+
+	known := make(map[string]bool, len(spots))
+	current := make(map[string]int, len(spots))
+	starts := make(map[string]int64, len(spots))
+	for _, spot := range spots {
+		known[spot.ID] = true
+		current[spot.ID] = stageIndex(spot.Stage)
+		starts[spot.ID] = spot.Since
+	}
+	seen := make(map[string]bool, len(spots))
+	for _, change := range changes {
+		if !known[change.SpotID] || seen[change.SpotID] {
+			continue
+		}
+		seen[change.SpotID] = true
+		current[change.SpotID] = stageIndex(change.From)
+		starts[change.SpotID] = min(starts[change.SpotID], change.Date)
+	}
+
+	// spots waiting to be counted, by the day they show up
+	arrivals := make(map[int64][]string)
+	first := activity.DayNumber(now, loc)
+	for _, spot := range spots {
+		n := min(activity.DayNumber(time.Unix(starts[spot.ID], 0), loc), activity.DayNumber(now, loc))
+		arrivals[n] = append(arrivals[n], spot.ID)
+		first = min(first, n)
+	}
+
+	last := activity.DayNumber(now, loc)
+	days := make([]Day, 0, last-first+1)
+	var counts [6]int
+	next := 0
+	for n := first; n <= last; n++ {
+		for _, spotID := range arrivals[n] {
+			counts[current[spotID]]++
+		}
+		steps := 0
+		for ; next < len(changes) && activity.DayNumber(time.Unix(changes[next].Date, 0), loc) <= n; next++ {
+			change := changes[next]
+			if !known[change.SpotID] {
+				continue
+			}
+			to := stageIndex(change.To)
+			steps += to - current[change.SpotID]
+			counts[current[change.SpotID]]--
+			counts[to]++
+			current[change.SpotID] = to
+		}
+		days = append(days, Day{Date: activity.DayDate(n), Counts: counts, Steps: steps})
+	}
+	return days
+}
+
+// Forecast is when a piece's spots should all be completed if practice keeps going the way it has
+type Forecast struct {
+	Days []Day
+	// Remaining is how many stage steps the spots still have to take
+	Remaining int
+	// StepsPerDay is the average stage steps taken per day over the window, demotions count against it
+	StepsPerDay float64
+	// Projected is the day all spots should be completed, zero when they aren't making progress
+	Projected time.Time
+}
+
+// Done is whether every spot is already completed
+func (f Forecast) Done() bool {
+	return len(f.Days) > 0 && f.Remaining == 0
+}
+
+// Project estimates when every spot will be completed from how quickly spots moved through the stages
+// in the last window days. New spots don't count as progress, only promotions and demotions do.
+func Project(days []Day, window int) Forecast {
+	forecast := Forecast{Days: days}
+	if len(days) == 0 {
+		return forecast
+	}
+	today := days[len(days)-1]
+	forecast.Remaining = today.remaining()
+	window = min(window, len(days))
+	if forecast.Remaining == 0 || window < 1 {
+		return forecast
+	}
+	steps := 0
+	for _, day := range days[len(days)-window:] {
+		steps += day.Steps
+	}
+	forecast.StepsPerDay = float64(steps) / float64(window)
+	if forecast.StepsPerDay <= 0 {
+		return forecast
+	}
+	forecast.Projected = today.Date.AddDate(0, 0, int(math.Ceil(float64(forecast.Remaining)/forecast.StepsPerDay)))
+	return forecast
+}
@@ -0,0 +1,201 @@
+package readiness_test
+
+import (
+	"practicebetter/internal/readiness"
+	"practicebetter/internal/stages"
+	"testing"
+	"time"
+)
+
+var now = time.Date(2024, time.August, 14, 18, 0, 0, 0, time.UTC)
+
+func at(day int, hour int) int64 {
+	return time.Date(2024, time.August, day, hour, 0, 0, 0, time.UTC).Unix()
+}
+
+func date(day int) time.Time {
+	return time.Date(2024, time.August, day, 0, 0, 0, 0, time.UTC)
+}
+
+func checkDays(t *testing.T, got []readiness.Day, want []readiness.Day) {
+	t.Helper()
+	if len(got) != len(want) {
+		t.Fatalf("Series() has %d days, want %d: %+v", len(got), len(want), got)
+	}
+	for i := range want {
+		if !got[i].Date.Equal(want[i].Date) || got[i].Counts != want[i].Counts || got[i].Steps != want[i].Steps {
+			t.Errorf("Series() day %d = %+v, want %+v", i, got[i], want[i])
+		}
+	}
+}
+
+func TestSeries(t *testing.T) {
+	spots := []readiness.Spot{
+		{ID: "promoted", Stage: stages.Random, Since: at(12, 10)},
+		{ID: "unchanged", Stage: stages.Interleave, Since: at(13, 8)},
+		// spots without a known start are given the current time, their first change moves it back
+		{ID: "demoted", Stage: stages.Repeat, Since: now.Unix()},
+	}
+	// out of order, and with a change for a spot that has since been deleted
+	changes := []readiness.Change{
+		{SpotID: "promoted", From: stages.ExtraRepeat, To: stages.Random, Date: at(13, 9)},
+		{SpotID: "deleted", From: stages.Repeat, To: stages.Completed, Date: at(13, 10)},
+		{SpotID: "demoted", From: stages.Random, To: stages.Repeat, Date: at(14, 10)},
+		{SpotID: "promoted", From: stages.Repeat, To: stages.ExtraRepeat, Date: at(12, 12)},
+	}
+	checkDays(t, readiness.Series(time.UTC, spots, changes, now), []readiness.Day{
+		{Date: date(12), Counts: [6]int{0, 1, 0, 0, 0, 0}, Steps: 1},
+		{Date: date(13), Counts: [6]int{0, 0, 1, 1, 0, 0}, Steps: 1},
+		{Date: date(14), Counts: [6]int{1, 0, 1, 1, 0, 0}, Steps: -2},
+	})
+}
+
+func TestSeriesUnchangedSpots(t *testing.T) {
+	spots := []readiness.Spot{
+		{ID: "a", Stage: stages.Repeat, Since: at(13, 12)},
+		{ID: "b", Stage: stages.Completed, Since: at(13, 12)},
+		// a start after now is counted from today
+		{ID: "c", Stage: stages.Repeat, Since: now.Add(48 * time.Hour).Unix()},
+	}
+	checkDays(t, readiness.Series(time.UTC, spots, nil, now), []readiness.Day{
+		{Date: date(13), Counts: [6]int{1, 0, 0, 0, 0, 1}},
+		{Date: date(14), Counts: [6]int{2, 0, 0, 0, 0, 1}},
+	})
+}
+
+func TestSeriesTimezone(t *testing.T) {
+	// 02:00 UTC on the 14th is still the evening of the 13th five hours behind
+	loc := time.FixedZone("UTC-5", -5*60*60)
+	spots := []readiness.Spot{{ID: "a", Stage: stages.ExtraRepeat, Since: at(12, 12)}}
+	changes := []readiness.Change{{SpotID: "a", From: stages.Repeat, To: stages.ExtraRepeat, Date: at(14, 2)}}
+	checkDays(t, readiness.Series(loc, spots, changes, now), []readiness.Day{
+		{Date: date(12), Counts: [6]int{1, 0, 0, 0, 0, 0}},
+		{Date: date(13), Counts: [6]int{0, 1, 0, 0, 0, 0}, Steps: 1},
+		{Date: date(14), Counts: [6]int{0, 1, 0, 0, 0, 0}},
+	})
+}
+
+func TestSeriesNoSpots(t *testing.T) {
+	changes := []readiness.Change{{SpotID: "deleted", From: stages.Repeat, To: stages.Completed, Date: at(13, 10)}}
+	if days := readiness.Series(time.UTC, nil, changes, now); days != nil {
+		t.Errorf("Series() = %+v, want nil", days)
+	}
+}
+
+// steps makes one day for each step count ending on the 14th, the last day has counts
+func steps(counts [6]int, dailySteps ...int) []readiness.Day {
+	days := make([]readiness.Day, 0, len(dailySteps))
+	for i, s := range dailySteps {
+		days = append(days, readiness.Day{Date: date(14 - len(dailySteps) + 1 + i), Steps: s})
+	}
+	days[len(days)-1].Counts = counts
+	return days
+}
+
+func TestProject(t *testing.T) {
+	// ten steps left, two spots at the start
+	twoNew := [6]int{2, 0, 0, 0, 0, 0}
+	tests := []struct {
+		name          string
+		days          []readiness.Day
+		window        int
+		wantRemaining int
+		wantRate      float64
+		wantProjected time.Time
+		wantDone      bool
+	}{
+		{
+			name:   "no days",
+			days:   nil,
+			window: 28,
+		},
+		{
+			name:     "all completed",
+			days:     steps([6]int{0, 0, 0, 0, 0, 3}, 5, 0),
+			window:   28,
+			wantDone: true,
+		},
+		{
+			name:          "steady progress",
+			days:          steps(twoNew, 2, 2, 2, 2),
+			window:        4,
+			wantRemaining: 10,
+			wantRate:      2,
+			wantProjected: date(19),
+		},
+		{
+			name:          "partial days round up",
+			days:          steps(twoNew, 3),
+			window:        1,
+			wantRemaining: 10,
+			wantRate:      3,
+			wantProjected: date(18),
+		},
+		{
+			name:          "window longer than the history",
+			days:          steps(twoNew, 3, 1),
+			window:        28,
+			wantRemaining: 10,
+			wantRate:      2,
+			wantProjected: date(19),
+		},
+		{
+			name:          "only the window counts",
+			days:          steps(twoNew, 10, 0, 1, 1),
+			window:        2,
+			wantRemaining: 10,
+			wantRate:      1,
+			wantProjected: date(24),
+		},
+		{
+			name:          "demotions count against progress",
+			days:          steps(twoNew, 2, -1, 2, -1),
+			window:        4,
+			wantRemaining: 10,
+			wantRate:      0.5,
+			wantProjected: date(34),
+		},
+		{
+			name:          "spots that never changed",
+			days:          steps(twoNew, 0, 0, 0),
+			window:        28,
+			wantRemaining: 10,
+		},
+		{
+			name:          "more demotions than promotions",
+			days:          steps(twoNew, 1, -3),
+			window:        28,
+			wantRemaining: 10,
+			wantRate:      -1,
+		},
+		{
+			name:          "zero window",
+			days:          steps(twoNew, 2, 2),
+			window:        0,
+			wantRemaining: 10,
+		},
+		{
+			name:          "negative window",
+			days:          steps(twoNew, 2, 2),
+			window:        -3,
+			wantRemaining: 10,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			forecast := readiness.Project(tt.days, tt.window)
+			if forecast.Remaining != tt.wantRemaining {
+				t.Errorf("Remaining = %d, want %d", forecast.Remaining, tt.wantRemaining)
+			}
+			if forecast.StepsPerDay != tt.wantRate {
+				t.Errorf("StepsPerDay = %v, want %v", forecast.StepsPerDay, tt.wantRate)
+			}
+			if !forecast.Projected.Equal(tt.wantProjected) {
+				t.Errorf("Projected = %v, want %v", forecast.Projected, tt.wantProjected)
+			}
+			if forecast.Done() != tt.wantDone {
+				t.Errorf("Done() = %v, want %v", forecast.Done(), tt.wantDone)
+			}
+		})
+	}
+}
@@ -0,0 +1,33 @@
+package scheduler
+
+import "time"
+
+// classicScheduler doubles the skip days after excellent practicing, up to a week, and starts over
+// after poor practicing. This is how infrequent spots have always worked.
+type classicScheduler struct{}
+
+func (classicScheduler) Due(spot Spot, loc *time.Location) time.Time {
+	if spot.LastPracticed.IsZero() {
+		return time.Time{}
+	}
+	// the skip days are the whole days between practicing the spot
+	return dayStart(spot.LastPracticed, spot.SkipDays+1, loc)
+}
+
+func (classicScheduler) Review(spot Spot, evaluation string, now time.Time) Result {
+	timeInStage := spot.timeInStage(now)
+	switch {
+	// excellent and more than four days old and days is less than 7, double the skip time
+	case evaluation == "excellent" && timeInStage > 4*24*time.Hour && spot.SkipDays < 7:
+		return Result{SkipDays: spot.SkipDays * 2, Outcome: Keep}
+	// poor quality resets days to 1 or demotes immediately
+	case evaluation == "poor" && spot.SkipDays < 2:
+		return Result{SkipDays: spot.SkipDays, Outcome: Demote}
+	case evaluation == "poor":
+		return Result{SkipDays: 1, Outcome: Keep}
+	case evaluation == "excellent" && spot.SkipDays > 6 && timeInStage > 20*24*time.Hour:
+		return Result{SkipDays: spot.SkipDays, Outcome: Complete}
+	default:
+		return Result{SkipDays: spot.SkipDays, Outcome: Keep}
+	}
+}
@@ -0,0 +1,103 @@
+package scheduler
+
+import (
+	"database/sql"
+	"time"
+)
+
+const (
+	Classic = "classic"
+	SM2     = "sm2"
+)
+
+// Names are the schedulers a user can choose from, in the order they should be shown
+var Names = []string{Classic, SM2}
+
+// Review is one evaluation of an infrequent spot
+type Review struct {
+	Evaluation string
+	Date       time.Time
+}
+
+// Spot is everything a scheduler knows about an infrequent spot
+type Spot struct {
+	SkipDays int64
+	// zero if the spot has never been practiced
+	LastPracticed time.Time
+	// zero if the stage start is unknown
+	StageStarted time.Time
+	// evaluations since the spot became infrequent, oldest first
+	Reviews []Review
+}
+
+type Outcome int
+
+const (
+	Keep Outcome = iota
+	Demote
+	Complete
+)
+
+// Result is what should happen to a spot after it was practiced
+type Result struct {
+	SkipDays int64
+	Outcome  Outcome
+}
+
+type Scheduler interface {
+	// Due is the start of the day, in the user's timezone, when the spot should next be practiced
+	Due(spot Spot, loc *time.Location) time.Time
+	// Review decides the next interval for a spot that was just practiced, or whether it
+	// should leave the infrequent stage
+	Review(spot Spot, evaluation string, now time.Time) Result
+}
+
+// Get returns the scheduler with the given name, anything unknown gets the classic scheduler
+func Get(name string) Scheduler {
+	switch name {
+	case SM2:
+		return sm2Scheduler{}
+	default:
+		return classicScheduler{}
+	}
+}
+
+// Label is the name of the scheduler to show to the user
+func Label(name string) string {
+	switch name {
+	case SM2:
+		return "Adaptive (SM-2)"
+	default:
+		return "Classic"
+	}
+}
+
+// NewSpot builds the scheduler info for a spot from the nullable database columns
+func NewSpot(skipDays int64, lastPracticed sql.NullInt64, stageStarted sql.NullInt64, reviews []Review) Spot {
+	spot := Spot{
+		SkipDays: skipDays,
+		Reviews:  reviews,
+	}
+	if lastPracticed.Valid {
+		spot.LastPracticed = time.Unix(lastPracticed.Int64, 0)
+	}
+	if stageStarted.Valid {
+		spot.StageStarted = time.Unix(stageStarted.Int64, 0)
+	}
+	return spot
+}
+
+// timeInStage is how long the spot has been infrequent, zero if unknown
+func (s Spot) timeInStage(now time.Time) time.Duration {
+	if s.StageStarted.IsZero() {
+		return 0
+	}
+	return now.Sub(s.StageStarted)
+}
+
+// dayStart is midnight at the start of the day that comes this many days after t, in loc. Counting
+// in days means a spot practiced in the evening is due in the morning of the right day.
+func dayStart(t time.Time, days int64, loc *time.Location) time.Time {
+	y, m, d := t.In(loc).Date()
+	return time.Date(y, m, d+int(days), 0, 0, 0, 0, loc)
+}
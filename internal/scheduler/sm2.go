@@ -0,0 +1,100 @@
+package scheduler
+
+import (
+	"math"
+	"time"
+)
+
+const (
+	sm2StartingEasiness = 2.5
+	sm2MinEasiness      = 1.3
+	// spots that reach this interval with an excellent evaluation are completed
+	sm2CompleteInterval = 30
+	sm2MaxInterval      = 60
+)
+
+// sm2Scheduler is the SuperMemo 2 algorithm. The interval grows by an easiness factor that is
+// adjusted after every evaluation, so spots that keep going well are seen less and less often.
+type sm2Scheduler struct{}
+
+type sm2State struct {
+	easiness    float64
+	repetitions int
+	interval    int64
+}
+
+// sm2Quality maps the evaluations to the 0-5 scale from the original algorithm, anything below 3
+// is a failure
+func sm2Quality(evaluation string) float64 {
+	switch evaluation {
+	case "excellent":
+		return 5
+	case "fine":
+		return 4
+	default:
+		return 2
+	}
+}
+
+func (s sm2State) next(evaluation string) sm2State {
+	quality := sm2Quality(evaluation)
+	if quality < 3 {
+		s.repetitions = 0
+		s.interval = 1
+	} else {
+		switch s.repetitions {
+		case 0:
+			s.interval = 1
+		case 1:
+			s.interval = 6
+		default:
+			s.interval = int64(math.Round(float64(s.interval) * s.easiness))
+		}
+		s.repetitions++
+	}
+	s.interval = min(s.interval, sm2MaxInterval)
+	s.easiness = max(sm2MinEasiness, s.easiness+0.1-(5-quality)*(0.08+(5-quality)*0.02))
+	return s
+}
+
+// replay runs the spot's history through the algorithm to find its easiness and repetitions. The
+// interval is the skip days the spot already has, which is where the last review left it or the
+// classic interval from before switching schedulers, so a long interval isn't started over at a day.
+func (sm2Scheduler) replay(spot Spot) sm2State {
+	state := sm2State{
+		easiness: sm2StartingEasiness,
+	}
+	for _, review := range spot.Reviews {
+		state = state.next(review.Evaluation)
+	}
+	state.interval = max(spot.SkipDays, 1)
+	// an interval that is already past the first steps keeps growing from where it is
+	switch {
+	case state.interval >= 6:
+		state.repetitions = max(state.repetitions, 2)
+	case state.interval > 1:
+		state.repetitions = max(state.repetitions, 1)
+	}
+	return state
+}
+
+func (s sm2Scheduler) Due(spot Spot, loc *time.Location) time.Time {
+	if spot.LastPracticed.IsZero() {
+		return time.Time{}
+	}
+	return dayStart(spot.LastPracticed, s.replay(spot).interval, loc)
+}
+
+func (s sm2Scheduler) Review(spot Spot, evaluation string, now time.Time) Result {
+	before := s.replay(spot)
+	after := before.next(evaluation)
+	switch {
+	// failing at the shortest interval means the spot needs more regular practice
+	case evaluation == "poor" && before.interval <= 1:
+		return Result{SkipDays: spot.SkipDays, Outcome: Demote}
+	case evaluation == "excellent" && after.interval >= sm2CompleteInterval && spot.timeInStage(now) > 20*24*time.Hour:
+		return Result{SkipDays: spot.SkipDays, Outcome: Complete}
+	default:
+		return Result{SkipDays: after.interval, Outcome: Keep}
+	}
+}
@@ -0,0 +1,274 @@
+package scoreimport
+
+import (
+	"bytes"
+	"cmp"
+	"encoding/binary"
+	"fmt"
+	"math"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// IsMIDI reports whether the data looks like a Standard MIDI File
+func IsMIDI(data []byte) bool {
+	return bytes.HasPrefix(data, []byte("MThd"))
+}
+
+type midiEvent struct {
+	tick int64
+	// the meta event type, only tempo, time signature, key signature and track name are kept
+	kind byte
+	data []byte
+}
+
+const (
+	midiTrackName     byte = 0x03
+	midiTempo         byte = 0x51
+	midiTimeSignature byte = 0x58
+	midiKeySignature  byte = 0x59
+)
+
+// meterChange is a time signature starting at a tick
+type meterChange struct {
+	tick        int64
+	beats       int
+	beatType    int
+	ticksPerBar int64
+}
+
+// ParseMIDI reads the tempo, meter and key from a Standard MIDI File and counts its bars. Every
+// change of tempo or meter after the start becomes a mark, so spots can begin there. Files with more
+// than maxMeasures bars return ErrTooLarge.
+func ParseMIDI(data []byte, maxMeasures int) (Score, error) {
+	if !IsMIDI(data) || len(data) < 14 {
+		return Score{}, fmt.Errorf("%w: not a midi file", ErrUnsupportedFile)
+	}
+	headerLength := int(binary.BigEndian.Uint32(data[4:8]))
+	if headerLength < 6 || 8+headerLength > len(data) {
+		return Score{}, fmt.Errorf("%w: bad midi header", ErrUnsupportedFile)
+	}
+	division := int64(binary.BigEndian.Uint16(data[12:14]))
+	if division&0x8000 != 0 || division == 0 {
+		return Score{}, fmt.Errorf("%w: timecode based midi files are not supported", ErrUnsupportedFile)
+	}
+
+	var events []midiEvent
+	var endTick int64
+	title := ""
+	pos := 8 + headerLength
+	for firstTrack := true; pos+8 <= len(data); {
+		chunkType := string(data[pos : pos+4])
+		length := int(binary.BigEndian.Uint32(data[pos+4 : pos+8]))
+		pos += 8
+		if length < 0 || pos+length > len(data) {
+			return Score{}, fmt.Errorf("%w: truncated midi chunk", ErrUnsupportedFile)
+		}
+		if chunkType == "MTrk" {
+			trackEvents, trackEnd, err := readMIDITrack(data[pos : pos+length])
+			if err != nil {
+				return Score{}, err
+			}
+			for _, event := range trackEvents {
+				// the first track's name is the name of the song
+				if event.kind == midiTrackName {
+					if firstTrack && title == "" {
+						title = strings.TrimSpace(string(event.data))
+					}
+					continue
+				}
+				events = append(events, event)
+			}
+			endTick = max(endTick, trackEnd)
+			firstTrack = false
+		}
+		pos += length
+	}
+	slices.SortStableFunc(events, func(a, b midiEvent) int {
+		return cmp.Compare(a.tick, b.tick)
+	})
+
+	score := Score{Title: title}
+	meters := []meterChange{{tick: 0, beats: 4, beatType: 4, ticksPerBar: division * 4}}
+	type change struct {
+		tick  int64
+		label string
+	}
+	var changes []change
+	keySet := false
+	for _, event := range events {
+		switch event.kind {
+		case midiTempo:
+			if len(event.data) != 3 {
+				continue
+			}
+			microseconds := int64(event.data[0])<<16 | int64(event.data[1])<<8 | int64(event.data[2])
+			if microseconds == 0 {
+				continue
+			}
+			bpm := int(math.Round(60_000_000 / float64(microseconds)))
+			if score.Tempo == 0 {
+				score.Tempo = bpm
+			}
+			if event.tick > 0 {
+				changes = append(changes, change{event.tick, "♩ = " + strconv.Itoa(bpm)})
+			}
+		case midiTimeSignature:
+			if len(event.data) < 2 || event.data[0] == 0 || event.data[1] > 6 {
+				continue
+			}
+			meter := meterChange{
+				tick:     event.tick,
+				beats:    int(event.data[0]),
+				beatType: 1 << event.data[1],
+			}
+			meter.ticksPerBar = division * 4 * int64(meter.beats) / int64(meter.beatType)
+			if meter.ticksPerBar < 1 {
+				continue
+			}
+			if event.tick == 0 {
+				meters[0] = meter
+			} else {
+				meters = append(meters, meter)
+				changes = append(changes, change{event.tick, strconv.Itoa(meter.beats) + "/" + strconv.Itoa(meter.beatType)})
+			}
+		case midiKeySignature:
+			if keySet || len(event.data) != 2 {
+				continue
+			}
+			mode := "Major (Ionian)"
+			if event.data[1] == 1 {
+				mode = "Minor (Aeolian)"
+			}
+			score.Key = keyFromFifths(int(int8(event.data[0])), mode)
+			score.Mode = mode
+			keySet = true
+		}
+	}
+	score.BeatsPerMeasure = meters[0].beats
+
+	// a new meter always starts a new bar, so each meter's bars are counted separately and rounded up
+	starts := make([]int, len(meters))
+	bars := 0
+	for i, meter := range meters {
+		starts[i] = bars + 1
+		end := endTick
+		if i+1 < len(meters) {
+			end = meters[i+1].tick
+		}
+		if end > meter.tick {
+			bars += int((end - meter.tick + meter.ticksPerBar - 1) / meter.ticksPerBar)
+		}
+		// a tiny division with long gaps between events can add up to millions of bars
+		if bars > maxMeasures {
+			return Score{}, fmt.Errorf("%w: more than %d measures", ErrTooLarge, maxMeasures)
+		}
+	}
+	score.Measures = bars
+
+	measureAt := func(tick int64) int {
+		i := len(meters) - 1
+		for i > 0 && meters[i].tick > tick {
+			i--
+		}
+		return starts[i] + int((tick-meters[i].tick)/meters[i].ticksPerBar)
+	}
+	for _, change := range changes {
+		measure := measureAt(change.tick)
+		if measure <= 1 || measure > score.Measures {
+			continue
+		}
+		// a tempo and meter change in the same bar make one mark
+		if last := len(score.Marks) - 1; last >= 0 && score.Marks[last].Measure == measure {
+			score.Marks[last].Label += ", " + change.label
+			continue
+		}
+		score.Marks = append(score.Marks, Mark{Measure: measure, Label: "m. " + strconv.Itoa(measure) + ": " + change.label})
+	}
+	return score, nil
+}
+
+// readMIDITrack returns the meta events in a track and the tick of its last event
+func readMIDITrack(track []byte) ([]midiEvent, int64, error) {
+	truncated := fmt.Errorf("%w: truncated midi track", ErrUnsupportedFile)
+	var events []midiEvent
+	var tick int64
+	var status byte
+	pos := 0
+
+	readVarInt := func() (int64, bool) {
+		var value int64
+		for i := 0; i < 4; i++ {
+			if pos >= len(track) {
+				return 0, false
+			}
+			b := track[pos]
+			pos++
+			value = value<<7 | int64(b&0x7f)
+			if b&0x80 == 0 {
+				return value, true
+			}
+		}
+		return 0, false
+	}
+
+	for pos < len(track) {
+		delta, ok := readVarInt()
+		if !ok {
+			return nil, 0, truncated
+		}
+		tick += delta
+		if pos >= len(track) {
+			return nil, 0, truncated
+		}
+
+		b := track[pos]
+		switch {
+		case b == 0xff:
+			pos++
+			if pos >= len(track) {
+				return nil, 0, truncated
+			}
+			kind := track[pos]
+			pos++
+			length, ok := readVarInt()
+			if !ok || int64(pos)+length > int64(len(track)) {
+				return nil, 0, truncated
+			}
+			switch kind {
+			case midiTrackName, midiTempo, midiTimeSignature, midiKeySignature:
+				events = append(events, midiEvent{tick: tick, kind: kind, data: track[pos : pos+int(length)]})
+			}
+			pos += int(length)
+			// end of track
+			if kind == 0x2f {
+				return events, tick, nil
+			}
+		case b == 0xf0 || b == 0xf7:
+			pos++
+			length, ok := readVarInt()
+			if !ok || int64(pos)+length > int64(len(track)) {
+				return nil, 0, truncated
+			}
+			pos += int(length)
+		default:
+			// channel messages can leave out the status byte if it's the same as the last one
+			if b&0x80 != 0 {
+				status = b
+				pos++
+			} else if status == 0 {
+				return nil, 0, fmt.Errorf("%w: bad midi event", ErrUnsupportedFile)
+			}
+			dataBytes := 2
+			if kind := status & 0xf0; kind == 0xc0 || kind == 0xd0 {
+				dataBytes = 1
+			}
+			if pos+dataBytes > len(track) {
+				return nil, 0, truncated
+			}
+			pos += dataBytes
+		}
+	}
+	return events, tick, nil
+}
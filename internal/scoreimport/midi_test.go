@@ -0,0 +1,159 @@
+package scoreimport_test
+
+import (
+	"encoding/binary"
+	"errors"
+	"practicebetter/internal/scoreimport"
+	"reflect"
+	"testing"
+)
+
+// varInt encodes a midi variable length number
+func varInt(value int) []byte {
+	out := []byte{byte(value & 0x7f)}
+	for value >>= 7; value > 0; value >>= 7 {
+		out = append([]byte{byte(value&0x7f) | 0x80}, out...)
+	}
+	return out
+}
+
+func meta(delta int, kind byte, data ...byte) []byte {
+	event := append(varInt(delta), 0xff, kind)
+	event = append(event, varInt(len(data))...)
+	return append(event, data...)
+}
+
+func endOfTrack(delta int) []byte {
+	return meta(delta, 0x2f)
+}
+
+func tempo(delta int, bpm int) []byte {
+	microseconds := 60_000_000 / bpm
+	return meta(delta, 0x51, byte(microseconds>>16), byte(microseconds>>8), byte(microseconds))
+}
+
+func track(events ...[]byte) []byte {
+	var data []byte
+	for _, event := range events {
+		data = append(data, event...)
+	}
+	chunk := append([]byte("MTrk"), binary.BigEndian.AppendUint32(nil, uint32(len(data)))...)
+	return append(chunk, data...)
+}
+
+func midiFile(division uint16, tracks ...[]byte) []byte {
+	data := append([]byte("MThd"), 0, 0, 0, 6, 0, 1)
+	data = binary.BigEndian.AppendUint16(data, uint16(len(tracks)))
+	data = binary.BigEndian.AppendUint16(data, division)
+	for _, t := range tracks {
+		data = append(data, t...)
+	}
+	return data
+}
+
+func TestParseMIDI(t *testing.T) {
+	const bar34 = 480 * 3
+	const bar44 = 480 * 4
+	tests := []struct {
+		name      string
+		data      []byte
+		wantScore scoreimport.Score
+	}{
+		{
+			name: "tempo change",
+			data: midiFile(480, track(
+				meta(0, 0x03, []byte("Etude")...),
+				tempo(0, 120),
+				meta(0, 0x58, 3, 2, 24, 8),
+				meta(0, 0x59, 0xff, 1),
+				tempo(bar34*4, 100),
+				endOfTrack(bar34*4),
+			)),
+			wantScore: scoreimport.Score{
+				Title:           "Etude",
+				Measures:        8,
+				BeatsPerMeasure: 3,
+				Tempo:           120,
+				Key:             "D",
+				Mode:            "Minor (Aeolian)",
+				Marks:           []scoreimport.Mark{{Measure: 5, Label: "m. 5: ♩ = 100"}},
+			},
+		},
+		{
+			name: "meter and tempo change in one bar",
+			data: midiFile(480,
+				track(tempo(0, 60), endOfTrack(0)),
+				track(
+					meta(bar44*2, 0x58, 3, 2, 24, 8),
+					tempo(0, 90),
+					endOfTrack(bar34*2),
+				),
+			),
+			wantScore: scoreimport.Score{
+				Measures:        4,
+				BeatsPerMeasure: 4,
+				Tempo:           60,
+				Marks:           []scoreimport.Mark{{Measure: 3, Label: "m. 3: 3/4, ♩ = 90"}},
+			},
+		},
+		{
+			name: "partial last bar",
+			data: midiFile(96, track(endOfTrack(96*4*2+1))),
+			wantScore: scoreimport.Score{
+				Measures:        3,
+				BeatsPerMeasure: 4,
+			},
+		},
+		{
+			name:      "no tracks",
+			data:      midiFile(96),
+			wantScore: scoreimport.Score{BeatsPerMeasure: 4},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			score, err := scoreimport.ParseMIDI(tt.data, 100)
+			if err != nil {
+				t.Fatalf("ParseMIDI() error = %v", err)
+			}
+			if !reflect.DeepEqual(score, tt.wantScore) {
+				t.Errorf("ParseMIDI() = %+v, want %+v", score, tt.wantScore)
+			}
+		})
+	}
+}
+
+func TestParseMIDIBadFiles(t *testing.T) {
+	// the largest delta a midi file can hold, with a division of 1 each one is millions of bars
+	const maxDelta = 0x0fffffff
+	valid := midiFile(480, track(endOfTrack(480*4)))
+	tests := []struct {
+		name    string
+		data    []byte
+		wantErr error
+	}{
+		{"empty", nil, scoreimport.ErrUnsupportedFile},
+		{"not midi", []byte("RIFF\x00\x00\x00\x04WAVE"), scoreimport.ErrUnsupportedFile},
+		{"short header", valid[:12], scoreimport.ErrUnsupportedFile},
+		{"header longer than file", append([]byte("MThd\x7f\xff\xff\xff"), valid[8:]...), scoreimport.ErrUnsupportedFile},
+		{"timecode division", midiFile(0xe728, track(endOfTrack(0))), scoreimport.ErrUnsupportedFile},
+		{"zero division", midiFile(0, track(endOfTrack(0))), scoreimport.ErrUnsupportedFile},
+		{"truncated chunk", valid[:len(valid)-1], scoreimport.ErrUnsupportedFile},
+		{"truncated delta", midiFile(480, track([]byte{0x80, 0x80})), scoreimport.ErrUnsupportedFile},
+		{"delta longer than four bytes", midiFile(480, track([]byte{0x80, 0x80, 0x80, 0x80, 0x00})), scoreimport.ErrUnsupportedFile},
+		{"meta length past the track", midiFile(480, track([]byte{0x00, 0xff, 0x51, 0x7f, 0x07})), scoreimport.ErrUnsupportedFile},
+		{"running status with no status", midiFile(480, track([]byte{0x00, 0x40, 0x40})), scoreimport.ErrUnsupportedFile},
+		{"huge delta", midiFile(1, track(endOfTrack(maxDelta))), scoreimport.ErrTooLarge},
+		{"many huge deltas", midiFile(1, track(tempo(maxDelta, 120), tempo(maxDelta, 90), endOfTrack(maxDelta))), scoreimport.ErrTooLarge},
+		{"huge delta in a later meter", midiFile(1, track(meta(4, 0x58, 1, 0), endOfTrack(maxDelta))), scoreimport.ErrTooLarge},
+		{"one bar over the limit", midiFile(480, track(endOfTrack(480*4*100+1))), scoreimport.ErrTooLarge},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := scoreimport.ParseMIDI(tt.data, 100)
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("ParseMIDI() error = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,282 @@
+package scoreimport
+
+import (
+	"archive/zip"
+	"bytes"
+	"cmp"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"path"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+type xmlScore struct {
+	XMLName       xml.Name
+	WorkTitle     string `xml:"work>work-title"`
+	MovementTitle string `xml:"movement-title"`
+	Creators      []struct {
+		Type string `xml:"type,attr"`
+		Name string `xml:",chardata"`
+	} `xml:"identification>creator"`
+	Credits []struct {
+		Types []string `xml:"credit-type"`
+		Words []string `xml:"credit-words"`
+	} `xml:"credit"`
+	Parts []struct {
+		Measures []xmlMeasure `xml:"measure"`
+	} `xml:"part"`
+}
+
+type xmlMeasure struct {
+	Implicit   string `xml:"implicit,attr"`
+	Attributes []struct {
+		Keys []struct {
+			Fifths *int   `xml:"fifths"`
+			Mode   string `xml:"mode"`
+		} `xml:"key"`
+		Times []struct {
+			Beats []string `xml:"beats"`
+		} `xml:"time"`
+	} `xml:"attributes"`
+	Directions []struct {
+		Types []struct {
+			Rehearsals []string `xml:"rehearsal"`
+			Metronome  *struct {
+				PerMinute string `xml:"per-minute"`
+			} `xml:"metronome"`
+		} `xml:"direction-type"`
+		Sound *xmlSound `xml:"sound"`
+	} `xml:"direction"`
+	Sounds []xmlSound `xml:"sound"`
+}
+
+type xmlSound struct {
+	Tempo string `xml:"tempo,attr"`
+}
+
+type xmlContainer struct {
+	Rootfiles []struct {
+		FullPath  string `xml:"full-path,attr"`
+		MediaType string `xml:"media-type,attr"`
+	} `xml:"rootfiles>rootfile"`
+}
+
+// ParseMusicXML reads an uncompressed .musicxml file or a compressed .mxl file. Only partwise
+// scores are supported, which is what notation programs export. Scores with more than maxMeasures
+// measures return ErrTooLarge.
+func ParseMusicXML(data []byte, maxSize int64, maxMeasures int) (Score, error) {
+	if bytes.HasPrefix(data, []byte("PK")) {
+		var err error
+		data, err = readMXL(data, maxSize)
+		if err != nil {
+			return Score{}, err
+		}
+	}
+
+	var doc xmlScore
+	decoder := xml.NewDecoder(bytes.NewReader(data))
+	decoder.Strict = false
+	decoder.Entity = xml.HTMLEntity
+	if err := decoder.Decode(&doc); err != nil {
+		return Score{}, fmt.Errorf("%w: %w", ErrUnsupportedFile, err)
+	}
+	if doc.XMLName.Local != "score-partwise" {
+		return Score{}, fmt.Errorf("%w: %s is not supported", ErrUnsupportedFile, doc.XMLName.Local)
+	}
+	score := doc.score()
+	if score.Measures > maxMeasures {
+		return Score{}, fmt.Errorf("%w: more than %d measures", ErrTooLarge, maxMeasures)
+	}
+	return score, nil
+}
+
+// readMXL finds the main score in a compressed MusicXML archive
+func readMXL(data []byte, maxSize int64) ([]byte, error) {
+	archive, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrUnsupportedFile, err)
+	}
+
+	rootPath := ""
+	if container, err := readZipFile(archive, "META-INF/container.xml", maxSize); err == nil {
+		var c xmlContainer
+		if err := xml.Unmarshal(container, &c); err == nil {
+			for _, rootfile := range c.Rootfiles {
+				if rootfile.MediaType == "" || strings.Contains(rootfile.MediaType, "musicxml") {
+					rootPath = rootfile.FullPath
+					break
+				}
+			}
+		}
+	}
+	// older archives don't always have a container, so use the first score file
+	if rootPath == "" {
+		for _, file := range archive.File {
+			ext := path.Ext(file.Name)
+			if !strings.HasPrefix(file.Name, "META-INF/") && (ext == ".xml" || ext == ".musicxml") {
+				rootPath = file.Name
+				break
+			}
+		}
+	}
+	if rootPath == "" {
+		return nil, fmt.Errorf("%w: no score in archive", ErrUnsupportedFile)
+	}
+	return readZipFile(archive, rootPath, maxSize)
+}
+
+func readZipFile(archive *zip.Reader, name string, maxSize int64) ([]byte, error) {
+	file, err := archive.Open(name)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrUnsupportedFile, err)
+	}
+	defer file.Close()
+	// the uncompressed size in the archive can't be trusted
+	data, err := io.ReadAll(io.LimitReader(file, maxSize+1))
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrUnsupportedFile, err)
+	}
+	if int64(len(data)) > maxSize {
+		return nil, ErrTooLarge
+	}
+	return data, nil
+}
+
+func (doc xmlScore) score() Score {
+	score := Score{
+		Title: strings.TrimSpace(doc.WorkTitle),
+	}
+	if score.Title == "" {
+		score.Title = strings.TrimSpace(doc.MovementTitle)
+	}
+	for _, creator := range doc.Creators {
+		if creator.Type == "composer" {
+			score.Composer = strings.TrimSpace(creator.Name)
+			break
+		}
+	}
+	// some programs only put the title and composer on the printed page
+	for _, credit := range doc.Credits {
+		if len(credit.Words) == 0 {
+			continue
+		}
+		words := strings.TrimSpace(credit.Words[0])
+		for _, creditType := range credit.Types {
+			if creditType == "title" && score.Title == "" {
+				score.Title = words
+			} else if creditType == "composer" && score.Composer == "" {
+				score.Composer = words
+			}
+		}
+	}
+	if len(doc.Parts) == 0 {
+		return score
+	}
+
+	// every part has the same measures, so the first part is used for the structure. Rehearsal
+	// marks and tempos are sometimes only in other parts though.
+	keySet := false
+	for _, measure := range doc.Parts[0].Measures {
+		if measure.Implicit != "yes" {
+			score.Measures++
+		}
+		for _, attributes := range measure.Attributes {
+			for _, key := range attributes.Keys {
+				if !keySet && key.Fifths != nil {
+					score.Mode = modeName(key.Mode)
+					score.Key = keyFromFifths(*key.Fifths, score.Mode)
+					keySet = true
+				}
+			}
+			for _, time := range attributes.Times {
+				if score.BeatsPerMeasure == 0 && len(time.Beats) > 0 {
+					score.BeatsPerMeasure = sumBeats(time.Beats[0])
+				}
+			}
+		}
+	}
+
+	markMeasures := make(map[int]bool)
+	for _, part := range doc.Parts {
+		// pickups aren't counted, so marks in them belong to the next full measure
+		number := 1
+		for _, measure := range part.Measures {
+			for _, direction := range measure.Directions {
+				for _, directionType := range direction.Types {
+					for _, rehearsal := range directionType.Rehearsals {
+						label := strings.TrimSpace(rehearsal)
+						if label != "" && !markMeasures[number] {
+							markMeasures[number] = true
+							score.Marks = append(score.Marks, Mark{Measure: number, Label: label})
+						}
+					}
+					if score.Tempo == 0 && directionType.Metronome != nil {
+						score.Tempo = parseTempo(directionType.Metronome.PerMinute)
+					}
+				}
+				if score.Tempo == 0 && direction.Sound != nil {
+					score.Tempo = parseTempo(direction.Sound.Tempo)
+				}
+			}
+			for _, sound := range measure.Sounds {
+				if score.Tempo == 0 {
+					score.Tempo = parseTempo(sound.Tempo)
+				}
+			}
+			if measure.Implicit != "yes" {
+				number++
+			}
+		}
+	}
+	// parts are read one after another, so marks from later parts can be out of order
+	slices.SortStableFunc(score.Marks, func(a, b Mark) int {
+		return cmp.Compare(a.Measure, b.Measure)
+	})
+	return score
+}
+
+// modeName turns a MusicXML mode into the name in scale_modes, files without a mode are major
+func modeName(mode string) string {
+	switch strings.ToLower(strings.TrimSpace(mode)) {
+	case "", "major", "ionian":
+		return "Major (Ionian)"
+	case "minor", "aeolian":
+		return "Minor (Aeolian)"
+	case "dorian":
+		return "Dorian"
+	case "phrygian":
+		return "Phrygian"
+	case "lydian":
+		return "Lydian"
+	case "mixolydian":
+		return "Mixolydian"
+	case "locrian":
+		return "Locrian"
+	default:
+		return ""
+	}
+}
+
+// sumBeats reads the top of a time signature, including compound ones like 3+2
+func sumBeats(beats string) int {
+	total := 0
+	for _, part := range strings.Split(beats, "+") {
+		n, err := strconv.Atoi(strings.TrimSpace(part))
+		if err != nil || n < 1 {
+			return 0
+		}
+		total += n
+	}
+	return total
+}
+
+func parseTempo(tempo string) int {
+	value, err := strconv.ParseFloat(strings.TrimSpace(tempo), 64)
+	if err != nil || value <= 0 {
+		return 0
+	}
+	return int(value + 0.5)
+}
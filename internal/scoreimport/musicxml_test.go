@@ -0,0 +1,169 @@
+package scoreimport_test
+
+import (
+	"archive/zip"
+	"bytes"
+	"errors"
+	"practicebetter/internal/scoreimport"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func partwise(parts ...string) string {
+	return `<?xml version="1.0" encoding="UTF-8"?>
+<score-partwise version="4.0">` + strings.Join(parts, "") + `</score-partwise>`
+}
+
+func part(measures ...string) string {
+	return `<part id="P1">` + strings.Join(measures, "") + `</part>`
+}
+
+// emptyMeasures makes count measures with nothing in them
+func emptyMeasures(count int) string {
+	return strings.Repeat(`<measure></measure>`, count)
+}
+
+func rehearsal(label string) string {
+	return `<measure><direction><direction-type><rehearsal>` + label + `</rehearsal></direction-type></direction></measure>`
+}
+
+func mxl(t *testing.T, files map[string]string) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	archive := zip.NewWriter(&buf)
+	for name, content := range files {
+		file, err := archive.Create(name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err := file.Write([]byte(content)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := archive.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+func TestParseMusicXML(t *testing.T) {
+	sonata := partwise(
+		`<work><work-title> Sonata </work-title></work>
+		<identification><creator type="lyricist">Someone</creator><creator type="composer">Composer</creator></identification>`,
+		part(
+			`<measure implicit="yes">
+				<attributes><key><fifths>2</fifths><mode>minor</mode></key><time><beats>3+2</beats></time></attributes>
+				<direction><direction-type><metronome><per-minute>71.6</per-minute></metronome></direction-type></direction>
+			</measure>`,
+			emptyMeasures(2),
+			rehearsal("A"),
+			emptyMeasures(3),
+		),
+		part(
+			emptyMeasures(5),
+			rehearsal("B"),
+		),
+	)
+	tests := []struct {
+		name      string
+		data      string
+		wantScore scoreimport.Score
+	}{
+		{
+			name: "normal file",
+			data: sonata,
+			wantScore: scoreimport.Score{
+				Title:           "Sonata",
+				Composer:        "Composer",
+				Measures:        6,
+				BeatsPerMeasure: 5,
+				Tempo:           72,
+				Key:             "B",
+				Mode:            "Minor (Aeolian)",
+				Marks:           []scoreimport.Mark{{Measure: 3, Label: "A"}, {Measure: 6, Label: "B"}},
+			},
+		},
+		{
+			name: "title and composer from credits",
+			data: partwise(
+				`<movement-title></movement-title>
+				<credit><credit-type>title</credit-type><credit-words>Etude</credit-words></credit>
+				<credit><credit-type>composer</credit-type><credit-words>Composer</credit-words></credit>`,
+				part(`<measure><sound tempo="90"/></measure>`),
+			),
+			wantScore: scoreimport.Score{Title: "Etude", Composer: "Composer", Measures: 1, Tempo: 90},
+		},
+		{
+			name:      "no parts",
+			data:      partwise(`<movement-title>Fragment</movement-title>`),
+			wantScore: scoreimport.Score{Title: "Fragment"},
+		},
+		{
+			name:      "no measures",
+			data:      partwise(part()),
+			wantScore: scoreimport.Score{},
+		},
+		{
+			name:      "only a pickup",
+			data:      partwise(part(`<measure implicit="yes"></measure>`)),
+			wantScore: scoreimport.Score{},
+		},
+		{
+			name:      "at the measure limit",
+			data:      partwise(part(`<measure implicit="yes"></measure>`, emptyMeasures(100))),
+			wantScore: scoreimport.Score{Measures: 100},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			score, err := scoreimport.ParseMusicXML([]byte(tt.data), 1<<20, 100)
+			if err != nil {
+				t.Fatalf("ParseMusicXML() error = %v", err)
+			}
+			if !reflect.DeepEqual(score, tt.wantScore) {
+				t.Errorf("ParseMusicXML() = %+v, want %+v", score, tt.wantScore)
+			}
+		})
+	}
+
+	t.Run("compressed", func(t *testing.T) {
+		data := mxl(t, map[string]string{
+			"META-INF/container.xml": `<container><rootfiles><rootfile full-path="score/sonata.musicxml" media-type="application/vnd.recordare.musicxml+xml"/></rootfiles></container>`,
+			"score/sonata.musicxml":  sonata,
+			"score/other.xml":        partwise(part(emptyMeasures(1))),
+		})
+		score, err := scoreimport.ParseMusicXML(data, 1<<20, 100)
+		if err != nil {
+			t.Fatalf("ParseMusicXML() error = %v", err)
+		}
+		if score.Title != "Sonata" || score.Measures != 6 {
+			t.Errorf("ParseMusicXML() = %+v, want the sonata from the container", score)
+		}
+	})
+}
+
+func TestParseMusicXMLBadFiles(t *testing.T) {
+	tests := []struct {
+		name    string
+		data    []byte
+		wantErr error
+	}{
+		{"empty", nil, scoreimport.ErrUnsupportedFile},
+		{"not xml", []byte("MThd"), scoreimport.ErrUnsupportedFile},
+		{"timewise", []byte(`<score-timewise><measure></measure></score-timewise>`), scoreimport.ErrUnsupportedFile},
+		{"one measure over the limit", []byte(partwise(part(emptyMeasures(101)))), scoreimport.ErrTooLarge},
+		{"absurd measures", []byte(partwise(part(emptyMeasures(20000)))), scoreimport.ErrTooLarge},
+		{"broken archive", []byte("PK\x03\x04 not really a zip"), scoreimport.ErrUnsupportedFile},
+		{"archive with no score", mxl(t, map[string]string{"readme.txt": "hello"}), scoreimport.ErrUnsupportedFile},
+		{"archive score over the size limit", mxl(t, map[string]string{"score.xml": partwise(part(emptyMeasures(20000)))}), scoreimport.ErrTooLarge},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := scoreimport.ParseMusicXML(tt.data, 1<<16, 100)
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("ParseMusicXML() error = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
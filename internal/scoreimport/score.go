@@ -0,0 +1,107 @@
+package scoreimport
+
+import (
+	"errors"
+	"practicebetter/internal/measurerange"
+)
+
+var (
+	ErrUnsupportedFile = errors.New("unsupported score file")
+	ErrTooLarge        = errors.New("score is too large")
+)
+
+// Score is the information from a score file that's useful for creating a piece
+type Score struct {
+	Title           string
+	Composer        string
+	Measures        int
+	BeatsPerMeasure int
+	Tempo           int
+	// Key and Mode match the names in scale_keys and scale_modes, they are empty if the file
+	// doesn't say
+	Key   string
+	Mode  string
+	Marks []Mark
+}
+
+// Mark is a place in the score where a new spot could start, like a rehearsal mark
+type Mark struct {
+	Measure int
+	Label   string
+}
+
+// SpotRange is a suggested spot covering measures Start through End
+type SpotRange struct {
+	Name string
+	measurerange.Range
+}
+
+// SpotsEvery splits the score into spots of size measures each, the last one may be shorter
+func (s Score) SpotsEvery(size int) []SpotRange {
+	chunks := measurerange.Chunks(s.Measures, size)
+	spots := make([]SpotRange, 0, len(chunks))
+	for _, chunk := range chunks {
+		spots = append(spots, SpotRange{Name: chunk.Name(), Range: chunk})
+	}
+	return spots
+}
+
+// SpotsAtMarks makes a spot from each mark to the next one. Anything before the first mark becomes
+// its own spot so no measures are left out.
+func (s Score) SpotsAtMarks() []SpotRange {
+	if s.Measures < 1 {
+		return nil
+	}
+	var marks []Mark
+	for _, mark := range s.Marks {
+		if mark.Measure < 1 || mark.Measure > s.Measures {
+			continue
+		}
+		// two marks in the same measure only start one spot
+		if len(marks) > 0 && marks[len(marks)-1].Measure >= mark.Measure {
+			continue
+		}
+		marks = append(marks, mark)
+	}
+	if len(marks) == 0 {
+		return nil
+	}
+
+	spots := make([]SpotRange, 0, len(marks)+1)
+	if marks[0].Measure > 1 {
+		spots = append(spots, SpotRange{Name: "Beginning", Range: measurerange.Range{Start: 1, End: marks[0].Measure - 1}})
+	}
+	for i, mark := range marks {
+		end := s.Measures
+		if i+1 < len(marks) {
+			end = marks[i+1].Measure - 1
+		}
+		spots = append(spots, SpotRange{Name: mark.Label, Range: measurerange.Range{Start: mark.Measure, End: end}})
+	}
+	return spots
+}
+
+var keyNames = [12]string{"C", "C♯/D♭", "D", "D♯/E♭", "E", "F", "F♯/G♭", "G", "G♯/A♭", "A", "A♯/B♭", "B"}
+
+// semitones from the major key's tonic to the tonic of each mode with the same key signature
+var modeOffsets = map[string]int{
+	"Major (Ionian)":  0,
+	"Dorian":          2,
+	"Phrygian":        4,
+	"Lydian":          5,
+	"Mixolydian":      7,
+	"Minor (Aeolian)": 9,
+	"Locrian":         11,
+}
+
+// keyFromFifths names the tonic for a key signature with the number of sharps (positive) or flats
+// (negative) in the given mode
+func keyFromFifths(fifths int, mode string) string {
+	offset, ok := modeOffsets[mode]
+	if !ok {
+		return ""
+	}
+	// each sharp moves the major tonic up a fifth, which is 7 semitones
+	tonic := ((fifths*7+offset)%12 + 12) % 12
+	return keyNames[tonic]
+}
@@ -0,0 +1,740 @@
+package server
+
+import (
+	"archive/zip"
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"log"
+	"net/http"
+	"os"
+	"path"
+	"path/filepath"
+	"practicebetter/internal/ck"
+	"practicebetter/internal/config"
+	"practicebetter/internal/db"
+	"practicebetter/internal/scheduler"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/mavolin/go-htmx"
+	"github.com/nrednav/cuid2"
+)
+
+// ACCOUNT_ARCHIVE_VERSION changes whenever the archive format changes in a way older imports can't read
+const ACCOUNT_ARCHIVE_VERSION = 1
+
+const accountArchiveDataFile = "data.json"
+const accountArchiveUploadsFolder = "uploads"
+
+type AccountArchiveSettings struct {
+	DefaultPlanIntensity string `json:"defaultPlanIntensity"`
+	TimeBetweenBreaks    int64  `json:"timeBetweenBreaks"`
+	DefaultTimeBudget    int64  `json:"defaultTimeBudget"`
+	Scheduler            string `json:"scheduler"`
+	Timezone             string `json:"timezone"`
+}
+
+// AccountArchive is everything in an account, with the ids from the instance it was exported from.
+// Uploaded files are stored next to it in the zip under uploads/<UploadsDir>.
+type AccountArchive struct {
+	Version             int                       `json:"version"`
+	Exported            int64                     `json:"exported"`
+	UploadsDir          string                    `json:"uploadsDir"`
+	Settings            AccountArchiveSettings    `json:"settings"`
+	StageRules          db.StageRule              `json:"stageRules"`
+	IntensityProfiles   []db.IntensityProfile     `json:"intensityProfiles"`
+	Pieces              []db.Piece                `json:"pieces"`
+	Sections            []db.Section              `json:"sections"`
+	Spots               []db.Spot                 `json:"spots"`
+	SpotsSections       []db.SpotsSection         `json:"spotsSections"`
+	UserScales          []db.UserScale            `json:"userScales"`
+	Reading             []db.Reading              `json:"reading"`
+	PracticePlans       []db.PracticePlan         `json:"practicePlans"`
+	PracticePlanSpots   []db.PracticePlanSpot     `json:"practicePlanSpots"`
+	PracticePlanPieces  []db.PracticePlanPiece    `json:"practicePlanPieces"`
+	PracticePlanScales  []db.PracticePlanScale    `json:"practicePlanScales"`
+	PracticePlanReading []db.PracticePlanReading  `json:"practicePlanReading"`
+	PracticeSessions    []db.PracticeSession      `json:"practiceSessions"`
+	SpotEvents          []db.SpotEvent            `json:"spotEvents"`
+	StartingPoints      []db.StartingPointSession `json:"startingPoints"`
+}
+
+func (s *Server) buildAccountArchive(ctx context.Context, qtx *db.Queries, user db.User) (AccountArchive, error) {
+	archive := AccountArchive{
+		Version:    ACCOUNT_ARCHIVE_VERSION,
+		Exported:   time.Now().Unix(),
+		UploadsDir: userUploadsDir(user.ID),
+		Settings: AccountArchiveSettings{
+			DefaultPlanIntensity: user.ConfigDefaultPlanIntensity,
+			TimeBetweenBreaks:    user.ConfigTimeBetweenBreaks,
+			DefaultTimeBudget:    user.ConfigDefaultTimeBudget,
+			Scheduler:            user.ConfigScheduler,
+			Timezone:             user.ConfigTimezone,
+		},
+	}
+	var err error
+	if archive.StageRules, err = getStageRules(ctx, qtx, user.ID); err != nil {
+		return archive, err
+	}
+	if archive.IntensityProfiles, err = qtx.ListUserIntensityProfiles(ctx, user.ID); err != nil {
+		return archive, err
+	}
+	if archive.Pieces, err = qtx.ListUserPiecesForExport(ctx, user.ID); err != nil {
+		return archive, err
+	}
+	if archive.Sections, err = qtx.ListUserSectionsForExport(ctx, user.ID); err != nil {
+		return archive, err
+	}
+	if archive.Spots, err = qtx.ListUserSpotsForExport(ctx, user.ID); err != nil {
+		return archive, err
+	}
+	if archive.SpotsSections, err = qtx.ListUserSpotsSectionsForExport(ctx, user.ID); err != nil {
+		return archive, err
+	}
+	if archive.UserScales, err = qtx.ListUserScalesForExport(ctx, user.ID); err != nil {
+		return archive, err
+	}
+	if archive.Reading, err = qtx.ListUserReadingForExport(ctx, user.ID); err != nil {
+		return archive, err
+	}
+	if archive.PracticePlans, err = qtx.ListUserPracticePlansForExport(ctx, user.ID); err != nil {
+		return archive, err
+	}
+	if archive.PracticePlanSpots, err = qtx.ListUserPracticePlanSpotsForExport(ctx, user.ID); err != nil {
+		return archive, err
+	}
+	if archive.PracticePlanPieces, err = qtx.ListUserPracticePlanPiecesForExport(ctx, user.ID); err != nil {
+		return archive, err
+	}
+	if archive.PracticePlanScales, err = qtx.ListUserPracticePlanScalesForExport(ctx, user.ID); err != nil {
+		return archive, err
+	}
+	if archive.PracticePlanReading, err = qtx.ListUserPracticePlanReadingForExport(ctx, user.ID); err != nil {
+		return archive, err
+	}
+	if archive.PracticeSessions, err = qtx.ListUserPracticeSessionsForExport(ctx, user.ID); err != nil {
+		return archive, err
+	}
+	if archive.SpotEvents, err = qtx.ListUserSpotEventsForExport(ctx, user.ID); err != nil {
+		return archive, err
+	}
+	if archive.StartingPoints, err = qtx.ListUserStartingPointSessionsForExport(ctx, user.ID); err != nil {
+		return archive, err
+	}
+	return archive, nil
+}
+
+func (s *Server) exportAccount(w http.ResponseWriter, r *http.Request) {
+	user := r.Context().Value(ck.UserKey).(db.User)
+
+	// everything is read in one transaction so the archive is consistent
+	tx, err := s.DB.BeginTx(r.Context(), nil)
+	if err != nil {
+		s.DatabaseError(w, r, err, "Could not start transaction")
+		return
+	}
+	defer func() {
+		if err := tx.Rollback(); err != nil && !errors.Is(err, sql.ErrTxDone) {
+			log.Default().Println(err)
+		}
+	}()
+	qtx := db.New(s.DB).WithTx(tx)
+	archive, err := s.buildAccountArchive(r.Context(), qtx, user)
+	if err != nil {
+		s.DatabaseError(w, r, err, "Could not export your account")
+		return
+	}
+	if err := tx.Commit(); err != nil {
+		s.DatabaseError(w, r, err, "Could not export your account")
+		return
+	}
+
+	data, err := json.MarshalIndent(archive, "", "  ")
+	if err != nil {
+		log.Default().Println(err)
+		http.Error(w, "Could not export your account", http.StatusInternalServerError)
+		return
+	}
+
+	// the response is streamed, so after this point errors can only be logged
+	filename := "practicebetter-" + time.Now().Format("2006-01-02") + ".zip"
+	w.Header().Set("Content-Type", "application/zip")
+	w.Header().Set("Content-Disposition", `attachment; filename="`+filename+`"`)
+	w.WriteHeader(http.StatusOK)
+
+	zw := zip.NewWriter(w)
+	defer func() {
+		if err := zw.Close(); err != nil {
+			log.Default().Println(err)
+		}
+	}()
+	f, err := zw.Create(accountArchiveDataFile)
+	if err != nil {
+		log.Default().Println(err)
+		return
+	}
+	if _, err := f.Write(data); err != nil {
+		log.Default().Println(err)
+		return
+	}
+
+	uploadsPath := filepath.Join(s.UploadsPath, archive.UploadsDir)
+	err = filepath.WalkDir(uploadsPath, func(p string, d fs.DirEntry, err error) error {
+		if errors.Is(err, fs.ErrNotExist) && p == uploadsPath {
+			// users that have never uploaded anything don't have a folder
+			return fs.SkipAll
+		}
+		if err != nil {
+			return err
+		}
+		if !d.Type().IsRegular() {
+			return nil
+		}
+		rel, err := filepath.Rel(uploadsPath, p)
+		if err != nil {
+			return err
+		}
+		return addFileToArchive(zw, p, path.Join(accountArchiveUploadsFolder, archive.UploadsDir, filepath.ToSlash(rel)))
+	})
+	if err != nil {
+		log.Default().Println(err)
+	}
+}
+
+func addFileToArchive(zw *zip.Writer, src string, name string) error {
+	file, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+	dst, err := zw.Create(name)
+	if err != nil {
+		return err
+	}
+	_, err = io.Copy(dst, file)
+	return err
+}
+
+// idMap gives everything from the archive a fresh id, so an archive can be imported into any
+// instance, even the one it came from.
+type idMap map[string]string
+
+func (m idMap) add(oldID string) string {
+	newID := cuid2.Generate()
+	m[oldID] = newID
+	return newID
+}
+
+// nullable remaps an optional reference, references to things that weren't in the archive become null
+func (m idMap) nullable(oldID sql.NullString) sql.NullString {
+	if !oldID.Valid {
+		return oldID
+	}
+	newID, ok := m[oldID.String]
+	return sql.NullString{String: newID, Valid: ok}
+}
+
+func (s *Server) importAccount(w http.ResponseWriter, r *http.Request) {
+	user := r.Context().Value(ck.UserKey).(db.User)
+
+	r.Body = http.MaxBytesReader(w, r.Body, config.MAX_ARCHIVE_SIZE)
+	if err := r.ParseMultipartForm(config.MAX_UPLOAD_SIZE); err != nil {
+		log.Default().Println(err)
+		s.InvalidInputError(w, r, "The archive is too big to import")
+		return
+	}
+	file, fileHeader, err := r.FormFile("archive")
+	if err != nil {
+		log.Default().Println(err)
+		s.InvalidInputError(w, r, "Choose an archive to import")
+		return
+	}
+	defer file.Close()
+
+	zr, err := zip.NewReader(file, fileHeader.Size)
+	if err != nil {
+		log.Default().Println(err)
+		s.InvalidInputError(w, r, "The file is not an account archive")
+		return
+	}
+	archive, err := readAccountArchive(zr)
+	if err != nil {
+		log.Default().Println(err)
+		s.InvalidInputError(w, r, err.Error())
+		return
+	}
+
+	queries := db.New(s.DB)
+	count, err := queries.CountUserLibraryItems(r.Context(), user.ID)
+	if err != nil {
+		s.DatabaseError(w, r, err, "Could not check your library")
+		return
+	}
+	if count > 0 {
+		s.InvalidInputError(w, r, "Archives can only be imported into an account with an empty library")
+		return
+	}
+
+	tx, err := s.DB.BeginTx(r.Context(), nil)
+	if err != nil {
+		s.DatabaseError(w, r, err, "Could not start transaction")
+		return
+	}
+	defer func() {
+		if err := tx.Rollback(); err != nil && !errors.Is(err, sql.ErrTxDone) {
+			log.Default().Println(err)
+		}
+	}()
+	qtx := queries.WithTx(tx)
+
+	if err := restoreAccountArchive(r.Context(), qtx, user, archive); err != nil {
+		s.DatabaseError(w, r, err, "Could not import your account")
+		return
+	}
+	written, err := s.restoreArchiveUploads(zr, archive.UploadsDir, userUploadsDir(user.ID))
+	if err != nil {
+		log.Default().Println(err)
+		removeFiles(written)
+		http.Error(w, "Could not restore uploaded files", http.StatusInternalServerError)
+		return
+	}
+	if err := tx.Commit(); err != nil {
+		removeFiles(written)
+		s.DatabaseError(w, r, err, "Could not import your account")
+		return
+	}
+
+	if err := htmx.Trigger(r, "ShowAlert", ShowAlertEvent{
+		Message:  fmt.Sprintf("Imported %d pieces and %d practice plans", len(archive.Pieces), len(archive.PracticePlans)),
+		Title:    "Account Imported!",
+		Variant:  "success",
+		Duration: 3000,
+	}); err != nil {
+		log.Default().Println(err)
+	}
+	s.Redirect(w, r, "/library")
+}
+
+func readAccountArchive(zr *zip.Reader) (AccountArchive, error) {
+	var archive AccountArchive
+	f, err := zr.Open(accountArchiveDataFile)
+	if err != nil {
+		return archive, errors.New("The archive is missing its data")
+	}
+	defer f.Close()
+	if err := json.NewDecoder(f).Decode(&archive); err != nil {
+		return archive, errors.New("The archive data could not be read")
+	}
+	if archive.Version < 1 || archive.Version > ACCOUNT_ARCHIVE_VERSION {
+		return archive, fmt.Errorf("Archive version %d can't be imported here", archive.Version)
+	}
+	for _, piece := range archive.Pieces {
+		if message := pieceMeasuresMessage(piece.Measures.Int64); message != "" {
+			return archive, errors.New(piece.Title + ": " + message)
+		}
+	}
+	// the settings are saved as they are, so they get the same checks as the settings form
+	if !slices.Contains(scheduler.Names, archive.Settings.Scheduler) {
+		return archive, errors.New("The archive has an invalid scheduler")
+	}
+	if archive.Settings.Timezone != "" {
+		if _, err := time.LoadLocation(archive.Settings.Timezone); err != nil {
+			return archive, errors.New("The archive has an invalid timezone")
+		}
+	}
+	return archive, nil
+}
+
+// restoreAccountArchive inserts everything from the archive into the user's account with new ids.
+// The user's intensity profiles, settings and stage rules are replaced with the ones from the archive.
+func restoreAccountArchive(ctx context.Context, qtx *db.Queries, user db.User, archive AccountArchive) error {
+	profileIDs := make(idMap)
+	pieceIDs := make(idMap)
+	sectionIDs := make(idMap)
+	spotIDs := make(idMap)
+	scaleIDs := make(idMap)
+	readingIDs := make(idMap)
+	planIDs := make(idMap)
+
+	oldUploads := "/uploads/" + archive.UploadsDir + "/"
+	newUploads := "/uploads/" + userUploadsDir(user.ID) + "/"
+	uploadURL := func(url string) string {
+		if archive.UploadsDir != "" && strings.HasPrefix(url, oldUploads) {
+			return newUploads + strings.TrimPrefix(url, oldUploads)
+		}
+		return url
+	}
+
+	if len(archive.IntensityProfiles) > 0 {
+		existing, err := qtx.ListUserIntensityProfiles(ctx, user.ID)
+		if err != nil {
+			return err
+		}
+		for _, profile := range existing {
+			if err := qtx.DeleteIntensityProfile(ctx, db.DeleteIntensityProfileParams{
+				ID:     profile.ID,
+				UserID: user.ID,
+			}); err != nil {
+				return err
+			}
+		}
+	}
+	for _, profile := range archive.IntensityProfiles {
+		if _, err := qtx.CreateIntensityProfile(ctx, db.CreateIntensityProfileParams{
+			ID:                 profileIDs.add(profile.ID),
+			UserID:             user.ID,
+			Name:               profile.Name,
+			MaxNewSpots:        profile.MaxNewSpots,
+			MaxInterleaveSpots: profile.MaxInterleaveSpots,
+			MaxInfrequentSpots: profile.MaxInfrequentSpots,
+			MaxSightReading:    profile.MaxSightReading,
+		}); err != nil {
+			return err
+		}
+	}
+	// the default intensity is a profile id, the time budget or the name of an old built in intensity.
+	// plans keep the name of the profile they were made with, so they don't need to change.
+	defaultIntensity := archive.Settings.DefaultPlanIntensity
+	if newID, ok := profileIDs[defaultIntensity]; ok {
+		defaultIntensity = newID
+	}
+
+	if _, err := qtx.UpdateUserSettings(ctx, db.UpdateUserSettingsParams{
+		ConfigDefaultPlanIntensity: defaultIntensity,
+		ConfigTimeBetweenBreaks:    archive.Settings.TimeBetweenBreaks,
+		ConfigDefaultTimeBudget:    archive.Settings.DefaultTimeBudget,
+		ConfigScheduler:            archive.Settings.Scheduler,
+		ConfigTimezone:             archive.Settings.Timezone,
+		ID:                         user.ID,
+	}); err != nil {
+		return err
+	}
+	if _, err := qtx.UpsertStageRules(ctx, db.UpsertStageRulesParams{
+		UserID:                     user.ID,
+		RandomPromoteExcellent:     archive.StageRules.RandomPromoteExcellent,
+		RandomPromoteMinDays:       archive.StageRules.RandomPromoteMinDays,
+		RandomDemotePoor:           archive.StageRules.RandomDemotePoor,
+		RandomDemoteStaleDays:      archive.StageRules.RandomDemoteStaleDays,
+		RandomDemoteTo:             archive.StageRules.RandomDemoteTo,
+		InterleavePromoteExcellent: archive.StageRules.InterleavePromoteExcellent,
+		InterleavePromoteMinDays:   archive.StageRules.InterleavePromoteMinDays,
+		InterleaveStaleDays:        archive.StageRules.InterleaveStaleDays,
+		InterleaveDemoteTo:         archive.StageRules.InterleaveDemoteTo,
+		InfrequentDemoteTo:         archive.StageRules.InfrequentDemoteTo,
+	}); err != nil {
+		return err
+	}
+
+	for _, piece := range archive.Pieces {
+		if err := qtx.ImportPiece(ctx, db.ImportPieceParams{
+			ID:              pieceIDs.add(piece.ID),
+			Title:           piece.Title,
+			Description:     piece.Description,
+			Composer:        piece.Composer,
+			Measures:        piece.Measures,
+			BeatsPerMeasure: piece.BeatsPerMeasure,
+			GoalTempo:       piece.GoalTempo,
+			UserID:          user.ID,
+			LastPracticed:   piece.LastPracticed,
+			Stage:           piece.Stage,
+			KeyID:           piece.KeyID,
+			ModeID:          piece.ModeID,
+		}); err != nil {
+			return err
+		}
+	}
+	for _, section := range archive.Sections {
+		pieceID, ok := pieceIDs[section.PieceID]
+		if !ok {
+			continue
+		}
+		if err := qtx.ImportSection(ctx, db.ImportSectionParams{
+			ID:           sectionIDs.add(section.ID),
+			Name:         section.Name,
+			Description:  section.Description,
+			PieceID:      pieceID,
+			StartMeasure: section.StartMeasure,
+			EndMeasure:   section.EndMeasure,
+		}); err != nil {
+			return err
+		}
+	}
+	for _, spot := range archive.Spots {
+		pieceID, ok := pieceIDs[spot.PieceID]
+		if !ok {
+			continue
+		}
+		measuresStart, measuresEnd := spotMeasureRange(spot.Measures)
+		if err := qtx.ImportSpot(ctx, db.ImportSpotParams{
+			ID:             spotIDs.add(spot.ID),
+			PieceID:        pieceID,
+			Name:           spot.Name,
+			Stage:          spot.Stage,
+			Measures:       spot.Measures,
+			AudioPromptUrl: uploadURL(spot.AudioPromptUrl),
+			ImagePromptUrl: uploadURL(spot.ImagePromptUrl),
+			NotesPrompt:    spot.NotesPrompt,
+			TextPrompt:     spot.TextPrompt,
+			CurrentTempo:   spot.CurrentTempo,
+			LastPracticed:  spot.LastPracticed,
+			StageStarted:   spot.StageStarted,
+			SkipDays:       spot.SkipDays,
+			Priority:       spot.Priority,
+			SectionID:      sectionIDs.nullable(spot.SectionID),
+			MeasuresStart:  measuresStart,
+			MeasuresEnd:    measuresEnd,
+		}); err != nil {
+			return err
+		}
+	}
+	for _, spotSection := range archive.SpotsSections {
+		spotID, spotOK := spotIDs[spotSection.SpotID]
+		sectionID, sectionOK := sectionIDs[spotSection.SectionID]
+		pieceID, pieceOK := pieceIDs[spotSection.PieceID]
+		if !spotOK || !sectionOK || !pieceOK {
+			continue
+		}
+		if err := qtx.ImportSpotsSection(ctx, db.ImportSpotsSectionParams{
+			SpotID:    spotID,
+			SectionID: sectionID,
+			PieceID:   pieceID,
+		}); err != nil {
+			return err
+		}
+	}
+
+	for _, scale := range archive.UserScales {
+		if err := qtx.ImportUserScale(ctx, db.ImportUserScaleParams{
+			ID:            scaleIDs.add(scale.ID),
+			UserID:        user.ID,
+			ScaleID:       scale.ScaleID,
+			PracticeNotes: scale.PracticeNotes,
+			LastPracticed: scale.LastPracticed,
+			Reference:     scale.Reference,
+			Working:       scale.Working,
+		}); err != nil {
+			return err
+		}
+	}
+	for _, item := range archive.Reading {
+		if err := qtx.ImportReading(ctx, db.ImportReadingParams{
+			ID:        readingIDs.add(item.ID),
+			Title:     item.Title,
+			Info:      item.Info,
+			Completed: item.Completed,
+			Composer:  item.Composer,
+			UserID:    user.ID,
+		}); err != nil {
+			return err
+		}
+	}
+
+	for _, plan := range archive.PracticePlans {
+		if err := qtx.ImportPracticePlan(ctx, db.ImportPracticePlanParams{
+			ID:            planIDs.add(plan.ID),
+			UserID:        user.ID,
+			Intensity:     plan.Intensity,
+			Date:          plan.Date,
+			Completed:     plan.Completed,
+			PracticeNotes: plan.PracticeNotes,
+			LastPracticed: plan.LastPracticed,
+		}); err != nil {
+			return err
+		}
+	}
+	for _, planSpot := range archive.PracticePlanSpots {
+		planID, planOK := planIDs[planSpot.PracticePlanID]
+		spotID, spotOK := spotIDs[planSpot.SpotID]
+		if !planOK || !spotOK {
+			continue
+		}
+		if err := qtx.ImportPracticePlanSpot(ctx, db.ImportPracticePlanSpotParams{
+			PracticePlanID: planID,
+			SpotID:         spotID,
+			PracticeType:   planSpot.PracticeType,
+			Evaluation:     planSpot.Evaluation,
+			Completed:      planSpot.Completed,
+			Idx:            planSpot.Idx,
+		}); err != nil {
+			return err
+		}
+	}
+	for _, planPiece := range archive.PracticePlanPieces {
+		planID, planOK := planIDs[planPiece.PracticePlanID]
+		pieceID, pieceOK := pieceIDs[planPiece.PieceID]
+		if !planOK || !pieceOK {
+			continue
+		}
+		if err := qtx.ImportPracticePlanPiece(ctx, db.ImportPracticePlanPieceParams{
+			PracticePlanID: planID,
+			PieceID:        pieceID,
+			PracticeType:   planPiece.PracticeType,
+			Completed:      planPiece.Completed,
+			Sessions:       planPiece.Sessions,
+			Idx:            planPiece.Idx,
+		}); err != nil {
+			return err
+		}
+	}
+	for _, planScale := range archive.PracticePlanScales {
+		planID, planOK := planIDs[planScale.PracticePlanID]
+		scaleID, scaleOK := scaleIDs[planScale.UserScaleID]
+		if !planOK || !scaleOK {
+			continue
+		}
+		if err := qtx.ImportPracticePlanScale(ctx, db.ImportPracticePlanScaleParams{
+			PracticePlanID: planID,
+			UserScaleID:    scaleID,
+			Completed:      planScale.Completed,
+			Idx:            planScale.Idx,
+		}); err != nil {
+			return err
+		}
+	}
+	for _, planReading := range archive.PracticePlanReading {
+		planID, planOK := planIDs[planReading.PracticePlanID]
+		readingID, readingOK := readingIDs[planReading.ReadingID]
+		if !planOK || !readingOK {
+			continue
+		}
+		if err := qtx.ImportPracticePlanReading(ctx, db.ImportPracticePlanReadingParams{
+			PracticePlanID: planID,
+			ReadingID:      readingID,
+			Completed:      planReading.Completed,
+			Idx:            planReading.Idx,
+		}); err != nil {
+			return err
+		}
+	}
+
+	for _, session := range archive.PracticeSessions {
+		if err := qtx.ImportPracticeSession(ctx, db.ImportPracticeSessionParams{
+			ID:              cuid2.Generate(),
+			UserID:          user.ID,
+			PracticeType:    session.PracticeType,
+			DurationMinutes: session.DurationMinutes,
+			Date:            session.Date,
+			SpotID:          spotIDs.nullable(session.SpotID),
+			PieceID:         pieceIDs.nullable(session.PieceID),
+			UserScaleID:     scaleIDs.nullable(session.UserScaleID),
+			ReadingID:       readingIDs.nullable(session.ReadingID),
+			PracticePlanID:  planIDs.nullable(session.PracticePlanID),
+		}); err != nil {
+			return err
+		}
+	}
+	for _, event := range archive.SpotEvents {
+		spotID, ok := spotIDs[event.SpotID]
+		if !ok {
+			continue
+		}
+		if err := qtx.ImportSpotEvent(ctx, db.ImportSpotEventParams{
+			ID:             cuid2.Generate(),
+			SpotID:         spotID,
+			UserID:         user.ID,
+			EventType:      event.EventType,
+			PracticeType:   event.PracticeType,
+			Evaluation:     event.Evaluation,
+			Success:        event.Success,
+			FromStage:      event.FromStage,
+			ToStage:        event.ToStage,
+			PracticePlanID: planIDs.nullable(event.PracticePlanID),
+			Date:           event.Date,
+			Attempts:       event.Attempts,
+			Successes:      event.Successes,
+			Resets:         event.Resets,
+		}); err != nil {
+			return err
+		}
+	}
+	for _, session := range archive.StartingPoints {
+		pieceID, ok := pieceIDs[session.PieceID]
+		if !ok {
+			continue
+		}
+		if err := qtx.ImportStartingPointSession(ctx, db.ImportStartingPointSessionParams{
+			ID:                cuid2.Generate(),
+			UserID:            user.ID,
+			PieceID:           pieceID,
+			Date:              session.Date,
+			MeasuresPracticed: session.MeasuresPracticed,
+			Rating:            session.Rating,
+			TroubleMeasures:   session.TroubleMeasures,
+			TroubleConverted:  session.TroubleConverted,
+			PracticePlanID:    planIDs.nullable(session.PracticePlanID),
+		}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// restoreArchiveUploads copies the uploaded files from the archive into the user's uploads folder
+// and returns the paths it wrote. Only audio and image files from the exported user's folder are
+// copied, anything else in the zip is ignored.
+func (s *Server) restoreArchiveUploads(zr *zip.Reader, oldDir string, newDir string) ([]string, error) {
+	var written []string
+	if oldDir == "" {
+		return written, nil
+	}
+	prefix := accountArchiveUploadsFolder + "/" + oldDir + "/"
+	for _, f := range zr.File {
+		name := path.Clean(f.Name)
+		if !strings.HasPrefix(name, prefix) || f.FileInfo().IsDir() {
+			continue
+		}
+		kind, filename, ok := strings.Cut(strings.TrimPrefix(name, prefix), "/")
+		if !ok || (kind != "audio" && kind != "images") || filename != path.Base(filename) {
+			continue
+		}
+		if f.UncompressedSize64 > config.MAX_UPLOAD_SIZE {
+			return written, fmt.Errorf("%s is too big", name)
+		}
+
+		folder := filepath.Join(s.UploadsPath, newDir, kind)
+		if err := os.MkdirAll(folder, os.ModePerm); err != nil {
+			return written, err
+		}
+		dst := filepath.Join(folder, filename)
+		if err := copyArchiveFile(f, dst); err != nil {
+			if errors.Is(err, fs.ErrExist) {
+				continue
+			}
+			return written, err
+		}
+		written = append(written, dst)
+	}
+	return written, nil
+}
+
+func copyArchiveFile(f *zip.File, dst string) error {
+	src, err := f.Open()
+	if err != nil {
+		return err
+	}
+	defer src.Close()
+	out, err := os.OpenFile(dst, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0644)
+	if err != nil {
+		return err
+	}
+	defer out.Close()
+	// the header can lie about the size, so the copy is limited too
+	if _, err := io.Copy(out, io.LimitReader(src, config.MAX_UPLOAD_SIZE)); err != nil {
+		return err
+	}
+	return nil
+}
+
+func removeFiles(paths []string) {
+	for _, p := range paths {
+		if err := os.Remove(p); err != nil {
+			log.Default().Println(err)
+		}
+	}
+}
@@ -10,10 +10,14 @@ import (
 	"net/mail"
 	"practicebetter/internal/auth"
 	"practicebetter/internal/ck"
+	"practicebetter/internal/config"
 	"practicebetter/internal/db"
 	"practicebetter/internal/pages/authpages"
+	"practicebetter/internal/scheduler"
+	"slices"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/gorilla/csrf"
 	"github.com/mavolin/go-htmx"
@@ -231,6 +235,7 @@ func (s *Server) completeCodeLogin(w http.ResponseWriter, r *http.Request) {
 			http.Error(w, "Could not log you in with that information.", http.StatusUnauthorized)
 			return
 		}
+		setBrowserTimezone(r.Context(), queries, r, user.ID)
 		nextLocCookie := http.Cookie{
 			Name:     "nextLoc",
 			Path:     "/",
@@ -312,6 +317,7 @@ func (s *Server) completePasskeySignin(w http.ResponseWriter, r *http.Request) {
 			// TODO: re-render the form with an error
 			return
 		}
+		setBrowserTimezone(r.Context(), queries, r, user.ID)
 		w.Header().Set("Content-Type", "application/json")
 		err := json.NewEncoder(w).Encode(map[string]string{"status": "ok", "redirect": "/auth/me"})
 		if err != nil {
@@ -353,8 +359,20 @@ func (s *Server) me(w http.ResponseWriter, r *http.Request) {
 		fmt.Println("Could not find registration options")
 	}
 
+	profiles, err := s.getIntensityProfiles(r.Context(), user)
+	if err != nil {
+		s.DatabaseError(w, r, err, "Could not get intensity profiles")
+		return
+	}
+
+	rules, err := getStageRules(r.Context(), queries, user.ID)
+	if err != nil {
+		s.DatabaseError(w, r, err, "Could not get stage rules")
+		return
+	}
+
 	token := csrf.Token(r)
-	component := authpages.MePage(user, registrationOptions, token, fmt.Sprintf("%d", credentialCount), s)
+	component := authpages.MePage(user, registrationOptions, token, fmt.Sprintf("%d", credentialCount), profiles, defaultIntensityProfileID(user, profiles), rules, s)
 	s.HxRender(w, r, component, "Account")
 }
 
@@ -557,15 +575,40 @@ func (s *Server) updateSettings(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 	practicePlanIntensity := r.Form.Get("config_default_plan_intensity")
-	if practicePlanIntensity != "light" && practicePlanIntensity != "medium" && practicePlanIntensity != "heavy" {
-		s.InvalidInputError(w, r, "Invalid plan intensity")
+	if practicePlanIntensity != config.TIME_BUDGET_INTENSITY {
+		if _, err := queries.GetIntensityProfile(r.Context(), db.GetIntensityProfileParams{
+			ID:     practicePlanIntensity,
+			UserID: user.ID,
+		}); err != nil {
+			s.InvalidInputError(w, r, "Invalid plan intensity")
+			return
+		}
+	}
+	timeBudget, err := strconv.Atoi(r.Form.Get("config_default_time_budget"))
+	if err != nil || timeBudget < config.MIN_TIME_BUDGET || timeBudget > config.MAX_TIME_BUDGET {
+		s.InvalidInputError(w, r, "Invalid time budget")
 		return
 	}
+	schedulerName := r.Form.Get("config_scheduler")
+	if !slices.Contains(scheduler.Names, schedulerName) {
+		s.InvalidInputError(w, r, "Invalid scheduler")
+		return
+	}
+	timezone := strings.TrimSpace(r.Form.Get("config_timezone"))
+	if timezone != "" {
+		if _, err := time.LoadLocation(timezone); err != nil {
+			s.InvalidInputError(w, r, "Invalid timezone")
+			return
+		}
+	}
 
 	user, err = queries.UpdateUserSettings(r.Context(), db.UpdateUserSettingsParams{
 		ID:                         user.ID,
 		ConfigTimeBetweenBreaks:    int64(timeBetweenBreaks),
 		ConfigDefaultPlanIntensity: practicePlanIntensity,
+		ConfigDefaultTimeBudget:    int64(timeBudget),
+		ConfigScheduler:            schedulerName,
+		ConfigTimezone:             timezone,
 	})
 	if err != nil {
 		log.Default().Println(err)
@@ -581,7 +624,12 @@ func (s *Server) updateSettings(w http.ResponseWriter, r *http.Request) {
 	}); err != nil {
 		log.Default().Println(err)
 	}
-	if err := authpages.UserSettingsForm(user, csrf.Token(r)).Render(r.Context(), w); err != nil {
+	profiles, err := s.getIntensityProfiles(r.Context(), user)
+	if err != nil {
+		s.DatabaseError(w, r, err, "Could not get intensity profiles")
+		return
+	}
+	if err := authpages.UserSettingsForm(user, profiles, defaultIntensityProfileID(user, profiles), csrf.Token(r)).Render(r.Context(), w); err != nil {
 		log.Default().Println(err)
 		http.Error(w, "Render Error", http.StatusInternalServerError)
 	}
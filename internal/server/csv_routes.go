@@ -0,0 +1,518 @@
+package server
+
+import (
+	"context"
+	"database/sql"
+	"encoding/csv"
+	"errors"
+	"io"
+	"log"
+	"net/http"
+	"practicebetter/internal/ck"
+	"practicebetter/internal/config"
+	"practicebetter/internal/db"
+	"practicebetter/internal/pages/librarypages"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/gorilla/csrf"
+	"github.com/mavolin/go-htmx"
+	"github.com/nrednav/cuid2"
+)
+
+// csvColumn is a field that can be imported from a spreadsheet. Aliases are other headers that are
+// matched to it automatically.
+type csvColumn struct {
+	field   librarypages.CSVField
+	aliases []string
+}
+
+// csvImportKind is something that can be imported from a spreadsheet, save is given the rows with
+// each value under its field key and returns how many items were created, or a message for the user
+// if the rows can't be imported.
+type csvImportKind struct {
+	title    string
+	itemName string
+	basePath string
+	columns  []csvColumn
+	save     func(ctx context.Context, qtx *db.Queries, userID string, rows []map[string]string) (int, string, error)
+}
+
+var pieceCSVColumns = []csvColumn{
+	{librarypages.CSVField{Key: "title", Label: "Title", Required: true}, []string{"piece", "piecetitle", "name", "work"}},
+	{librarypages.CSVField{Key: "composer", Label: "Composer"}, []string{"author", "arranger"}},
+	{librarypages.CSVField{Key: "measures", Label: "Measures"}, []string{"bars", "piecemeasures"}},
+	{librarypages.CSVField{Key: "beats_per_measure", Label: "Beats Per Measure"}, []string{"beats", "meter"}},
+	{librarypages.CSVField{Key: "goal_tempo", Label: "Goal Tempo"}, []string{"tempo", "bpm"}},
+	{librarypages.CSVField{Key: "spot_name", Label: "Spot Name"}, []string{"spot"}},
+	{librarypages.CSVField{Key: "spot_measures", Label: "Spot Measures"}, []string{"spotbars"}},
+	{librarypages.CSVField{Key: "spot_stage", Label: "Spot Stage"}, []string{"stage"}},
+	{librarypages.CSVField{Key: "spot_tempo", Label: "Spot Tempo"}, []string{"currenttempo"}},
+	{librarypages.CSVField{Key: "spot_priority", Label: "Spot Priority"}, []string{"priority"}},
+	{librarypages.CSVField{Key: "spot_text_prompt", Label: "Spot Text Prompt"}, []string{"textprompt", "prompt"}},
+}
+
+var readingCSVColumns = []csvColumn{
+	{librarypages.CSVField{Key: "title", Label: "Title", Required: true}, []string{"name", "piece", "book"}},
+	{librarypages.CSVField{Key: "composer", Label: "Composer"}, []string{"author", "arranger"}},
+	{librarypages.CSVField{Key: "info", Label: "Info"}, []string{"notes", "description", "source"}},
+	{librarypages.CSVField{Key: "completed", Label: "Completed"}, []string{"done", "finished", "read"}},
+}
+
+var pieceCSVImport = csvImportKind{
+	title:    "Pieces",
+	itemName: "pieces",
+	basePath: "/library/pieces",
+	columns:  pieceCSVColumns,
+	save:     savePieceCSVRows,
+}
+
+var readingCSVImport = csvImportKind{
+	title:    "Sight Reading",
+	itemName: "sight reading items",
+	basePath: "/library/reading",
+	columns:  readingCSVColumns,
+	save:     saveReadingCSVRows,
+}
+
+func (kind csvImportKind) info() librarypages.CSVImport {
+	fields := make([]librarypages.CSVField, 0, len(kind.columns))
+	for _, column := range kind.columns {
+		fields = append(fields, column.field)
+	}
+	return librarypages.CSVImport{
+		Title:    kind.title,
+		BasePath: kind.basePath,
+		Fields:   fields,
+	}
+}
+
+// normalizeCSVHeader makes headers like "Goal Tempo" and "goal_tempo" match
+func normalizeCSVHeader(header string) string {
+	return strings.Map(func(r rune) rune {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			return unicode.ToLower(r)
+		}
+		return -1
+	}, header)
+}
+
+// guessMapping matches each column to the first header with the same name or an alias
+func (kind csvImportKind) guessMapping(headers []string) map[string]int {
+	mapping := make(map[string]int, len(kind.columns))
+	used := make(map[int]bool, len(headers))
+	for _, column := range kind.columns {
+		mapping[column.field.Key] = -1
+		names := append([]string{column.field.Key, column.field.Label}, column.aliases...)
+	search:
+		for _, name := range names {
+			for i, header := range headers {
+				if !used[i] && normalizeCSVHeader(header) == normalizeCSVHeader(name) {
+					mapping[column.field.Key] = i
+					used[i] = true
+					break search
+				}
+			}
+		}
+	}
+	return mapping
+}
+
+// formMapping reads the column the user picked for each field
+func (kind csvImportKind) formMapping(r *http.Request, headers []string) map[string]int {
+	mapping := make(map[string]int, len(kind.columns))
+	for _, column := range kind.columns {
+		i, err := strconv.Atoi(r.Form.Get("map_" + column.field.Key))
+		if err != nil || i < 0 || i >= len(headers) {
+			i = -1
+		}
+		mapping[column.field.Key] = i
+	}
+	return mapping
+}
+
+// mapRows puts each row's values under their field keys, unmapped fields are empty
+func (kind csvImportKind) mapRows(rows [][]string, mapping map[string]int) []map[string]string {
+	mapped := make([]map[string]string, 0, len(rows))
+	for _, row := range rows {
+		values := make(map[string]string, len(kind.columns))
+		for _, column := range kind.columns {
+			value := ""
+			if col := mapping[column.field.Key]; col >= 0 && col < len(row) {
+				value = strings.TrimSpace(row[col])
+			}
+			values[column.field.Key] = value
+		}
+		mapped = append(mapped, values)
+	}
+	return mapped
+}
+
+// parseCSV splits an uploaded file into its header row and the rows after it, skipping blank rows
+func parseCSV(data string) ([]string, [][]string, error) {
+	reader := csv.NewReader(strings.NewReader(strings.TrimPrefix(data, "\ufeff")))
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+	records, err := reader.ReadAll()
+	if err != nil {
+		return nil, nil, err
+	}
+	if len(records) == 0 {
+		return nil, nil, errors.New("no header row")
+	}
+	rows := make([][]string, 0, len(records)-1)
+	for _, record := range records[1:] {
+		if strings.TrimSpace(strings.Join(record, "")) != "" {
+			rows = append(rows, record)
+		}
+	}
+	return records[0], rows, nil
+}
+
+func (s *Server) renderCSVPreview(w http.ResponseWriter, r *http.Request, kind csvImportKind, data string, mapping func([]string) map[string]int, full bool) {
+	headers, rows, err := parseCSV(data)
+	if err != nil {
+		log.Default().Println(err)
+		s.InvalidInputError(w, r, "Could not read the CSV file")
+		return
+	}
+	if len(rows) == 0 {
+		s.InvalidInputError(w, r, "The CSV file has no rows after the header")
+		return
+	}
+	info := kind.info()
+	info.Headers = headers
+	info.Mapping = mapping(headers)
+	info.Data = data
+	info.RowCount = len(rows)
+	info.Preview = kind.mapRows(rows[:min(len(rows), config.CSV_PREVIEW_ROWS)], info.Mapping)
+
+	if !full {
+		w.Header().Set("Content-Type", "text/html")
+		if err := librarypages.CSVImportPreview(info).Render(r.Context(), w); err != nil {
+			log.Default().Println(err)
+			http.Error(w, "Render Error", http.StatusInternalServerError)
+		}
+		return
+	}
+	s.HxRender(w, r, librarypages.CSVImportMappingPage(info, csrf.Token(r)), "Import "+kind.title)
+}
+
+func (s *Server) csvImportForm(kind csvImportKind) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		s.HxRender(w, r, librarypages.CSVImportUploadPage(kind.info(), csrf.Token(r)), "Import "+kind.title)
+	}
+}
+
+func (s *Server) csvImportUpload(kind csvImportKind) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		r.Body = http.MaxBytesReader(w, r.Body, config.MAX_UPLOAD_SIZE)
+		if err := r.ParseMultipartForm(config.MAX_UPLOAD_SIZE); err != nil {
+			log.Default().Println(err)
+			s.InvalidInputError(w, r, "The uploaded file is too big. Please choose a file that's less than 1MB in size")
+			return
+		}
+		file, _, err := r.FormFile("file")
+		if err != nil {
+			log.Default().Println(err)
+			s.InvalidInputError(w, r, "Choose a CSV file to import")
+			return
+		}
+		defer file.Close()
+		data, err := io.ReadAll(file)
+		if err != nil {
+			log.Default().Println(err)
+			s.InvalidInputError(w, r, "Could not read the uploaded file")
+			return
+		}
+		s.renderCSVPreview(w, r, kind, string(data), kind.guessMapping, true)
+	}
+}
+
+func (s *Server) csvImportPreview(kind csvImportKind) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		// the file comes back url encoded, which can make it a few times bigger
+		r.Body = http.MaxBytesReader(w, r.Body, 4*config.MAX_UPLOAD_SIZE)
+		if err := r.ParseForm(); err != nil {
+			log.Default().Println(err)
+			s.InvalidInputError(w, r, "Invalid input")
+			return
+		}
+		s.renderCSVPreview(w, r, kind, r.Form.Get("data"), func(headers []string) map[string]int {
+			return kind.formMapping(r, headers)
+		}, false)
+	}
+}
+
+func (s *Server) csvImportConfirm(kind csvImportKind) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		user := r.Context().Value(ck.UserKey).(db.User)
+		// the file comes back url encoded, which can make it a few times bigger
+		r.Body = http.MaxBytesReader(w, r.Body, 4*config.MAX_UPLOAD_SIZE)
+		if err := r.ParseForm(); err != nil {
+			log.Default().Println(err)
+			s.InvalidInputError(w, r, "Invalid input")
+			return
+		}
+		headers, rows, err := parseCSV(r.Form.Get("data"))
+		if err != nil {
+			log.Default().Println(err)
+			s.InvalidInputError(w, r, "Could not read the CSV file")
+			return
+		}
+		mapping := kind.formMapping(r, headers)
+		for _, column := range kind.columns {
+			if column.field.Required && mapping[column.field.Key] == -1 {
+				s.InvalidInputError(w, r, "Choose a column for "+column.field.Label)
+				return
+			}
+		}
+
+		tx, err := s.DB.BeginTx(r.Context(), nil)
+		if err != nil {
+			s.DatabaseError(w, r, err, "Could not import "+kind.itemName)
+			return
+		}
+		defer func() {
+			if err := tx.Rollback(); err != nil && !errors.Is(err, sql.ErrTxDone) {
+				log.Default().Println(err)
+			}
+		}()
+		qtx := db.New(s.DB).WithTx(tx)
+
+		count, message, err := kind.save(r.Context(), qtx, user.ID, kind.mapRows(rows, mapping))
+		if message != "" {
+			s.InvalidInputError(w, r, message)
+			return
+		}
+		if err != nil {
+			s.DatabaseError(w, r, err, "Could not import "+kind.itemName)
+			return
+		}
+		if err := tx.Commit(); err != nil {
+			s.DatabaseError(w, r, err, "Could not import "+kind.itemName)
+			return
+		}
+
+		if err := htmx.Trigger(r, "ShowAlert", ShowAlertEvent{
+			Message:  "Successfully imported " + strconv.Itoa(count) + " " + kind.itemName + ".",
+			Title:    "Import Complete",
+			Variant:  "success",
+			Duration: 3000,
+		}); err != nil {
+			log.Default().Println(err)
+		}
+		htmx.Redirect(r, kind.basePath)
+		http.Redirect(w, r, kind.basePath, http.StatusSeeOther)
+	}
+}
+
+// csvInt reads an optional whole number from a spreadsheet cell
+func csvInt(value string) (int64, bool) {
+	if value == "" {
+		return 0, true
+	}
+	n, err := strconv.ParseInt(value, 10, 64)
+	return n, err == nil
+}
+
+// savePieceCSVRows groups rows with the same title and composer into one piece. The first row for
+// a piece sets its details, and every row with a spot name adds a spot.
+func savePieceCSVRows(ctx context.Context, qtx *db.Queries, userID string, rows []map[string]string) (int, string, error) {
+	var pieces []ImportExportPiece
+	pieceIndex := make(map[string]int)
+	for i, row := range rows {
+		rowLabel := "Row " + strconv.Itoa(i+2)
+		title, composer := row["title"], row["composer"]
+		if title == "" {
+			return 0, rowLabel + " has no title", nil
+		}
+		key := title + "\x00" + composer
+		index, ok := pieceIndex[key]
+		if !ok {
+			piece := ImportExportPiece{
+				Version:         PIECE_EXPORT_VERSION,
+				IncludeProgress: true,
+				Title:           title,
+				Composer:        composer,
+				Spots:           []ImportExportSpot{},
+			}
+			numbers := []struct {
+				key  string
+				name string
+				dest *int64
+			}{
+				{"measures", "measures", &piece.Measures},
+				{"beats_per_measure", "beats per measure", &piece.BeatsPerMeasure},
+				{"goal_tempo", "goal tempo", &piece.GoalTempo},
+			}
+			for _, number := range numbers {
+				value, ok := csvInt(row[number.key])
+				if !ok || value < 0 {
+					return 0, rowLabel + " has an invalid " + number.name, nil
+				}
+				*number.dest = value
+			}
+			if message := pieceMeasuresMessage(piece.Measures); message != "" {
+				return 0, rowLabel + ": " + message, nil
+			}
+			index = len(pieces)
+			pieceIndex[key] = index
+			pieces = append(pieces, piece)
+		}
+
+		if row["spot_name"] == "" {
+			continue
+		}
+		spot := ImportExportSpot{
+			Name:       row["spot_name"],
+			Measures:   row["spot_measures"],
+			Stage:      row["spot_stage"],
+			TextPrompt: row["spot_text_prompt"],
+		}
+		tempo, ok := csvInt(row["spot_tempo"])
+		if !ok || tempo < 0 {
+			return 0, rowLabel + " has an invalid spot tempo", nil
+		}
+		spot.CurrentTempo = tempo
+		priority, ok := csvInt(row["spot_priority"])
+		if !ok {
+			return 0, rowLabel + " has an invalid spot priority", nil
+		}
+		spot.Priority = priority
+		pieces[index].Spots = append(pieces[index].Spots, spot)
+	}
+
+	for _, piece := range pieces {
+		if message := validateImportPiece(&piece); message != "" {
+			return 0, piece.Title + ": " + message, nil
+		}
+		if _, err := createImportedPiece(ctx, qtx, piece, userID); err != nil {
+			return 0, "", err
+		}
+	}
+	return len(pieces), "", nil
+}
+
+func csvBool(value string) bool {
+	switch strings.ToLower(value) {
+	case "1", "true", "yes", "y", "x", "done", "completed":
+		return true
+	default:
+		return false
+	}
+}
+
+func saveReadingCSVRows(ctx context.Context, qtx *db.Queries, userID string, rows []map[string]string) (int, string, error) {
+	for i, row := range rows {
+		if row["title"] == "" {
+			return 0, "Row " + strconv.Itoa(i+2) + " has no title", nil
+		}
+		if err := qtx.ImportReading(ctx, db.ImportReadingParams{
+			ID:        cuid2.Generate(),
+			Title:     row["title"],
+			Composer:  sql.NullString{String: row["composer"], Valid: row["composer"] != ""},
+			Info:      sql.NullString{String: row["info"], Valid: row["info"] != ""},
+			Completed: csvBool(row["completed"]),
+			UserID:    userID,
+		}); err != nil {
+			return 0, "", err
+		}
+	}
+	return len(rows), "", nil
+}
+
+// writeCSVDownload sends the records as a csv file attachment
+func writeCSVDownload(w http.ResponseWriter, filename string, records [][]string) {
+	w.Header().Set("Content-Type", "text/csv; charset=utf-8")
+	w.Header().Set("Content-Disposition", `attachment; filename="`+filename+`"`)
+	w.WriteHeader(http.StatusOK)
+	writer := csv.NewWriter(w)
+	if err := writer.WriteAll(records); err != nil {
+		log.Default().Println(err)
+	}
+}
+
+func csvHeaders(columns []csvColumn) []string {
+	headers := make([]string, 0, len(columns))
+	for _, column := range columns {
+		headers = append(headers, column.field.Label)
+	}
+	return headers
+}
+
+func nullIntText(value sql.NullInt64) string {
+	if !value.Valid {
+		return ""
+	}
+	return strconv.FormatInt(value.Int64, 10)
+}
+
+func (s *Server) exportPiecesCSV(w http.ResponseWriter, r *http.Request) {
+	user := r.Context().Value(ck.UserKey).(db.User)
+	queries := db.New(s.DB)
+	pieces, err := queries.ListUserPiecesForExport(r.Context(), user.ID)
+	if err != nil {
+		s.DatabaseError(w, r, err, "Could not export pieces")
+		return
+	}
+	spots, err := queries.ListUserSpotsForExport(r.Context(), user.ID)
+	if err != nil {
+		s.DatabaseError(w, r, err, "Could not export spots")
+		return
+	}
+	pieceSpots := make(map[string][]db.Spot, len(pieces))
+	for _, spot := range spots {
+		pieceSpots[spot.PieceID] = append(pieceSpots[spot.PieceID], spot)
+	}
+
+	records := [][]string{csvHeaders(pieceCSVColumns)}
+	for _, piece := range pieces {
+		pieceValues := []string{
+			piece.Title,
+			piece.Composer.String,
+			nullIntText(piece.Measures),
+			nullIntText(piece.BeatsPerMeasure),
+			nullIntText(piece.GoalTempo),
+		}
+		if len(pieceSpots[piece.ID]) == 0 {
+			records = append(records, append(pieceValues, "", "", "", "", "", ""))
+			continue
+		}
+		for _, spot := range pieceSpots[piece.ID] {
+			record := append([]string{}, pieceValues...)
+			record = append(record,
+				spot.Name,
+				spot.Measures.String,
+				spot.Stage,
+				nullIntText(spot.CurrentTempo),
+				strconv.FormatInt(spot.Priority, 10),
+				spot.TextPrompt,
+			)
+			records = append(records, record)
+		}
+	}
+	writeCSVDownload(w, "pieces-"+time.Now().Format("2006-01-02")+".csv", records)
+}
+
+func (s *Server) exportReadingCSV(w http.ResponseWriter, r *http.Request) {
+	user := r.Context().Value(ck.UserKey).(db.User)
+	queries := db.New(s.DB)
+	items, err := queries.ListAllUserReadingItems(r.Context(), user.ID)
+	if err != nil {
+		s.DatabaseError(w, r, err, "Could not export sight reading")
+		return
+	}
+	records := [][]string{csvHeaders(readingCSVColumns)}
+	for _, item := range items {
+		records = append(records, []string{
+			item.Title,
+			item.Composer.String,
+			item.Info.String,
+			strconv.FormatBool(item.Completed),
+		})
+	}
+	writeCSVDownload(w, "sight-reading-"+time.Now().Format("2006-01-02")+".csv", records)
+}
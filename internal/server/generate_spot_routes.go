@@ -0,0 +1,186 @@
+package server
+
+import (
+	"database/sql"
+	"errors"
+	"log"
+	"net/http"
+	"practicebetter/internal/ck"
+	"practicebetter/internal/config"
+	"practicebetter/internal/db"
+	"practicebetter/internal/measurerange"
+	"practicebetter/internal/pages/librarypages"
+	"strconv"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/gorilla/csrf"
+	"github.com/mavolin/go-htmx"
+	"github.com/nrednav/cuid2"
+)
+
+// generatedSpotRanges reads the generator form and returns the spots it describes, or a message for
+// the user if it can't
+func generatedSpotRanges(r *http.Request) ([]measurerange.Range, string) {
+	measures := 0
+	if value := r.Form.Get("measures"); value != "" {
+		var err error
+		measures, err = strconv.Atoi(value)
+		if err != nil || measures < 0 {
+			return nil, "The number of measures must be a positive number"
+		}
+		if measures > config.MAX_PIECE_MEASURES {
+			return nil, "Pieces can have at most " + strconv.Itoa(config.MAX_PIECE_MEASURES) + " measures"
+		}
+	}
+
+	var ranges []measurerange.Range
+	switch r.Form.Get("mode") {
+	case "ranges":
+		var err error
+		ranges, err = measurerange.ParseList(r.Form.Get("ranges"))
+		if err != nil {
+			return nil, err.Error()
+		}
+		if measures > 0 {
+			for _, measureRange := range ranges {
+				if measureRange.End > measures {
+					return nil, measureRange.String() + " goes past the end of the piece"
+				}
+			}
+		}
+	default:
+		if measures < 1 {
+			return nil, "Enter the number of measures in the piece"
+		}
+		size, err := strconv.Atoi(r.Form.Get("chunkSize"))
+		if err != nil || size < 1 {
+			return nil, "Measures per spot must be a positive number"
+		}
+		// check the count before making the spots, so a tiny chunk size can't make a huge list
+		count := measures / size
+		if measures%size != 0 {
+			count++
+		}
+		if count > config.MAX_GENERATED_SPOTS {
+			return nil, "That would make more than " + strconv.Itoa(config.MAX_GENERATED_SPOTS) + " spots"
+		}
+		ranges = measurerange.Chunks(measures, size)
+	}
+	if len(ranges) > config.MAX_GENERATED_SPOTS {
+		return nil, "That would make more than " + strconv.Itoa(config.MAX_GENERATED_SPOTS) + " spots"
+	}
+	return ranges, ""
+}
+
+func (s *Server) generateSpotsPage(w http.ResponseWriter, r *http.Request) {
+	user := r.Context().Value(ck.UserKey).(db.User)
+	pieceID := chi.URLParam(r, "pieceID")
+	queries := db.New(s.DB)
+	piece, err := queries.GetPieceWithoutSpots(r.Context(), db.GetPieceWithoutSpotsParams{
+		ID:     pieceID,
+		UserID: user.ID,
+	})
+	if err != nil {
+		log.Default().Println(err)
+		if err := htmx.Trigger(r, "ShowAlert", ShowAlertEvent{
+			Message:  "Could not find matching piece",
+			Title:    "Not Found",
+			Variant:  "error",
+			Duration: 3000,
+		}); err != nil {
+			log.Default().Println(err)
+		}
+		http.Error(w, "Could not find piece", http.StatusNotFound)
+		return
+	}
+	measures := ""
+	if piece.Measures.Valid {
+		measures = strconv.FormatInt(piece.Measures.Int64, 10)
+	}
+	s.HxRender(w, r, librarypages.GenerateSpotsPage(csrf.Token(r), piece.ID, piece.Title, measures), "Generate Spots")
+}
+
+func (s *Server) previewGeneratedSpots(w http.ResponseWriter, r *http.Request) {
+	if err := r.ParseForm(); err != nil {
+		log.Default().Println(err)
+		s.InvalidInputError(w, r, "Invalid input")
+		return
+	}
+	ranges, message := generatedSpotRanges(r)
+	w.Header().Set("Content-Type", "text/html")
+	if err := librarypages.GeneratedSpotsPreview(ranges, message).Render(r.Context(), w); err != nil {
+		log.Default().Println(err)
+		http.Error(w, "Render Error", http.StatusInternalServerError)
+	}
+}
+
+func (s *Server) generateSpots(w http.ResponseWriter, r *http.Request) {
+	user := r.Context().Value(ck.UserKey).(db.User)
+	pieceID := chi.URLParam(r, "pieceID")
+	if err := r.ParseForm(); err != nil {
+		log.Default().Println(err)
+		s.InvalidInputError(w, r, "Invalid input")
+		return
+	}
+	ranges, message := generatedSpotRanges(r)
+	if message != "" {
+		s.InvalidInputError(w, r, message)
+		return
+	}
+
+	tx, err := s.DB.BeginTx(r.Context(), nil)
+	if err != nil {
+		s.DatabaseError(w, r, err, "Could not create spots")
+		return
+	}
+	defer func() {
+		if err := tx.Rollback(); err != nil && !errors.Is(err, sql.ErrTxDone) {
+			log.Default().Println(err)
+		}
+	}()
+	qtx := db.New(s.DB).WithTx(tx)
+
+	piece, err := qtx.GetPieceWithoutSpots(r.Context(), db.GetPieceWithoutSpotsParams{
+		ID:     pieceID,
+		UserID: user.ID,
+	})
+	if err != nil {
+		s.DatabaseError(w, r, err, "Could not find piece")
+		return
+	}
+	for _, measureRange := range ranges {
+		if _, err := qtx.CreateSpot(r.Context(), db.CreateSpotParams{
+			ID:             cuid2.Generate(),
+			Name:           measureRange.Name(),
+			Stage:          "repeat",
+			Measures:       sql.NullString{String: measureRange.String(), Valid: true},
+			MeasuresStart:  sql.NullInt64{Int64: int64(measureRange.Start), Valid: true},
+			MeasuresEnd:    sql.NullInt64{Int64: int64(measureRange.End), Valid: true},
+			AudioPromptUrl: "",
+			ImagePromptUrl: "",
+			NotesPrompt:    "",
+			TextPrompt:     "",
+			CurrentTempo:   sql.NullInt64{Valid: false},
+			PieceID:        piece.ID,
+			UserID:         user.ID,
+		}); err != nil {
+			s.DatabaseError(w, r, err, "Could not create spots")
+			return
+		}
+	}
+	if err := tx.Commit(); err != nil {
+		s.DatabaseError(w, r, err, "Could not create spots")
+		return
+	}
+
+	if err := htmx.Trigger(r, "ShowAlert", ShowAlertEvent{
+		Message:  "Added " + strconv.Itoa(len(ranges)) + " spots to " + piece.Title,
+		Title:    "Spots Created",
+		Variant:  "success",
+		Duration: 3000,
+	}); err != nil {
+		log.Default().Println(err)
+	}
+	htmx.Redirect(r, "/library/pieces/"+piece.ID)
+	http.Redirect(w, r, "/library/pieces/"+piece.ID, http.StatusSeeOther)
+}
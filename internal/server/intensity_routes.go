@@ -0,0 +1,290 @@
+package server
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"log"
+	"net/http"
+	"practicebetter/internal/ck"
+	"practicebetter/internal/config"
+	"practicebetter/internal/db"
+	"practicebetter/internal/pages/authpages"
+	"practicebetter/internal/planner"
+	"strconv"
+	"strings"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/gorilla/csrf"
+	"github.com/mavolin/go-htmx"
+	"github.com/nrednav/cuid2"
+)
+
+// every user starts with the profiles that used to be hard coded
+var defaultIntensityProfiles = []db.CreateDefaultIntensityProfileParams{
+	{
+		Name:               "Light",
+		MaxNewSpots:        config.LIGHT_MAX_NEW_SPOTS,
+		MaxInterleaveSpots: config.LIGHT_MAX_INTERLEAVE_SPOTS,
+		MaxInfrequentSpots: config.LIGHT_MAX_INFREQUENT_SPOTS,
+		MaxSightReading:    config.LIGHT_SIGHT_READING,
+	},
+	{
+		Name:               "Medium",
+		MaxNewSpots:        config.MEDIUM_MAX_NEW_SPOTS,
+		MaxInterleaveSpots: config.MEDIUM_MAX_INTERLEAVE_SPOTS,
+		MaxInfrequentSpots: config.MEDIUM_MAX_INFREQUENT_SPOTS,
+		MaxSightReading:    config.MEDIUM_SIGHT_READING,
+	},
+	{
+		Name:               "Heavy",
+		MaxNewSpots:        config.HEAVY_MAX_NEW_SPOTS,
+		MaxInterleaveSpots: config.HEAVY_MAX_INTERLEAVE_SPOTS,
+		MaxInfrequentSpots: config.HEAVY_MAX_INFREQUENT_SPOTS,
+		MaxSightReading:    config.HEAVY_SIGHT_READING,
+	},
+}
+
+// getIntensityProfiles lists the user's intensity profiles, creating the defaults the first time.
+// Users from before profiles existed have their light/medium/heavy default moved to the new profile.
+// Two requests can both find no profiles, so the defaults skip names that already exist and the
+// profiles are listed again afterwards.
+func (s *Server) getIntensityProfiles(ctx context.Context, user db.User) ([]db.IntensityProfile, error) {
+	queries := db.New(s.DB)
+	profiles, err := queries.ListUserIntensityProfiles(ctx, user.ID)
+	if err != nil {
+		return nil, err
+	}
+	if len(profiles) > 0 {
+		return profiles, nil
+	}
+
+	tx, err := s.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		if err := tx.Rollback(); err != nil && !errors.Is(err, sql.ErrTxDone) {
+			log.Default().Println(err)
+		}
+	}()
+	qtx := queries.WithTx(tx)
+
+	for _, params := range defaultIntensityProfiles {
+		params.ID = cuid2.Generate()
+		params.UserID = user.ID
+		if err := qtx.CreateDefaultIntensityProfile(ctx, params); err != nil {
+			return nil, err
+		}
+	}
+	profiles, err = qtx.ListUserIntensityProfiles(ctx, user.ID)
+	if err != nil {
+		return nil, err
+	}
+	for _, profile := range profiles {
+		if strings.ToLower(profile.Name) == user.ConfigDefaultPlanIntensity {
+			if err := qtx.SetUserDefaultPlanIntensity(ctx, db.SetUserDefaultPlanIntensityParams{
+				ConfigDefaultPlanIntensity: profile.ID,
+				UserID:                     user.ID,
+				PreviousIntensity:          user.ConfigDefaultPlanIntensity,
+			}); err != nil {
+				return nil, err
+			}
+			break
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+	return profiles, nil
+}
+
+// the default profile is stored on the user, fall back to the first profile if it was deleted
+func defaultIntensityProfileID(user db.User, profiles []db.IntensityProfile) string {
+	if user.ConfigDefaultPlanIntensity == config.TIME_BUDGET_INTENSITY {
+		return config.TIME_BUDGET_INTENSITY
+	}
+	for _, profile := range profiles {
+		if profile.ID == user.ConfigDefaultPlanIntensity ||
+			strings.ToLower(profile.Name) == user.ConfigDefaultPlanIntensity {
+			return profile.ID
+		}
+	}
+	if len(profiles) > 0 {
+		return profiles[0].ID
+	}
+	return ""
+}
+
+func plannerSettingsFromProfile(profile db.IntensityProfile) planner.Settings {
+	return planner.Settings{
+		MaxNewSpots:        int(profile.MaxNewSpots),
+		MaxInterleaveSpots: int(profile.MaxInterleaveSpots),
+		MaxInfrequentSpots: int(profile.MaxInfrequentSpots),
+		MaxSightReading:    int(profile.MaxSightReading),
+	}
+}
+
+type intensityProfileForm struct {
+	Name               string
+	MaxNewSpots        int64
+	MaxInterleaveSpots int64
+	MaxInfrequentSpots int64
+	MaxSightReading    int64
+}
+
+func parseIntensityProfileForm(r *http.Request) (intensityProfileForm, string) {
+	var form intensityProfileForm
+	form.Name = strings.TrimSpace(r.Form.Get("name"))
+	if form.Name == "" {
+		return form, "Your profile needs a name"
+	}
+	fields := []struct {
+		key   string
+		label string
+		max   int64
+		dest  *int64
+	}{
+		{"max_new_spots", "new spots", 50, &form.MaxNewSpots},
+		{"max_interleave_spots", "interleave spots", 100, &form.MaxInterleaveSpots},
+		{"max_infrequent_spots", "infrequent spots", 100, &form.MaxInfrequentSpots},
+		{"max_sight_reading", "sight reading items", 20, &form.MaxSightReading},
+	}
+	for _, field := range fields {
+		value, err := strconv.ParseInt(r.Form.Get(field.key), 10, 64)
+		if err != nil || value < 0 || value > field.max {
+			return form, "The number of " + field.label + " must be between 0 and " + strconv.FormatInt(field.max, 10)
+		}
+		*field.dest = value
+	}
+	return form, ""
+}
+
+func (s *Server) renderIntensityProfiles(w http.ResponseWriter, r *http.Request, user db.User) {
+	profiles, err := s.getIntensityProfiles(r.Context(), user)
+	if err != nil {
+		s.DatabaseError(w, r, err, "Could not get intensity profiles")
+		return
+	}
+	token := csrf.Token(r)
+	if err := authpages.IntensityProfiles(profiles, token).Render(r.Context(), w); err != nil {
+		log.Default().Println(err)
+		return
+	}
+	// the settings form has a list of profiles, so it needs to be updated too
+	if err := authpages.UserSettingsFormOOB(user, profiles, defaultIntensityProfileID(user, profiles), token, true).Render(r.Context(), w); err != nil {
+		log.Default().Println(err)
+	}
+}
+
+func (s *Server) createIntensityProfile(w http.ResponseWriter, r *http.Request) {
+	user := r.Context().Value(ck.UserKey).(db.User)
+	if err := r.ParseForm(); err != nil {
+		log.Default().Println(err)
+		s.InvalidInputError(w, r, "Invalid input")
+		return
+	}
+	form, message := parseIntensityProfileForm(r)
+	if message != "" {
+		s.InvalidInputError(w, r, message)
+		return
+	}
+
+	queries := db.New(s.DB)
+	if _, err := queries.CreateIntensityProfile(r.Context(), db.CreateIntensityProfileParams{
+		ID:                 cuid2.Generate(),
+		UserID:             user.ID,
+		Name:               form.Name,
+		MaxNewSpots:        form.MaxNewSpots,
+		MaxInterleaveSpots: form.MaxInterleaveSpots,
+		MaxInfrequentSpots: form.MaxInfrequentSpots,
+		MaxSightReading:    form.MaxSightReading,
+	}); err != nil {
+		s.DatabaseError(w, r, err, "Could not create profile, make sure the name is unique")
+		return
+	}
+
+	if err := htmx.TriggerAfterSettle(r, "ShowAlert", ShowAlertEvent{
+		Message:  "Created intensity profile " + form.Name,
+		Title:    "Profile Created!",
+		Variant:  "success",
+		Duration: 3000,
+	}); err != nil {
+		log.Default().Println(err)
+	}
+	s.renderIntensityProfiles(w, r, user)
+}
+
+func (s *Server) updateIntensityProfile(w http.ResponseWriter, r *http.Request) {
+	user := r.Context().Value(ck.UserKey).(db.User)
+	profileID := chi.URLParam(r, "profileID")
+	if err := r.ParseForm(); err != nil {
+		log.Default().Println(err)
+		s.InvalidInputError(w, r, "Invalid input")
+		return
+	}
+	form, message := parseIntensityProfileForm(r)
+	if message != "" {
+		s.InvalidInputError(w, r, message)
+		return
+	}
+
+	queries := db.New(s.DB)
+	if _, err := queries.UpdateIntensityProfile(r.Context(), db.UpdateIntensityProfileParams{
+		Name:               form.Name,
+		MaxNewSpots:        form.MaxNewSpots,
+		MaxInterleaveSpots: form.MaxInterleaveSpots,
+		MaxInfrequentSpots: form.MaxInfrequentSpots,
+		MaxSightReading:    form.MaxSightReading,
+		ID:                 profileID,
+		UserID:             user.ID,
+	}); err != nil {
+		s.DatabaseError(w, r, err, "Could not update profile, make sure the name is unique")
+		return
+	}
+
+	if err := htmx.TriggerAfterSettle(r, "ShowAlert", ShowAlertEvent{
+		Message:  "Updated intensity profile " + form.Name,
+		Title:    "Profile Updated!",
+		Variant:  "success",
+		Duration: 3000,
+	}); err != nil {
+		log.Default().Println(err)
+	}
+	s.renderIntensityProfiles(w, r, user)
+}
+
+func (s *Server) deleteIntensityProfile(w http.ResponseWriter, r *http.Request) {
+	user := r.Context().Value(ck.UserKey).(db.User)
+	profileID := chi.URLParam(r, "profileID")
+
+	profiles, err := s.getIntensityProfiles(r.Context(), user)
+	if err != nil {
+		s.DatabaseError(w, r, err, "Could not get intensity profiles")
+		return
+	}
+	if len(profiles) < 2 {
+		s.InvalidInputError(w, r, "You need at least one intensity profile")
+		return
+	}
+
+	queries := db.New(s.DB)
+	if err := queries.DeleteIntensityProfile(r.Context(), db.DeleteIntensityProfileParams{
+		ID:     profileID,
+		UserID: user.ID,
+	}); err != nil {
+		s.DatabaseError(w, r, err, "Could not delete profile")
+		return
+	}
+
+	if err := htmx.TriggerAfterSettle(r, "ShowAlert", ShowAlertEvent{
+		Message:  "Deleted intensity profile",
+		Title:    "Profile Deleted",
+		Variant:  "success",
+		Duration: 3000,
+	}); err != nil {
+		log.Default().Println(err)
+	}
+	s.renderIntensityProfiles(w, r, user)
+}
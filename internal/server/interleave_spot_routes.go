@@ -13,6 +13,8 @@ import (
 	"practicebetter/internal/db"
 	"practicebetter/internal/pages/librarypages"
 	"practicebetter/internal/pages/planpages"
+	"practicebetter/internal/scheduler"
+	"practicebetter/internal/stages"
 	"time"
 
 	"github.com/go-chi/chi/v5"
@@ -352,6 +354,11 @@ func (s *Server) saveInterleaveInList(w http.ResponseWriter, r *http.Request, sp
 			return
 		}
 
+		if err := recordSpotEvaluation(r.Context(), qtx, userID, spotID, "interleave", evaluation, planID); err != nil {
+			s.DatabaseError(w, r, err, "Could not save spot history")
+			return
+		}
+
 		if err := qtx.UpdatePiecePracticed(r.Context(), db.UpdatePiecePracticedParams{
 			UserID:  userID,
 			PieceID: pieceID,
@@ -524,6 +531,11 @@ func (s *Server) saveInterleaveSingle(w http.ResponseWriter, r *http.Request, sp
 			return
 		}
 
+		if err := recordSpotEvaluation(r.Context(), qtx, userID, spotID, "interleave", evaluation, planID); err != nil {
+			s.DatabaseError(w, r, err, "Could not save spot history")
+			return
+		}
+
 		if err := qtx.UpdatePiecePracticed(r.Context(), db.UpdatePiecePracticedParams{
 			UserID:  userID,
 			PieceID: pieceID,
@@ -840,13 +852,7 @@ func (s *Server) saveInfrequentResult(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	skipDays := finishedSpot.SkipDays
-
-	var timeSinceStarted time.Duration
-	if finishedSpot.StageStarted.Valid {
-		timeSinceStarted = time.Since(time.Unix(finishedSpot.StageStarted.Int64, 0))
-	} else {
-		timeSinceStarted = 0
+	if !finishedSpot.StageStarted.Valid {
 		err := qtx.FixSpotStageStarted(r.Context(), db.FixSpotStageStartedParams{
 			SpotID: finishedSpot.ID,
 			UserID: user.ID,
@@ -857,55 +863,57 @@ func (s *Server) saveInfrequentResult(w http.ResponseWriter, r *http.Request) {
 		}
 	}
 
-	// excellent and more than three days old and days is less than 7, double the skip time
-	if evaluation == "excellent" &&
-		finishedSpot.StageStarted.Valid &&
-		timeSinceStarted.Hours() > 4*24 &&
-		finishedSpot.SkipDays < 7 {
-		skipDays *= 2
-		err := qtx.UpdateSpotSkipDaysAndPractice(r.Context(), db.UpdateSpotSkipDaysAndPracticeParams{
-			SkipDays: skipDays,
-			SpotID:   finishedSpot.ID,
-			UserID:   user.ID,
+	// the history has to be loaded before this evaluation is saved
+	evaluations, err := qtx.ListSpotStageEvaluations(r.Context(), db.ListSpotStageEvaluationsParams{
+		SpotID:       finishedSpot.ID,
+		UserID:       user.ID,
+		PracticeType: sql.NullString{String: "interleave_days", Valid: true},
+	})
+	if err != nil {
+		s.DatabaseError(w, r, err, "Could not get spot history")
+		return
+	}
+	reviews := make([]scheduler.Review, 0, len(evaluations))
+	for _, row := range evaluations {
+		reviews = append(reviews, scheduler.Review{
+			Evaluation: row.Evaluation.String,
+			Date:       time.Unix(row.Date, 0),
 		})
-		if err != nil {
-			s.DatabaseError(w, r, err, "Could not update spot")
+	}
+
+	if evaluation == "excellent" || evaluation == "fine" || evaluation == "poor" {
+		if err := recordSpotEvaluation(r.Context(), qtx, user.ID, finishedSpot.ID, "interleave_days", evaluation, planID); err != nil {
+			s.DatabaseError(w, r, err, "Could not save spot history")
 			return
 		}
-		// poor quality resets days to 1 or demotes immediately
-	} else if evaluation == "poor" {
-		if skipDays < 2 {
-			err := qtx.DemoteSpotToInterleave(r.Context(), db.DemoteSpotToInterleaveParams{
-				SpotID: finishedSpot.ID,
-				UserID: user.ID,
-			})
-			if err != nil {
-				s.DatabaseError(w, r, err, "Could not demote spot")
-				return
-			}
-		} else {
-			skipDays = 1
-			err := qtx.UpdateSpotSkipDaysAndPractice(r.Context(), db.UpdateSpotSkipDaysAndPracticeParams{
-				SkipDays: skipDays,
-				SpotID:   finishedSpot.ID,
-				UserID:   user.ID,
-			})
-			if err != nil {
-				s.DatabaseError(w, r, err, "Could not update spot")
-				return
-			}
+	}
+
+	// the scheduler verifies the conditions for demoting and completing, the client isn't trusted
+	spot := scheduler.NewSpot(finishedSpot.SkipDays, finishedSpot.LastPracticed, finishedSpot.StageStarted, reviews)
+	result := scheduler.Get(user.ConfigScheduler).Review(spot, evaluation, time.Now())
+	rules, err := getStageRules(r.Context(), qtx, user.ID)
+	if err != nil {
+		s.DatabaseError(w, r, err, "Could not get stage rules")
+		return
+	}
+	toStage := stages.New(rules, time.Now().In(userLocation(user))).Infrequent(finishedSpot.Stage, result.Outcome)
+	switch {
+	case toStage != finishedSpot.Stage:
+		if err := applyStageChange(r.Context(), qtx, user.ID, finishedSpot.ID, finishedSpot.Stage, toStage); err != nil {
+			s.DatabaseError(w, r, err, "Could not update spot stage")
+			return
 		}
-		// completed promotes to completed, also have to verify conditions and not trust the client
-	} else if evaluation == "excellent" && skipDays > 6 && timeSinceStarted.Hours() > 20*24 {
-		_, err := qtx.PromoteSpotToCompleted(r.Context(), db.PromoteSpotToCompletedParams{
-			SpotID: finishedSpot.ID,
-			UserID: user.ID,
+	case result.SkipDays != finishedSpot.SkipDays:
+		err := qtx.UpdateSpotSkipDaysAndPractice(r.Context(), db.UpdateSpotSkipDaysAndPracticeParams{
+			SkipDays: result.SkipDays,
+			SpotID:   finishedSpot.ID,
+			UserID:   user.ID,
 		})
 		if err != nil {
-			s.DatabaseError(w, r, err, "Could not promote spot")
+			s.DatabaseError(w, r, err, "Could not update spot")
 			return
 		}
-	} else {
+	default:
 		if err := qtx.UpdateSpotPracticed(r.Context(), db.UpdateSpotPracticedParams{
 			SpotID: finishedSpot.ID,
 			UserID: user.ID,
@@ -915,6 +923,11 @@ func (s *Server) saveInfrequentResult(w http.ResponseWriter, r *http.Request) {
 		}
 	}
 
+	if err := recordSpotStageChange(r.Context(), qtx, user.ID, finishedSpot.ID, finishedSpot.Stage, planID); err != nil {
+		s.DatabaseError(w, r, err, "Could not save spot history")
+		return
+	}
+
 	if err := qtx.UpdatePiecePracticed(r.Context(), db.UpdatePiecePracticedParams{
 		UserID:  user.ID,
 		PieceID: pieceID,
@@ -17,6 +17,7 @@ import (
 	"practicebetter/internal/db"
 	"practicebetter/internal/pages/librarypages"
 	"strings"
+	"time"
 
 	"github.com/gabriel-vasile/mimetype"
 	"github.com/gorilla/csrf"
@@ -76,7 +77,17 @@ func (s *Server) libraryDashboard(w http.ResponseWriter, r *http.Request) {
 		recentPlanInfo = append(recentPlanInfo, nextPlanInfo)
 	}
 
-	s.HxRender(w, r, librarypages.Dashboard(s, pieces, hasPlan, activePlan, recentPlanInfo), "Library")
+	var stats librarypages.DashboardStats
+	calendar, err := practiceCalendar(r.Context(), queries, user.ID, userLocation(user))
+	if err != nil {
+		log.Default().Println(err)
+	} else {
+		now := time.Now()
+		stats.CurrentStreak, _ = calendar.Streaks(now)
+		stats.WeekMinutes = calendar.WeekMinutes(now)
+	}
+
+	s.HxRender(w, r, librarypages.Dashboard(s, pieces, hasPlan, activePlan, recentPlanInfo, stats), "Library")
 }
 
 type PieceFormData struct {
@@ -103,6 +114,13 @@ type SpotFormData struct {
 	StageStarted   *int64  `json:"stageStarted,omitempty"`
 }
 
+// userUploadsDir is the name of the folder in the uploads path that holds the user's files
+func userUploadsDir(userID string) string {
+	h := sha256.New()
+	h.Write([]byte(userID))
+	return hex.EncodeToString(h.Sum(nil))[:8]
+}
+
 func (s *Server) saveAudio(file multipart.File, fileHeader *multipart.FileHeader, userID string) (string, string, error) {
 	buff := make([]byte, 512)
 	_, err := file.Read(buff)
@@ -125,9 +143,7 @@ func (s *Server) saveAudio(file multipart.File, fileHeader *multipart.FileHeader
 
 	// Create the uploads folder if it doesn't
 	// already exist
-	h := sha256.New()
-	h.Write([]byte(userID))
-	userIDHash := hex.EncodeToString(h.Sum(nil))[:8]
+	userIDHash := userUploadsDir(userID)
 
 	userAudioPath := path.Join(s.UploadsPath, userIDHash, "audio")
 	err = os.MkdirAll(userAudioPath, os.ModePerm)
@@ -221,9 +237,7 @@ func (s *Server) saveImage(file multipart.File, fileHeader *multipart.FileHeader
 
 	// Create the uploads folder if it doesn't
 	// already exist
-	h := sha256.New()
-	h.Write([]byte(userID))
-	userIDHash := hex.EncodeToString(h.Sum(nil))[:8]
+	userIDHash := userUploadsDir(userID)
 
 	userImagePath := path.Join(s.UploadsPath, userIDHash, "images")
 	err = os.MkdirAll(userImagePath, os.ModePerm)
@@ -0,0 +1,108 @@
+package server
+
+import (
+	"practicebetter/internal/config"
+	"practicebetter/internal/measurerange"
+	"practicebetter/internal/pages/librarypages"
+	"practicebetter/internal/stages"
+	"slices"
+	"sort"
+	"strconv"
+)
+
+type coverageSpot struct {
+	Name  string
+	Stage string
+	// Range is the zero range when the spot's measures couldn't be read
+	Range measurerange.Range
+}
+
+// numberedMeasures makes an entry for every measure of a piece, numbered from 1. Pieces saved before
+// measures were limited can have any number of them, so those get no entries at all.
+func numberedMeasures[T any](measures int, number func(measure *T, n int)) []T {
+	if measures < 1 || measures > config.MAX_PIECE_MEASURES {
+		return make([]T, 0)
+	}
+	list := make([]T, measures)
+	for i := range list {
+		number(&list[i], i+1)
+	}
+	return list
+}
+
+// measureCoverage works out which measures of the piece are in a spot, marking each one with the
+// earliest stage of the spots that cover it
+func measureCoverage(measures int, spots []coverageSpot) librarypages.MeasureCoverage {
+	coverage := librarypages.MeasureCoverage{
+		Measures: numberedMeasures(measures, func(measure *librarypages.CoveredMeasure, n int) {
+			measure.Number = n
+		}),
+		Overlaps: make([]string, 0),
+		PastEnd:  make([]string, 0),
+	}
+	measures = len(coverage.Measures)
+
+	readable := make([]coverageSpot, 0, len(spots))
+	for _, spot := range spots {
+		if spot.Range.Start < 1 {
+			coverage.Unreadable++
+			continue
+		}
+		readable = append(readable, spot)
+		if spot.Range.End > measures {
+			coverage.PastEnd = append(coverage.PastEnd, spot.Name)
+		}
+		for n := spot.Range.Start; n <= min(spot.Range.End, measures); n++ {
+			measure := &coverage.Measures[n-1]
+			measure.Spots = append(measure.Spots, spot.Name)
+			if measure.Stage == "" || slices.Index(stages.All, spot.Stage) < slices.Index(stages.All, measure.Stage) {
+				measure.Stage = spot.Stage
+			}
+		}
+	}
+
+	coverage.Uncovered = measureRuns(coverage.Measures, func(measure librarypages.CoveredMeasure) bool {
+		return len(measure.Spots) == 0
+	})
+
+	sort.SliceStable(readable, func(i, j int) bool {
+		return readable[i].Range.Start < readable[j].Range.Start
+	})
+	for i, first := range readable {
+		for _, second := range readable[i+1:] {
+			if second.Range.Start > first.Range.End {
+				break
+			}
+			shared := measurerange.Range{Start: second.Range.Start, End: min(first.Range.End, second.Range.End)}
+			coverage.Overlaps = append(coverage.Overlaps, first.Name+" and "+second.Name+" share "+sharedMeasures(shared))
+		}
+	}
+	return coverage
+}
+
+func sharedMeasures(r measurerange.Range) string {
+	if r.Start == r.End {
+		return "m. " + strconv.Itoa(r.Start)
+	}
+	return "mm. " + r.String()
+}
+
+// measureRuns finds the runs of matching measures, like "1-8" and "12". The first entry is measure 1.
+func measureRuns[T any](measures []T, match func(T) bool) []string {
+	runs := make([]string, 0)
+	start := 0
+	for i, measure := range measures {
+		if match(measure) {
+			if start == 0 {
+				start = i + 1
+			}
+		} else if start > 0 {
+			runs = append(runs, measurerange.Range{Start: start, End: i}.String())
+			start = 0
+		}
+	}
+	if start > 0 {
+		runs = append(runs, measurerange.Range{Start: start, End: len(measures)}.String())
+	}
+	return runs
+}
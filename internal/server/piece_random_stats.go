@@ -0,0 +1,57 @@
+package server
+
+import (
+	"practicebetter/internal/db"
+	"practicebetter/internal/pages/librarypages"
+	"practicebetter/internal/stages"
+)
+
+// randomPracticeStats totals up the saved random spots evaluations for each spot, in the same order
+// as the piece's spots. Excellent ratings in a row only count for spots still in random practice,
+// since that's what promotes them.
+func randomPracticeStats(evaluations []db.ListPieceRandomEvaluationsRow, spots []librarypages.PiecePageSpot, rules db.StageRule) librarypages.RandomPracticeStats {
+	bySpot := make(map[string]*librarypages.RandomSpotStats, len(spots))
+	for _, row := range evaluations {
+		stats, ok := bySpot[row.SpotID]
+		if !ok {
+			stats = &librarypages.RandomSpotStats{SpotID: row.SpotID}
+			bySpot[row.SpotID] = stats
+		}
+		stats.Reps++
+		stats.LastPracticed = max(stats.LastPracticed, row.Date)
+		switch row.Evaluation.String {
+		case "excellent":
+			stats.Excellent++
+		case "fine":
+			stats.Fine++
+		case "poor":
+			stats.Poor++
+		}
+		// rows are oldest first, so this ends up as the streak at the end of the current stage
+		if row.Date >= row.SpotStageStarted.Int64 {
+			if row.Evaluation.String == "excellent" {
+				stats.InARow++
+			} else {
+				stats.InARow = 0
+			}
+		}
+	}
+
+	practiceStats := librarypages.RandomPracticeStats{
+		Spots:            make([]librarypages.RandomSpotStats, 0, len(bySpot)),
+		PromoteExcellent: rules.RandomPromoteExcellent,
+	}
+	for _, spot := range spots {
+		stats, ok := bySpot[spot.ID]
+		if !ok {
+			continue
+		}
+		stats.Name = spot.Name
+		stats.Stage = spot.Stage
+		if spot.Stage != stages.Random {
+			stats.InARow = 0
+		}
+		practiceStats.Spots = append(practiceStats.Spots, *stats)
+	}
+	return practiceStats
+}
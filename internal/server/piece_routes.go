@@ -5,15 +5,22 @@ import (
 	"database/sql"
 	"encoding/json"
 	"errors"
+	"io"
 	"log"
 	"math"
 	"net/http"
+	"path/filepath"
 	"practicebetter/internal/ck"
 	"practicebetter/internal/config"
 	"practicebetter/internal/db"
+	"practicebetter/internal/measurerange"
 	"practicebetter/internal/pages/librarypages"
+	"practicebetter/internal/scoreimport"
+	"practicebetter/internal/stages"
+	"slices"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/go-chi/chi/v5"
 	"github.com/gorilla/csrf"
@@ -49,6 +56,10 @@ func (s *Server) createPiece(w http.ResponseWriter, r *http.Request) {
 	if err != nil {
 		measures = sql.NullInt64{Valid: false}
 	}
+	if message := pieceMeasuresMessage(measures.Int64); message != "" {
+		s.InvalidInputError(w, r, message)
+		return
+	}
 	b, err := strconv.Atoi(r.Form.Get("beats"))
 	beatsPerMeasure := sql.NullInt64{Int64: int64(b), Valid: true}
 	if err != nil {
@@ -174,6 +185,7 @@ func (s *Server) renderPiece(w http.ResponseWriter, r *http.Request, pieceID str
 		},
 		Spots: make([]librarypages.PiecePageSpot, 0, len(piece)),
 	}
+	coverageSpots := make([]coverageSpot, 0, len(piece))
 	for _, row := range piece {
 		if !row.SpotID.Valid || !row.SpotStage.Valid || !row.SpotName.Valid {
 			continue
@@ -210,13 +222,115 @@ func (s *Server) renderPiece(w http.ResponseWriter, r *http.Request, pieceID str
 			}
 		}
 		pieceInfo.Spots = append(pieceInfo.Spots, spotInfo)
-
+		coverage := coverageSpot{Name: spotInfo.Name, Stage: spotInfo.Stage}
+		if row.SpotMeasuresStart.Valid && row.SpotMeasuresEnd.Valid {
+			coverage.Range = measurerange.Range{
+				Start: int(row.SpotMeasuresStart.Int64),
+				End:   int(row.SpotMeasuresEnd.Int64),
+			}
+		}
+		coverageSpots = append(coverageSpots, coverage)
+	}
+	// pieces saved before measures were limited could have any number of them
+	if pieceInfo.Measures.Valid && pieceInfo.Measures.Int64 > 0 && pieceInfo.Measures.Int64 <= config.MAX_PIECE_MEASURES && len(coverageSpots) > 0 {
+		pieceInfo.Coverage = measureCoverage(int(pieceInfo.Measures.Int64), coverageSpots)
+	}
+	sections, err := queries.ListPieceSections(r.Context(), db.ListPieceSectionsParams{
+		UserID:  userID,
+		PieceID: pieceID,
+	})
+	if err != nil {
+		s.DatabaseError(w, r, err, "Could not get sections")
+		return
+	}
+	if len(sections) > 0 {
+		spotsSections, err := queries.ListPieceSpotsSections(r.Context(), db.ListPieceSpotsSectionsParams{
+			UserID:  userID,
+			PieceID: pieceID,
+		})
+		if err != nil {
+			s.DatabaseError(w, r, err, "Could not get section spots")
+			return
+		}
+		groupSpotsBySection(&pieceInfo, sections, spotsSections)
+	}
+	measures := 0
+	if pieceInfo.Measures.Valid {
+		measures = int(pieceInfo.Measures.Int64)
+	}
+	pieceInfo.StartingPoints, err = startingPointHistory(r.Context(), queries, userID, pieceID, measures)
+	if err != nil {
+		s.DatabaseError(w, r, err, "Could not get starting point practice")
+		return
+	}
+	randomEvaluations, err := queries.ListPieceRandomEvaluations(r.Context(), db.ListPieceRandomEvaluationsParams{
+		UserID:  userID,
+		PieceID: pieceID,
+	})
+	if err != nil {
+		s.DatabaseError(w, r, err, "Could not get random practice")
+		return
+	}
+	if len(randomEvaluations) > 0 {
+		rules, err := getStageRules(r.Context(), queries, userID)
+		if err != nil {
+			s.DatabaseError(w, r, err, "Could not get stage rules")
+			return
+		}
+		pieceInfo.RandomPractice = randomPracticeStats(randomEvaluations, pieceInfo.Spots, rules)
+	}
+	spotTempos, err := queries.ListPieceSpotTempos(r.Context(), db.ListPieceSpotTemposParams{
+		PieceID: pieceID,
+		UserID:  userID,
+	})
+	if err != nil {
+		s.DatabaseError(w, r, err, "Could not get tempo history")
+		return
+	}
+	pieceInfo.TempoProgress = pieceTempoProgress(spotTempos, pieceInfo.Spots, pieceInfo.GoalTempo.Int64)
+	user := r.Context().Value(ck.UserKey).(db.User)
+	pieceInfo.Readiness, err = pieceReadiness(r.Context(), queries, userID, pieceID, userLocation(user))
+	if err != nil {
+		s.DatabaseError(w, r, err, "Could not get stage history")
+		return
 	}
 	log.Default().Println(pieceInfo.LastPracticed.Int64)
 	token := csrf.Token(r)
 	s.HxRender(w, r, librarypages.SinglePiece(s, pieceInfo, token), pieceInfo.Title)
 }
 
+// groupSpotsBySection puts each spot under every section it belongs to, keeping the piece order
+func groupSpotsBySection(pieceInfo *librarypages.SinglePieceInfo, sections []db.Section, spotsSections []db.ListPieceSpotsSectionsRow) {
+	spotSections := make(map[string][]string)
+	for _, row := range spotsSections {
+		spotSections[row.SpotID] = append(spotSections[row.SpotID], row.SectionID)
+	}
+	sectionIndex := make(map[string]int, len(sections))
+	pieceInfo.Sections = make([]librarypages.PiecePageSection, 0, len(sections))
+	for i, section := range sections {
+		sectionIndex[section.ID] = i
+		pieceInfo.Sections = append(pieceInfo.Sections, librarypages.PiecePageSection{
+			ID:       section.ID,
+			Name:     section.Name,
+			Measures: librarypages.SectionMeasures(section),
+			Spots:    make([]librarypages.PiecePageSpot, 0),
+		})
+	}
+	pieceInfo.UnsectionedSpots = make([]librarypages.PiecePageSpot, 0)
+	for _, spot := range pieceInfo.Spots {
+		found := false
+		for _, sectionID := range spotSections[spot.ID] {
+			if i, ok := sectionIndex[sectionID]; ok {
+				pieceInfo.Sections[i].Spots = append(pieceInfo.Sections[i].Spots, spot)
+				found = true
+			}
+		}
+		if !found {
+			pieceInfo.UnsectionedSpots = append(pieceInfo.UnsectionedSpots, spot)
+		}
+	}
+}
+
 func getSpotBreakdown(piece []db.GetPieceByIDRow) librarypages.PieceSpotsBreakdown {
 	breakdown := librarypages.PieceSpotsBreakdown{
 		Repeat:      0,
@@ -428,6 +542,10 @@ func (s *Server) updatePiece(w http.ResponseWriter, r *http.Request) {
 	if err != nil {
 		measures = sql.NullInt64{Valid: false}
 	}
+	if message := pieceMeasuresMessage(measures.Int64); message != "" {
+		s.InvalidInputError(w, r, message)
+		return
+	}
 	b, err := strconv.Atoi(r.Form.Get("beats"))
 	beatsPerMeasure := sql.NullInt64{Int64: int64(b), Valid: true}
 	if err != nil {
@@ -494,6 +612,32 @@ func (s *Server) piecePracticeRandomSpotsPage(w http.ResponseWriter, r *http.Req
 
 		return
 	}
+	if section, ok := s.getPracticeSection(r, pieceID, user.ID); ok {
+		spotIDs, err := queries.ListSectionSpotIDs(r.Context(), db.ListSectionSpotIDsParams{
+			SectionID: section.ID,
+			UserID:    user.ID,
+			PieceID:   pieceID,
+		})
+		if err != nil {
+			s.DatabaseError(w, r, err, "Could not get section spots")
+			return
+		}
+		inSection := make(map[string]bool, len(spotIDs))
+		for _, spotID := range spotIDs {
+			inSection[spotID] = true
+		}
+		sectionSpots := make([]db.GetPieceWithRandomSpotsRow, 0, len(piece))
+		for _, row := range piece {
+			if inSection[row.SpotID] {
+				sectionSpots = append(sectionSpots, row)
+			}
+		}
+		if len(sectionSpots) == 0 {
+			s.HxRender(w, r, librarypages.PiecePracticeNoSpotsPage(piece[0].Title, piece[0].ID), piece[0].Title)
+			return
+		}
+		piece = sectionSpots
+	}
 	// TODO: unfuck this and get rid of the goddamn pointers
 	var spots []SpotFormData
 	for _, row := range piece {
@@ -542,14 +686,33 @@ func (s *Server) piecePracticeRandomSpotsPage(w http.ResponseWriter, r *http.Req
 		http.Error(w, err.Error(), http.StatusInternalServerError)
 		return
 	}
+	// the client uses the rules to recommend promotions and demotions
+	rules, err := getStageRules(r.Context(), queries, user.ID)
+	if err != nil {
+		s.DatabaseError(w, r, err, "Could not get stage rules")
+		return
+	}
+	rulesData, err := json.Marshal(rules)
+	if err != nil {
+		log.Default().Println(err)
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
 	token := csrf.Token(r)
-	s.HxRender(w, r, librarypages.PiecePracticeRandomSpotsPage(s, token, piece, string(spotsData)), piece[0].Title)
+	s.HxRender(w, r, librarypages.PiecePracticeRandomSpotsPage(s, token, piece, string(spotsData), string(rulesData)), piece[0].Title)
 }
 
 type PracticeSpot struct {
-	ID      string `json:"id"`
-	Promote bool   `json:"promote"`
-	Demote  bool   `json:"demote"`
+	ID        string `json:"id"`
+	Promote   bool   `json:"promote"`
+	Demote    bool   `json:"demote"`
+	Excellent int64  `json:"excellent"`
+	Fine      int64  `json:"fine"`
+	Poor      int64  `json:"poor"`
+	// Evaluations are in the order they were given, older clients only send the counts
+	Evaluations []string `json:"evaluations"`
+	// Keep is set when the practicer turned down moving the spot
+	Keep bool `json:"keep"`
 }
 
 type PieceSpotsPracticeInfo struct {
@@ -566,6 +729,14 @@ func (s *Server) finishPracticePieceSpots(w http.ResponseWriter, r *http.Request
 		http.Error(w, err.Error(), http.StatusBadRequest)
 		return
 	}
+	for _, spot := range info.Spots {
+		for _, evaluation := range spot.Evaluations {
+			if evaluation != "poor" && evaluation != "fine" && evaluation != "excellent" {
+				http.Error(w, "Invalid evaluation", http.StatusBadRequest)
+				return
+			}
+		}
+	}
 
 	queries := db.New(s.DB)
 	tx, err := s.DB.Begin()
@@ -580,6 +751,18 @@ func (s *Server) finishPracticePieceSpots(w http.ResponseWriter, r *http.Request
 	}()
 
 	qtx := queries.WithTx(tx)
+	// make sure the piece is the user's before saving anything against it
+	if _, err := qtx.GetPieceWithoutSpots(r.Context(), db.GetPieceWithoutSpotsParams{
+		ID:     pieceID,
+		UserID: user.ID,
+	}); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			http.Error(w, "Could not find matching piece", http.StatusNotFound)
+			return
+		}
+		s.DatabaseError(w, r, err, "Could not get piece")
+		return
+	}
 
 	activePracticePlanID, ok := s.GetActivePracticePlanID(r.Context())
 	if ok && activePracticePlanID != "" {
@@ -602,38 +785,71 @@ func (s *Server) finishPracticePieceSpots(w http.ResponseWriter, r *http.Request
 		}
 	}
 
+	if info.DurationMinutes > 0 {
+		if err := qtx.CreatePracticeSession(r.Context(), db.CreatePracticeSessionParams{
+			ID:              cuid2.Generate(),
+			UserID:          user.ID,
+			PracticeType:    "random_spots",
+			DurationMinutes: info.DurationMinutes,
+			PieceID:         sql.NullString{String: pieceID, Valid: true},
+			PracticePlanID:  sql.NullString{String: activePracticePlanID, Valid: ok && activePracticePlanID != ""},
+		}); err != nil {
+			s.DatabaseError(w, r, err, "Could not save practice session")
+			return
+		}
+	}
+
+	rules, err := getStageRules(r.Context(), qtx, user.ID)
+	if err != nil {
+		s.DatabaseError(w, r, err, "Could not get stage rules")
+		return
+	}
+	engine := stages.New(rules, time.Now().In(userLocation(user)))
+
 	for _, spot := range info.Spots {
-		if spot.Promote {
-			if err := qtx.PromoteSpotToInterleave(r.Context(), db.PromoteSpotToInterleaveParams{
-				SpotID:  spot.ID,
-				UserID:  user.ID,
-				PieceID: pieceID,
-			}); err != nil {
-				log.Default().Println(err)
-				http.Error(w, "Could not promote spot", http.StatusInternalServerError)
-				return
-			}
-		} else if spot.Demote {
-			if err := qtx.DemoteSpotToExtraRepeat(r.Context(), db.DemoteSpotToExtraRepeatParams{
-				SpotID:  spot.ID,
-				UserID:  user.ID,
-				PieceID: pieceID,
-			}); err != nil {
-				log.Default().Println(err)
-				http.Error(w, "Could not demote spot", http.StatusInternalServerError)
+		spotStage, err := qtx.GetSpotStageStarted(r.Context(), db.GetSpotStageStartedParams{
+			SpotID:  spot.ID,
+			UserID:  user.ID,
+			PieceID: pieceID,
+		})
+		if err != nil {
+			// the spot isn't in this piece, or isn't the user's
+			if errors.Is(err, sql.ErrNoRows) {
+				http.Error(w, "Could not find matching spot", http.StatusNotFound)
 				return
 			}
-		} else {
-			if err := qtx.UpdateSpotPracticed(r.Context(), db.UpdateSpotPracticedParams{
-				SpotID: spot.ID,
-				UserID: user.ID,
-			}); err != nil {
-				log.Default().Println(err)
-				http.Error(w, "Could not update spot", http.StatusInternalServerError)
+			s.DatabaseError(w, r, err, "Could not get spot")
+			return
+		}
+		fromStage := spotStage.Stage
+		var stageStarted time.Time
+		if spotStage.StageStarted.Valid {
+			stageStarted = time.Unix(spotStage.StageStarted.Int64, 0)
+		}
+		result := stages.RandomResult{
+			Excellent: spot.Excellent,
+			Fine:      spot.Fine,
+			Poor:      spot.Poor,
+			Promote:   spot.Promote,
+			Demote:    spot.Demote,
+		}
+		if len(spot.Evaluations) > 0 {
+			result, err = saveRandomEvaluations(r.Context(), qtx, user.ID, spot, activePracticePlanID)
+			if err != nil {
+				s.DatabaseError(w, r, err, "Could not save spot history")
 				return
 			}
 		}
-
+		toStage := engine.RandomSpots(fromStage, result, stageStarted)
+		if err := applyStageChange(r.Context(), qtx, user.ID, spot.ID, fromStage, toStage); err != nil {
+			log.Default().Println(err)
+			http.Error(w, "Could not update spot", http.StatusInternalServerError)
+			return
+		}
+		if err := recordSpotStageChange(r.Context(), qtx, user.ID, spot.ID, fromStage, activePracticePlanID); err != nil {
+			s.DatabaseError(w, r, err, "Could not save spot history")
+			return
+		}
 	}
 	if err := tx.Commit(); err != nil {
 		s.DatabaseError(w, r, err, "Could not save changes")
@@ -646,6 +862,44 @@ func (s *Server) finishPracticePieceSpots(w http.ResponseWriter, r *http.Request
 	}
 }
 
+// saveRandomEvaluations records each rep of a spot from a random spots session and returns the
+// result to decide its stage from. The counts come from the saved evaluations rather than the client,
+// and promotion goes by the excellent ratings in a row since the spot became a random spot.
+func saveRandomEvaluations(ctx context.Context, qtx *db.Queries, userID string, spot PracticeSpot, planID string) (stages.RandomResult, error) {
+	result := stages.RandomResult{
+		// the spot only moves when the client recommended it and the practicer didn't keep it where it
+		// is, the evaluations still have to meet the rules
+		Promote: spot.Promote && !spot.Keep,
+		Demote:  spot.Demote && !spot.Keep,
+	}
+	for _, evaluation := range spot.Evaluations {
+		if err := recordSpotEvaluation(ctx, qtx, userID, spot.ID, "random_spots", evaluation, planID); err != nil {
+			return result, err
+		}
+		switch evaluation {
+		case "excellent":
+			result.Excellent++
+		case "fine":
+			result.Fine++
+		case "poor":
+			result.Poor++
+		}
+	}
+	history, err := qtx.ListSpotStageEvaluations(ctx, db.ListSpotStageEvaluationsParams{
+		SpotID:       spot.ID,
+		UserID:       userID,
+		PracticeType: sql.NullString{String: "random_spots", Valid: true},
+	})
+	if err != nil {
+		return result, err
+	}
+	result.History = make([]string, 0, len(history))
+	for _, h := range history {
+		result.History = append(result.History, h.Evaluation.String)
+	}
+	return result, nil
+}
+
 func (s *Server) piecePracticeStartingPointPage(w http.ResponseWriter, r *http.Request) {
 	pieceID := chi.URLParam(r, "pieceID")
 	user := r.Context().Value(ck.UserKey).(db.User)
@@ -665,8 +919,10 @@ func (s *Server) piecePracticeStartingPointPage(w http.ResponseWriter, r *http.R
 		return
 	}
 
+	// practicing a section starts with the bounds set to the section's measures
+	section, _ := s.getPracticeSection(r, pieceID, user.ID)
 	token := csrf.Token(r)
-	s.HxRender(w, r, librarypages.PiecePracticeStartingPointPage(s, token, piece), piece.Title)
+	s.HxRender(w, r, librarypages.PiecePracticeStartingPointPage(s, token, piece, section), piece.Title)
 }
 
 type PiecePracticeInfo struct {
@@ -697,6 +953,19 @@ func (s *Server) piecePracticeStartingPointFinished(w http.ResponseWriter, r *ht
 	}()
 
 	qtx := queries.WithTx(tx)
+	// make sure the piece is the user's before saving anything against it
+	if _, err := qtx.GetPieceWithoutSpots(r.Context(), db.GetPieceWithoutSpotsParams{
+		ID:     pieceID,
+		UserID: user.ID,
+	}); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			http.Error(w, "Could not find matching piece", http.StatusNotFound)
+			return
+		}
+		s.DatabaseError(w, r, err, "Could not get piece")
+		return
+	}
+
 	activePracticePlanID, ok := s.GetActivePracticePlanID(r.Context())
 	if ok && activePracticePlanID != "" {
 		if err := qtx.CompletePracticePlanPiece(r.Context(), db.CompletePracticePlanPieceParams{
@@ -719,6 +988,37 @@ func (s *Server) piecePracticeStartingPointFinished(w http.ResponseWriter, r *ht
 
 	}
 
+	if info.DurationMinutes > 0 {
+		if err := qtx.CreatePracticeSession(r.Context(), db.CreatePracticeSessionParams{
+			ID:              cuid2.Generate(),
+			UserID:          user.ID,
+			PracticeType:    "starting_point",
+			DurationMinutes: info.DurationMinutes,
+			PieceID:         sql.NullString{String: pieceID, Valid: true},
+			PracticePlanID:  sql.NullString{String: activePracticePlanID, Valid: ok && activePracticePlanID != ""},
+		}); err != nil {
+			s.DatabaseError(w, r, err, "Could not save practice session")
+			return
+		}
+	}
+
+	// the session is saved so the rating and trouble bars can be added from the summary. it's
+	// written in the same transaction the piece was checked in and only for the user's own piece.
+	startingPointSessionID := ""
+	if measuresPracticed, err := measurerange.ParseList(info.MeasuresPracticed); err == nil {
+		startingPointSessionID = cuid2.Generate()
+		if err := qtx.CreateStartingPointSession(r.Context(), db.CreateStartingPointSessionParams{
+			ID:                startingPointSessionID,
+			UserID:            user.ID,
+			PieceID:           pieceID,
+			MeasuresPracticed: joinMeasureRanges(measuresPracticed),
+			PracticePlanID:    sql.NullString{String: activePracticePlanID, Valid: ok && activePracticePlanID != ""},
+		}); err != nil {
+			s.DatabaseError(w, r, err, "Could not save starting points")
+			return
+		}
+	}
+
 	if err := qtx.UpdatePiecePracticed(r.Context(), db.UpdatePiecePracticedParams{
 		UserID:  user.ID,
 		PieceID: pieceID,
@@ -732,10 +1032,9 @@ func (s *Server) piecePracticeStartingPointFinished(w http.ResponseWriter, r *ht
 		return
 	}
 
-	w.WriteHeader(http.StatusOK)
-	if _, err := w.Write([]byte("OK")); err != nil {
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(map[string]string{"status": "ok", "sessionId": startingPointSessionID}); err != nil {
 		log.Default().Println(err)
-		http.Error(w, "Could not write response", http.StatusInternalServerError)
 	}
 }
 
@@ -772,6 +1071,10 @@ func (s *Server) piecePracticeRepeatPage(w http.ResponseWriter, r *http.Request)
 	s.HxRender(w, r, librarypages.PiecePracticeRepeatPage(piece), piece[0].Title)
 }
 
+// PIECE_EXPORT_VERSION is the format of exported piece files. Files from before versioning have no
+// version and are read as version 1.
+const PIECE_EXPORT_VERSION = 2
+
 type ImportExportSpot struct {
 	Name           string `json:"name"`
 	Stage          string `json:"stage"`
@@ -782,9 +1085,14 @@ type ImportExportSpot struct {
 	TextPrompt     string `json:"textPrompt,omitempty"`
 	CurrentTempo   int64  `json:"currentTempo"`
 	Priority       int64  `json:"priority"`
+	StageStarted   int64  `json:"stageStarted,omitempty"`
+	SkipDays       int64  `json:"skipDays,omitempty"`
+	LastPracticed  int64  `json:"lastPracticed,omitempty"`
 }
 
 type ImportExportPiece struct {
+	Version         int                `json:"version,omitempty"`
+	IncludeProgress bool               `json:"includeProgress,omitempty"`
 	Title           string             `json:"title"`
 	Description     string             `json:"description,omitempty"`
 	Composer        string             `json:"composer,omitempty"`
@@ -795,6 +1103,62 @@ type ImportExportPiece struct {
 	Spots           []ImportExportSpot `json:"spots"`
 }
 
+// pieceMeasuresMessage is a message for the user when a piece can't have this many measures, or an
+// empty string if it can. Everything that saves a piece's measures checks them, since the piece page
+// has an entry for every measure.
+func pieceMeasuresMessage(measures int64) string {
+	if measures < 0 || measures > config.MAX_PIECE_MEASURES {
+		return "The number of measures must be between 0 and " + strconv.Itoa(config.MAX_PIECE_MEASURES)
+	}
+	return ""
+}
+
+// validateImportPiece checks an imported piece against what the database will accept and fills in
+// defaults for anything older files leave out. It returns a message for the user if the piece can't
+// be imported, or an empty string if it can.
+func validateImportPiece(p *ImportExportPiece) string {
+	if p.Version > PIECE_EXPORT_VERSION {
+		return "This file is from a newer version of the app and can't be imported"
+	}
+	if p.Version < 0 {
+		return "This file has an invalid version"
+	}
+	if strings.TrimSpace(p.Title) == "" {
+		return "The piece must have a title"
+	}
+	if p.Stage == "" || !p.IncludeProgress {
+		p.Stage = "active"
+	}
+	if !slices.Contains([]string{"active", "completed", "future"}, p.Stage) {
+		return "Invalid piece stage: " + p.Stage
+	}
+	if message := pieceMeasuresMessage(p.Measures); message != "" {
+		return message
+	}
+	for i := range p.Spots {
+		spot := &p.Spots[i]
+		if strings.TrimSpace(spot.Name) == "" {
+			return "Every spot must have a name"
+		}
+		if spot.Stage == "" {
+			spot.Stage = stages.Repeat
+		}
+		if !slices.Contains(stages.All, spot.Stage) {
+			return "Invalid stage for spot " + spot.Name + ": " + spot.Stage
+		}
+		if spot.Priority < -2 || spot.Priority > 2 {
+			return "The priority for spot " + spot.Name + " must be between -2 and 2"
+		}
+		if spot.SkipDays == 0 {
+			spot.SkipDays = 1
+		}
+		if spot.SkipDays < 0 {
+			return "The skip days for spot " + spot.Name + " must be positive"
+		}
+	}
+	return ""
+}
+
 func (s *Server) exportPiece(w http.ResponseWriter, r *http.Request) {
 	user := r.Context().Value(ck.UserKey).(db.User)
 	pieceID := chi.URLParam(r, "pieceID")
@@ -819,10 +1183,27 @@ func (s *Server) exportPiece(w http.ResponseWriter, r *http.Request) {
 			return
 		}
 	}
+	includeProgress := r.URL.Query().Get("progress") == "true"
+	progress := make(map[string]db.ListPieceSpotsRow)
+	if includeProgress {
+		spots, err := queries.ListPieceSpots(r.Context(), db.ListPieceSpotsParams{
+			PieceID: pieceID,
+			UserID:  user.ID,
+		})
+		if err != nil {
+			s.DatabaseError(w, r, err, "Could not get spot progress")
+			return
+		}
+		for _, spot := range spots {
+			progress[spot.ID] = spot
+		}
+	}
 	exportPiece := ImportExportPiece{
-		Title: piece[0].Title,
-		Stage: piece[0].Stage,
-		Spots: make([]ImportExportSpot, 0, len(piece)),
+		Version:         PIECE_EXPORT_VERSION,
+		IncludeProgress: includeProgress,
+		Title:           piece[0].Title,
+		Stage:           piece[0].Stage,
+		Spots:           make([]ImportExportSpot, 0, len(piece)),
 	}
 	if piece[0].Description.Valid {
 		exportPiece.Description = piece[0].Description.String
@@ -892,6 +1273,17 @@ func (s *Server) exportPiece(w http.ResponseWriter, r *http.Request) {
 		if row.SpotCurrentTempo.Valid {
 			exportSpot.CurrentTempo = row.SpotCurrentTempo.Int64
 		}
+		if spot, ok := progress[row.SpotID.String]; ok {
+			exportSpot.Stage = spot.Stage
+			exportSpot.Priority = spot.Priority
+			exportSpot.SkipDays = spot.SkipDays
+			if spot.StageStarted.Valid {
+				exportSpot.StageStarted = spot.StageStarted.Int64
+			}
+			if spot.LastPracticed.Valid {
+				exportSpot.LastPracticed = spot.LastPracticed.Int64
+			}
+		}
 		exportPiece.Spots = append(exportPiece.Spots, exportSpot)
 	}
 
@@ -921,47 +1313,84 @@ func (s *Server) importPiece(w http.ResponseWriter, r *http.Request) {
 	user := r.Context().Value(ck.UserKey).(db.User)
 
 	url := r.URL.Query().Get("url")
-	if url != "" {
-		resp, err := http.Get(url)
-		if err != nil {
-			log.Default().Println(err)
-			http.Error(w, err.Error(), http.StatusBadRequest)
-			return
-		}
-		defer resp.Body.Close()
-		var p ImportExportPiece
-		if err := json.NewDecoder(resp.Body).Decode(&p); err != nil {
-			log.Default().Println(err)
-			http.Error(w, err.Error(), http.StatusBadRequest)
-			return
-		}
-		pieceID, err := s.createPieceWithSpots(r.Context(), p, user.ID)
-		if err != nil {
-			log.Default().Println(err)
-			http.Error(w, err.Error(), http.StatusInternalServerError)
-			return
-		}
-		// if err := htmx.Trigger(r, "ShowAlert", ShowAlertEvent{
-		// 	Message:  "Your piece has been imported and you can start practicing!",
-		// 	Title:    "Imported Piece",
-		// 	Variant:  "success",
-		// 	Duration: 3000,
-		// }); err != nil {
-		// 	log.Default().Println(err)
-		// }
-		//
-		// htmx.PushURL(r, "/library/pieces/"+pieceID)
-		// TODO: show an alert
-		http.Redirect(w, r, "/library/pieces/"+pieceID, http.StatusSeeOther)
+	if url == "" {
+		s.InvalidInputError(w, r, "No link to import from")
+		return
+	}
+
+	// exported pieces are downloaded as octet-stream, and some file hosts serve json as plain text
+	fetcher := newSafeFetcher(config.MAX_UPLOAD_SIZE, "application/json", "application/octet-stream", "text/plain")
+	body, err := fetcher.Get(r.Context(), url)
+	if err != nil {
+		log.Default().Println(err)
+		s.importFailedError(w, r, fetchErrorMessage(err))
+		return
+	}
+	var p ImportExportPiece
+	if err := json.Unmarshal(body, &p); err != nil {
+		log.Default().Println(err)
+		s.importFailedError(w, r, "The linked file is not a valid piece file")
 		return
 	}
+	if message := validateImportPiece(&p); message != "" {
+		s.InvalidInputError(w, r, message)
+		return
+	}
+	pieceID, err := s.createPieceWithSpots(r.Context(), p, user.ID)
+	if err != nil {
+		s.DatabaseError(w, r, err, "Could not import piece")
+		return
+	}
+	if err := htmx.Trigger(r, "ShowAlert", ShowAlertEvent{
+		Message:  "Added Piece: " + p.Title,
+		Title:    "Piece Imported!",
+		Variant:  "success",
+		Duration: 3000,
+	}); err != nil {
+		log.Default().Println(err)
+	}
+	htmx.Redirect(r, "/library/pieces/"+pieceID)
+	http.Redirect(w, r, "/library/pieces/"+pieceID, http.StatusSeeOther)
+}
 
-	w.WriteHeader(http.StatusBadRequest)
+func (s *Server) importFailedError(w http.ResponseWriter, r *http.Request, message string) {
+	if err := htmx.Trigger(r, "ShowAlert", ShowAlertEvent{
+		Message:  message,
+		Title:    "Import Failed",
+		Variant:  "error",
+		Duration: 3000,
+	}); err != nil {
+		log.Default().Println(err)
+	}
+	http.Error(w, message, http.StatusBadRequest)
 }
 
+// createPieceWithSpots saves an imported piece, which should already have been checked with
+// validateImportPiece. Spots keep their progress only if the file includes it.
 func (s *Server) createPieceWithSpots(ctx context.Context, p ImportExportPiece, userID string) (string, error) {
-	queries := db.New(s.DB)
+	tx, err := s.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return "", err
+	}
+	defer func() {
+		if err := tx.Rollback(); err != nil && !errors.Is(err, sql.ErrTxDone) {
+			log.Default().Println(err)
+		}
+	}()
+	qtx := db.New(s.DB).WithTx(tx)
+
+	pieceID, err := createImportedPiece(ctx, qtx, p, userID)
+	if err != nil {
+		return "", err
+	}
+	if err := tx.Commit(); err != nil {
+		return "", err
+	}
+	return pieceID, nil
+}
 
+// createImportedPiece saves an imported piece and its spots as part of a larger transaction
+func createImportedPiece(ctx context.Context, qtx *db.Queries, p ImportExportPiece, userID string) (string, error) {
 	measures := sql.NullInt64{Int64: 0, Valid: false}
 	if p.Measures > 0 {
 		measures = sql.NullInt64{Int64: p.Measures, Valid: true}
@@ -986,7 +1415,7 @@ func (s *Server) createPieceWithSpots(ctx context.Context, p ImportExportPiece,
 
 	pieceID := cuid2.Generate()
 
-	piece, err := queries.CreatePiece(ctx, db.CreatePieceParams{
+	if err := qtx.ImportPiece(ctx, db.ImportPieceParams{
 		ID:              pieceID,
 		Title:           p.Title,
 		Description:     description,
@@ -995,14 +1424,16 @@ func (s *Server) createPieceWithSpots(ctx context.Context, p ImportExportPiece,
 		BeatsPerMeasure: beatsPerMeasure,
 		GoalTempo:       goalTempo,
 		UserID:          userID,
-	})
-	if err != nil {
+		Stage:           p.Stage,
+	}); err != nil {
 		return "", err
 	}
 
+	now := time.Now().Unix()
 	for _, spot := range p.Spots {
-		_, err := queries.CreateSpot(ctx, db.CreateSpotParams{
+		params := db.ImportSpotParams{
 			ID:          cuid2.Generate(),
+			PieceID:     pieceID,
 			Name:        spot.Name,
 			Stage:       spot.Stage,
 			NotesPrompt: spot.NotesPrompt,
@@ -1017,14 +1448,25 @@ func (s *Server) createPieceWithSpots(ctx context.Context, p ImportExportPiece,
 			},
 			AudioPromptUrl: spot.AudioPromptUrl,
 			ImagePromptUrl: spot.ImagePromptUrl,
-			PieceID:        pieceID,
-			UserID:         userID,
-		})
-		if err != nil {
+			StageStarted:   sql.NullInt64{Int64: now, Valid: true},
+			SkipDays:       1,
+		}
+		params.MeasuresStart, params.MeasuresEnd = spotMeasureRange(params.Measures)
+		if p.IncludeProgress {
+			params.Priority = spot.Priority
+			params.SkipDays = spot.SkipDays
+			if spot.StageStarted > 0 {
+				params.StageStarted = sql.NullInt64{Int64: spot.StageStarted, Valid: true}
+			}
+			if spot.LastPracticed > 0 {
+				params.LastPracticed = sql.NullInt64{Int64: spot.LastPracticed, Valid: true}
+			}
+		}
+		if err := qtx.ImportSpot(ctx, params); err != nil {
 			return "", err
 		}
 	}
-	return piece.ID, nil
+	return pieceID, nil
 }
 
 func (s *Server) uploadPieceFile(w http.ResponseWriter, r *http.Request) {
@@ -1050,12 +1492,33 @@ func (s *Server) importPieceFromFile(w http.ResponseWriter, r *http.Request) {
 		http.Error(w, "invalid file", http.StatusBadRequest)
 		return
 	}
-	if fileHeader.Filename[len(fileHeader.Filename)-4:] != "json" {
-		log.Default().Println(err)
-		http.Error(w, "The file must be a JSON file", http.StatusBadRequest)
+	defer file.Close()
+
+	ext := strings.ToLower(filepath.Ext(fileHeader.Filename))
+	if ext == ".mid" || ext == ".midi" {
+		data, err := io.ReadAll(file)
+		if err != nil {
+			log.Default().Println(err)
+			http.Error(w, "invalid file", http.StatusBadRequest)
+			return
+		}
+		score, err := scoreimport.ParseMIDI(data, config.MAX_PIECE_MEASURES)
+		if err != nil {
+			log.Default().Println(err)
+			if errors.Is(err, scoreimport.ErrTooLarge) {
+				s.importFailedError(w, r, "The score is too big to import")
+			} else {
+				s.importFailedError(w, r, "Could not read the MIDI file")
+			}
+			return
+		}
+		s.createPieceFromScore(w, r, user.ID, score, fileHeader.Filename)
+		return
+	}
+	if ext != ".json" {
+		http.Error(w, "The file must be a JSON or MIDI file", http.StatusBadRequest)
 		return
 	}
-	defer file.Close()
 
 	var p ImportExportPiece
 	if err := json.NewDecoder(file).Decode(&p); err != nil {
@@ -1063,6 +1526,10 @@ func (s *Server) importPieceFromFile(w http.ResponseWriter, r *http.Request) {
 		http.Error(w, "Could not decode file", http.StatusBadRequest)
 		return
 	}
+	if message := validateImportPiece(&p); message != "" {
+		s.InvalidInputError(w, r, message)
+		return
+	}
 
 	pieceID, err := s.createPieceWithSpots(r.Context(), p, user.ID)
 	if err != nil {
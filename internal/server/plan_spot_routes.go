@@ -6,9 +6,9 @@ import (
 	"log"
 	"net/http"
 	"practicebetter/internal/ck"
-	"practicebetter/internal/config"
 	"practicebetter/internal/db"
 	"practicebetter/internal/pages/planpages"
+	"practicebetter/internal/stages"
 	"time"
 
 	"github.com/go-chi/chi/v5"
@@ -16,30 +16,7 @@ import (
 	"github.com/mavolin/go-htmx"
 )
 
-// Hack enum *sigh*
-type PromoteDemote int
-
-const (
-	Promote PromoteDemote = 0
-	Demote  PromoteDemote = 1
-	Neither PromoteDemote = 2
-)
-
-func promoteDemoteInterleaveSpot(e string, started sql.NullInt64) PromoteDemote {
-	if e == "excellent" &&
-		started.Valid &&
-		time.Since(time.Unix(started.Int64, 0)) > config.INTERLEAVE_SPOT_MIN_DAYS*24*time.Hour {
-		return Promote
-	}
-	if e == "poor" || e == "fine" &&
-		started.Valid &&
-		time.Since(time.Unix(started.Int64, 0)) > config.INTERLEAVE_SPOT_MAX_DAYS*24*time.Hour {
-		return Demote
-	}
-	return Neither
-}
-
-func (s *Server) completeInterleaveSpots(w http.ResponseWriter, r *http.Request, planID string, userID string) {
+func (s *Server) completeInterleaveSpots(w http.ResponseWriter, r *http.Request, planID string, user db.User) {
 	tx, err := s.DB.BeginTx(r.Context(), nil)
 	if err != nil {
 		s.DatabaseError(w, r, err, "Could not start transaction")
@@ -55,20 +32,27 @@ func (s *Server) completeInterleaveSpots(w http.ResponseWriter, r *http.Request,
 
 	spots, err := qtx.GetPracticePlanEvaluatedInterleaveSpots(r.Context(), db.GetPracticePlanEvaluatedInterleaveSpotsParams{
 		PlanID: planID,
-		UserID: userID,
+		UserID: user.ID,
 	})
 	if err != nil {
 		s.DatabaseError(w, r, err, "Could not get interleave spots")
 		return
 	}
 
+	rules, err := getStageRules(r.Context(), qtx, user.ID)
+	if err != nil {
+		s.DatabaseError(w, r, err, "Could not get stage rules")
+		return
+	}
+	engine := stages.New(rules, time.Now().In(userLocation(user)))
+
 	for _, sp := range spots {
 		if !sp.Evaluation.Valid {
 			continue
 		}
 		if err := qtx.CompletePracticePlanSpot(r.Context(), db.CompletePracticePlanSpotParams{
 			PlanID: planID,
-			UserID: userID,
+			UserID: user.ID,
 			SpotID: sp.SpotID,
 		}); err != nil {
 			s.DatabaseError(w, r, err, "Could not complete spot")
@@ -77,40 +61,49 @@ func (s *Server) completeInterleaveSpots(w http.ResponseWriter, r *http.Request,
 		if !sp.SpotStageStarted.Valid {
 			err := qtx.FixSpotStageStarted(r.Context(), db.FixSpotStageStartedParams{
 				SpotID: sp.SpotID,
-				UserID: userID,
+				UserID: user.ID,
 			})
 			if err != nil {
 				s.DatabaseError(w, r, err, "Could not fix spot started time")
 				return
 			}
 		}
-		switch promoteDemoteInterleaveSpot(sp.Evaluation.String, sp.SpotStageStarted) {
-		case Promote:
-			err := qtx.PromoteSpotToInterleaveDays(r.Context(), db.PromoteSpotToInterleaveDaysParams{
-				SpotID: sp.SpotID,
-				UserID: userID,
-			})
-			if err != nil {
-				s.DatabaseError(w, r, err, "Could not promote spot")
-				return
-			}
-		case Demote:
-			err := qtx.DemoteSpotToRandom(r.Context(), db.DemoteSpotToRandomParams{
-				SpotID: sp.SpotID,
-				UserID: userID,
-			})
-			if err != nil {
-				s.DatabaseError(w, r, err, "Could not demote spot")
-				return
-			}
-		case Neither:
-			if err := qtx.UpdateSpotPracticed(r.Context(), db.UpdateSpotPracticedParams{
-				SpotID: sp.SpotID,
-				UserID: userID,
-			}); err != nil {
-				s.DatabaseError(w, r, err, "Could not update spot")
-				return
-			}
+		fromStage, err := qtx.GetSpotStage(r.Context(), db.GetSpotStageParams{
+			SpotID: sp.SpotID,
+			UserID: user.ID,
+		})
+		if err != nil {
+			s.DatabaseError(w, r, err, "Could not get spot")
+			return
+		}
+		history, err := qtx.ListSpotStageEvaluations(r.Context(), db.ListSpotStageEvaluationsParams{
+			SpotID:       sp.SpotID,
+			UserID:       user.ID,
+			PracticeType: sql.NullString{String: "interleave", Valid: true},
+		})
+		if err != nil {
+			s.DatabaseError(w, r, err, "Could not get spot history")
+			return
+		}
+		evaluations := make([]string, 0, len(history)+1)
+		for _, h := range history {
+			evaluations = append(evaluations, h.Evaluation.String)
+		}
+		if len(evaluations) == 0 || evaluations[len(evaluations)-1] != sp.Evaluation.String {
+			evaluations = append(evaluations, sp.Evaluation.String)
+		}
+		var stageStarted time.Time
+		if sp.SpotStageStarted.Valid {
+			stageStarted = time.Unix(sp.SpotStageStarted.Int64, 0)
+		}
+		toStage := engine.Interleave(fromStage, evaluations, stageStarted)
+		if err := applyStageChange(r.Context(), qtx, user.ID, sp.SpotID, fromStage, toStage); err != nil {
+			s.DatabaseError(w, r, err, "Could not update spot")
+			return
+		}
+		if err := recordSpotStageChange(r.Context(), qtx, user.ID, sp.SpotID, fromStage, planID); err != nil {
+			s.DatabaseError(w, r, err, "Could not save spot history")
+			return
 		}
 	}
 
@@ -284,7 +277,7 @@ func (s *Server) getNewSpotsForPracticePlan(w http.ResponseWriter, r *http.Reque
 		}
 
 		if row.StageStarted.Valid {
-			spot.DaysSinceStarted = int64(time.Since(time.Unix(row.StageStarted.Int64, 0)).Hours() / 24)
+			spot.DaysSinceStarted = stages.CalendarDays(time.Unix(row.StageStarted.Int64, 0), time.Now().In(userLocation(user)))
 		} else {
 			spot.DaysSinceStarted = 0
 			err := queries.FixSpotStageStarted(r.Context(), db.FixSpotStageStartedParams{
@@ -384,7 +377,7 @@ func (s *Server) getSpotsForPracticePlan(w http.ResponseWriter, r *http.Request)
 		}
 
 		if row.StageStarted.Valid {
-			spot.DaysSinceStarted = int64(time.Since(time.Unix(row.StageStarted.Int64, 0)).Hours() / 24)
+			spot.DaysSinceStarted = stages.CalendarDays(time.Unix(row.StageStarted.Int64, 0), time.Now().In(userLocation(user)))
 		} else {
 			spot.DaysSinceStarted = 0
 			err := queries.FixSpotStageStarted(r.Context(), db.FixSpotStageStartedParams{
@@ -506,7 +499,7 @@ func (s *Server) addSpotsToPracticePlan(w http.ResponseWriter, r *http.Request)
 		}
 
 		if row.StageStarted.Valid {
-			spot.DaysSinceStarted = int64(time.Since(time.Unix(row.StageStarted.Int64, 0)).Hours() / 24)
+			spot.DaysSinceStarted = stages.CalendarDays(time.Unix(row.StageStarted.Int64, 0), time.Now().In(userLocation(user)))
 		} else {
 			spot.DaysSinceStarted = 0
 			err := queries.FixSpotStageStarted(r.Context(), db.FixSpotStageStartedParams{
@@ -1,14 +1,12 @@
 package server
 
 import (
-	"cmp"
 	"context"
 	"database/sql"
 	"errors"
 	"fmt"
 	"log"
 	"math"
-	"math/rand"
 	"net/http"
 	"practicebetter/internal/ck"
 	"practicebetter/internal/components"
@@ -17,8 +15,11 @@ import (
 	"practicebetter/internal/pages/ewspages"
 	"practicebetter/internal/pages/planpages"
 	"practicebetter/internal/pages/readingpages"
-	"slices"
+	"practicebetter/internal/planner"
+	"practicebetter/internal/scheduler"
+	"practicebetter/internal/stages"
 	"strconv"
+	"strings"
 	"time"
 
 	"github.com/go-chi/chi/v5"
@@ -37,92 +38,12 @@ func (s *Server) createPracticePlanForm(w http.ResponseWriter, r *http.Request)
 		s.DatabaseError(w, r, err, "Failed to load active pieces")
 		return
 	}
-	s.HxRender(w, r, planpages.CreatePracticePlanPage(s, token, activePieces, planpages.PlanCreationErrors{}, user), "Create Practice Plan")
-}
-
-type PotentialInfrequentSpot struct {
-	ID        string
-	TimeSince time.Duration
-}
-
-func (s *Server) fixSpotSkipDays(ctx context.Context, spotID string, userID string) {
-	queries := db.New(s.DB)
-	if err := queries.UpdateSpotSkipDays(ctx, db.UpdateSpotSkipDaysParams{
-		SkipDays: 1,
-		UserID:   userID,
-		SpotID:   spotID,
-	}); err != nil {
-		log.Default().Println("Error fixing spot skip days:", err)
-	}
-}
-
-type PlanPieceInfo struct {
-	NewSpotIDs               []string
-	ExtraRepeatSpotIDs       []string
-	InterleaveSpotIDs        []string
-	PotentialInfrequentSpots []PotentialInfrequentSpot
-	RandomSpotCount          int
-	ExtraRepeatSpotCount     int
-	CompletedSpotCount       int
-}
-
-func (s *Server) generatePiecePlanInfo(ctx context.Context, rows []db.GetPieceForPlanRow, failedNewSpotIDs map[string]struct{}, userID string) PlanPieceInfo {
-
-	newSpotIDs := make([]string, 0, len(rows)/2)
-	extraRepeatSpotIDs := make([]string, 0, len(rows)/4)
-	interleaveSpotIDs := make([]string, 0, len(rows)/4)
-	potentialInfrequentSpots := make([]PotentialInfrequentSpot, 0, len(rows)/4)
-	randomSpotCount := 0
-	extraRepeatSpotCount := 0
-	completedSpotCount := 0
-	for _, row := range rows {
-		if !row.SpotStage.Valid || !row.SpotID.Valid {
-			continue
-		}
-		switch row.SpotStage.String {
-		case "repeat":
-			// we're going to combine the lists later, so make need to prevent duplicates
-			if _, ok := failedNewSpotIDs[row.SpotID.String]; !ok {
-				newSpotIDs = append(newSpotIDs, row.SpotID.String)
-			}
-		case "extra_repeat":
-			extraRepeatSpotIDs = append(extraRepeatSpotIDs, row.SpotID.String)
-			extraRepeatSpotCount += 1
-		case "interleave":
-			interleaveSpotIDs = append(interleaveSpotIDs, row.SpotID.String)
-		case "interleave_days":
-			if !row.SpotSkipDays.Valid {
-				go s.fixSpotSkipDays(ctx, row.SpotID.String, userID)
-			}
-
-			// we want to make sure that it has been more than the skip days value since the last time
-			// this spot was practiced. I've made the offset 12 hours as a reasonable way to avoid adding
-			// an extra day because someone practiced in the evening one day and in the morning the next
-			if !row.SpotLastPracticed.Valid ||
-				time.Since(time.Unix(row.SpotLastPracticed.Int64, 0)) > (time.Duration(row.SpotSkipDays.Int64)+1)*24*time.Hour+config.INFREQUENT_SPOT_OFFSET {
-				potentialInfrequentSpots = append(potentialInfrequentSpots, PotentialInfrequentSpot{
-					ID:        row.SpotID.String,
-					TimeSince: time.Since(time.Unix(row.SpotLastPracticed.Int64, 0)),
-				})
-			}
-		case "random":
-			randomSpotCount += 1
-		case "completed":
-			completedSpotCount += 1
-		default:
-			continue
-		}
-	}
-	return PlanPieceInfo{
-		NewSpotIDs:               newSpotIDs,
-		ExtraRepeatSpotIDs:       extraRepeatSpotIDs,
-		InterleaveSpotIDs:        interleaveSpotIDs,
-		PotentialInfrequentSpots: potentialInfrequentSpots,
-		RandomSpotCount:          randomSpotCount,
-		ExtraRepeatSpotCount:     extraRepeatSpotCount,
-		CompletedSpotCount:       completedSpotCount,
+	profiles, err := s.getIntensityProfiles(r.Context(), user)
+	if err != nil {
+		s.DatabaseError(w, r, err, "Failed to load intensity profiles")
+		return
 	}
-
+	s.HxRender(w, r, planpages.CreatePracticePlanPage(s, token, activePieces, planpages.PlanCreationErrors{}, profiles, defaultIntensityProfileID(user, profiles), user.ConfigDefaultTimeBudget), "Create Practice Plan")
 }
 
 func (s *Server) createPracticePlan(w http.ResponseWriter, r *http.Request) {
@@ -158,16 +79,55 @@ func (s *Server) createPracticePlan(w http.ResponseWriter, r *http.Request) {
 			s.DatabaseError(w, r, err, "Failed to load active pieces")
 			return
 		}
+		profiles, err := s.getIntensityProfiles(r.Context(), user)
+		if err != nil {
+			s.DatabaseError(w, r, err, "Failed to load intensity profiles")
+			return
+		}
 		token := csrf.Token(r)
 		s.HxRender(w, r, planpages.CreatePracticePlanPage(s, token, activePieces,
 			planpages.PlanCreationErrors{
 				Pieces: "You need to select at least one piece to practice.",
 			},
-			user,
+			profiles,
+			r.FormValue("intensity"),
+			user.ConfigDefaultTimeBudget,
 		), "Create Practice Plan")
 		return
 	}
 
+	// either fill the plan up to a time budget, or use the caps from one of the user's profiles
+	intensity := r.FormValue("intensity")
+	var settings planner.Settings
+	if intensity == config.TIME_BUDGET_INTENSITY {
+		budget, err := strconv.Atoi(r.FormValue("time_budget"))
+		if err != nil || budget < config.MIN_TIME_BUDGET || budget > config.MAX_TIME_BUDGET {
+			s.InvalidInputError(w, r, fmt.Sprintf("Your time budget must be between %d and %d minutes", config.MIN_TIME_BUDGET, config.MAX_TIME_BUDGET))
+			return
+		}
+		averages, err := db.New(s.DB).ListUserPracticeSessionAverages(r.Context(), db.ListUserPracticeSessionAveragesParams{
+			UserID: user.ID,
+			Date:   time.Now().Add(-config.ESTIMATE_HISTORY_WINDOW).Unix(),
+		})
+		if err != nil {
+			// the defaults are good enough to make a plan
+			log.Default().Println(err)
+		}
+		settings.TimeBudget = time.Duration(budget) * time.Minute
+		settings.Estimates = planner.DefaultEstimates().WithHistory(averages)
+	} else {
+		profile, err := db.New(s.DB).GetIntensityProfile(r.Context(), db.GetIntensityProfileParams{
+			ID:     intensity,
+			UserID: user.ID,
+		})
+		if err != nil {
+			s.InvalidInputError(w, r, "Invalid plan intensity")
+			return
+		}
+		settings = plannerSettingsFromProfile(profile)
+		intensity = profile.Name
+	}
+
 	tx, err := s.DB.Begin()
 	if err != nil {
 		log.Default().Printf("Database error: %v\n", err)
@@ -183,8 +143,9 @@ func (s *Server) createPracticePlan(w http.ResponseWriter, r *http.Request) {
 	qtx := queries.WithTx(tx)
 
 	// We're going to carry forward failed new spots, so we need to get the new spots from the last plan that are
-	// still in the repeat practice stage
-	failedNewSpotIDs := make(map[string]struct{}, 0)
+	// still in the repeat practice stage. The ones that keep failing get pointed out once the plan is made.
+	failedNewSpotIDs := make([]string, 0)
+	strugglingSpots := make([]string, 0)
 	failedNewSpots, err := qtx.GetPracticePlanFailedNewSpots(r.Context(), db.GetPracticePlanFailedNewSpotsParams{
 		UserID:   user.ID,
 		PieceIDs: pieceIDs,
@@ -193,14 +154,17 @@ func (s *Server) createPracticePlan(w http.ResponseWriter, r *http.Request) {
 		log.Default().Println(err)
 	} else {
 		for _, spot := range failedNewSpots {
-			failedNewSpotIDs[spot.SpotID] = struct{}{}
+			failedNewSpotIDs = append(failedNewSpotIDs, spot.SpotID)
+			if spot.RepeatFailures >= config.REPEAT_FAILURE_WARNING {
+				strugglingSpots = append(strugglingSpots, fmt.Sprintf("%s (failed %d times, %d resets)", spot.SpotName, spot.RepeatFailures, spot.RepeatResets))
+			}
 		}
 	}
 
 	newPlan, err := qtx.CreatePracticePlan(r.Context(), db.CreatePracticePlanParams{
 		ID:        cuid2.Generate(),
 		UserID:    user.ID,
-		Intensity: r.FormValue("intensity"),
+		Intensity: intensity,
 	})
 	if err != nil {
 		log.Default().Printf("Database error: %v\n", err)
@@ -208,34 +172,21 @@ func (s *Server) createPracticePlan(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	var maxNewSpots int64
-	var maxInfrequentSpots int
-	var maxInterleaveSpots int
-	var maxSightReading int
-	switch r.FormValue("intensity") {
-	case "light":
-		maxNewSpots = config.LIGHT_MAX_NEW_SPOTS
-		maxInfrequentSpots = config.LIGHT_MAX_INFREQUENT_SPOTS
-		maxInterleaveSpots = config.LIGHT_MAX_INTERLEAVE_SPOTS
-		maxSightReading = config.LIGHT_SIGHT_READING
-	case "medium":
-		maxNewSpots = config.MEDIUM_MAX_NEW_SPOTS
-		maxInfrequentSpots = config.MEDIUM_MAX_INFREQUENT_SPOTS
-		maxInterleaveSpots = config.MEDIUM_MAX_INTERLEAVE_SPOTS
-		maxSightReading = config.MEDIUM_SIGHT_READING
-	case "heavy":
-		maxNewSpots = config.HEAVY_MAX_NEW_SPOTS
-		maxInfrequentSpots = config.HEAVY_MAX_INFREQUENT_SPOTS
-		maxInterleaveSpots = config.HEAVY_MAX_INTERLEAVE_SPOTS
-		maxSightReading = config.HEAVY_SIGHT_READING
-	}
+	settings.PracticeNew = r.FormValue("practice_new") == "on"
+	settings.PracticeInterleave = r.FormValue("practice_interleave") == "on"
+	settings.PracticeRandomSpots = r.FormValue("practice_random_single") == "on"
+	settings.PracticeStartingPoint = r.FormValue("practice_starting_point") == "on"
 
+	p := planner.New(time.Now().UnixNano())
+
+	scaleCount := 0
 	if r.FormValue("scale") == "on" {
 		workingScales, err := qtx.ListWorkingScales(r.Context(), user.ID)
 		if err != nil {
 			log.Default().Println(err)
 		}
 		if len(workingScales) > 0 {
+			scaleCount = len(workingScales)
 			for i, scale := range workingScales {
 				_, err = qtx.CreatePracticePlanScaleWithIdx(r.Context(), db.CreatePracticePlanScaleWithIdxParams{
 					PracticePlanID: newPlan.ID,
@@ -247,27 +198,30 @@ func (s *Server) createPracticePlan(w http.ResponseWriter, r *http.Request) {
 				}
 			}
 		} else {
-			var selectedScaleID int64
+			var scaleIDs []int64
 			if r.FormValue("modal-scales") == "on" {
 				allScales, err := qtx.ListScales(r.Context())
 				if err != nil {
 					s.DatabaseError(w, r, err, "Failed to load scales")
 					return
 				}
-				rand.Shuffle(len(allScales), func(i, j int) {
-					allScales[i], allScales[j] = allScales[j], allScales[i]
-				})
-				selectedScaleID = allScales[0].ID
+				for _, scale := range allScales {
+					scaleIDs = append(scaleIDs, scale.ID)
+				}
 			} else {
 				basicScales, err := qtx.ListBasicScales(r.Context())
 				if err != nil {
 					s.DatabaseError(w, r, err, "Failed to load scales")
 					return
 				}
-				rand.Shuffle(len(basicScales), func(i, j int) {
-					basicScales[i], basicScales[j] = basicScales[j], basicScales[i]
-				})
-				selectedScaleID = basicScales[0].ID
+				for _, scale := range basicScales {
+					scaleIDs = append(scaleIDs, scale.ID)
+				}
+			}
+			selectedScaleID, ok := p.PickScale(scaleIDs)
+			if !ok {
+				s.DatabaseError(w, r, errors.New("no scales found"), "Failed to load scales")
+				return
 			}
 
 			var userScaleID string
@@ -299,39 +253,27 @@ func (s *Server) createPracticePlan(w http.ResponseWriter, r *http.Request) {
 				s.DatabaseError(w, r, err, "Failed to create practice plan scale")
 				return
 			}
+			scaleCount = 1
 		}
 	}
 
+	if settings.TimeBudget > 0 {
+		// scales are chosen before the rest of the plan, but they still take up some of the time
+		settings.TimeBudget = max(settings.TimeBudget-time.Duration(scaleCount)*settings.Estimates.Scale, time.Minute)
+	}
+
+	var readingIDs []string
 	if r.FormValue("reading") == "on" {
 		items, err := qtx.ListIncompleteUserReadingItems(r.Context(), user.ID)
 		if err != nil {
 			log.Default().Println(err)
 		}
-		rand.Shuffle(len(items), func(i, j int) {
-			items[i], items[j] = items[j], items[i]
-		})
-		i := 0
-		for i < int(math.Min(float64(len(items)), float64(maxSightReading))) {
-			_, err = qtx.CreatePracticePlanReadingWithIdx(r.Context(), db.CreatePracticePlanReadingWithIdxParams{
-				PracticePlanID: newPlan.ID,
-				ReadingID:      items[i].ID,
-				Idx:            int64(i),
-			})
-			if err != nil {
-				log.Default().Println(err)
-			}
-			i++
+		for _, item := range items {
+			readingIDs = append(readingIDs, item.ID)
 		}
-
 	}
 
-	maybeNewSpotLists := make([][]string, 0, len(pieceIDs))
-	potentialInfrequentSpots := make([]PotentialInfrequentSpot, 0, len(pieceIDs)*10)
-	extraRepeatSpotIDs := make([]string, 0, len(pieceIDs)*10)
-	interleaveSpotIDs := make([]string, 0, len(pieceIDs)*10)
-	randomSpotPieceIDs := make([]string, 0, len(pieceIDs))
-	startingPointPieceIDs := make([]string, 0, len(pieceIDs))
-
+	pieces := make([]planner.Piece, 0, len(pieceIDs))
 	for _, pieceID := range pieceIDs {
 		pieceRows, err := qtx.GetPieceForPlan(r.Context(), db.GetPieceForPlanParams{
 			PieceID: pieceID,
@@ -341,173 +283,85 @@ func (s *Server) createPracticePlan(w http.ResponseWriter, r *http.Request) {
 			s.DatabaseError(w, r, err, "Could not get piece")
 			return
 		}
+		pieces = append(pieces, planner.Piece{
+			ID:   pieceID,
+			Rows: pieceRows,
+		})
+	}
 
-		pieceInfo := s.generatePiecePlanInfo(r.Context(), pieceRows, failedNewSpotIDs, user.ID)
-
-		extraRepeatSpotIDs = append(extraRepeatSpotIDs, pieceInfo.ExtraRepeatSpotIDs...)
-		interleaveSpotIDs = append(interleaveSpotIDs, pieceInfo.InterleaveSpotIDs...)
-		potentialInfrequentSpots = append(potentialInfrequentSpots, pieceInfo.PotentialInfrequentSpots...)
-
-		// Only new spots if there aren't too many extra repeat/random spots.
-		if (pieceInfo.ExtraRepeatSpotCount + pieceInfo.RandomSpotCount) < config.MAX_ALLOWED_RANDOM_SPOTS {
-			maybeNewSpotLists = append(maybeNewSpotLists, pieceInfo.NewSpotIDs)
+	settings.Scheduler = scheduler.Get(user.ConfigScheduler)
+	reviews := make(map[string][]scheduler.Review)
+	if settings.PracticeInterleave {
+		evaluations, err := qtx.ListUserInfrequentEvaluations(r.Context(), user.ID)
+		if err != nil {
+			// the scheduler can still use the skip days
+			log.Default().Println(err)
 		}
-
-		if r.FormValue("practice_random_single") == "on" &&
-			pieceInfo.RandomSpotCount > 2 {
-			randomSpotPieceIDs = append(randomSpotPieceIDs, pieceID)
+		for _, evaluation := range evaluations {
+			reviews[evaluation.SpotID] = append(reviews[evaluation.SpotID], scheduler.Review{
+				Evaluation: evaluation.Evaluation.String,
+				Date:       time.Unix(evaluation.Date, 0),
+			})
 		}
+	}
 
-		if r.FormValue("practice_starting_point") == "on" &&
-			pieceInfo.CompletedSpotCount > 5 {
-			startingPointPieceIDs = append(startingPointPieceIDs, pieceID)
+	plan := p.Generate(planner.Input{
+		Settings:         settings,
+		Pieces:           pieces,
+		FailedNewSpotIDs: failedNewSpotIDs,
+		ReadingIDs:       readingIDs,
+		Reviews:          reviews,
+		Now:              time.Now().In(userLocation(user)),
+	})
+
+	for _, spotID := range plan.MissingSkipDaysSpotIDs {
+		if err := qtx.UpdateSpotSkipDays(r.Context(), db.UpdateSpotSkipDaysParams{
+			SkipDays: 1,
+			UserID:   user.ID,
+			SpotID:   spotID,
+		}); err != nil {
+			log.Default().Println("Error fixing spot skip days:", err)
 		}
 	}
 
-	// Add Spots
-	// extra repeat
-	rand.Shuffle(len(extraRepeatSpotIDs), func(i, j int) {
-		extraRepeatSpotIDs[i], extraRepeatSpotIDs[j] = extraRepeatSpotIDs[j], extraRepeatSpotIDs[i]
-	})
-	for i, spotID := range extraRepeatSpotIDs {
-		_, err := qtx.CreatePracticePlanSpotWithIdx(r.Context(), db.CreatePracticePlanSpotWithIdxParams{
+	for i, readingID := range plan.ReadingIDs {
+		_, err = qtx.CreatePracticePlanReadingWithIdx(r.Context(), db.CreatePracticePlanReadingWithIdxParams{
 			PracticePlanID: newPlan.ID,
-			SpotID:         spotID,
-			PracticeType:   "extra_repeat",
+			ReadingID:      readingID,
 			Idx:            int64(i),
 		})
 		if err != nil {
-			s.DatabaseError(w, r, err, "Could not add extra repeat spot")
-			return
+			log.Default().Println(err)
 		}
 	}
 
-	if r.FormValue("practice_interleave") == "on" {
-		// interleave
-		rand.Shuffle(len(interleaveSpotIDs), func(i, j int) {
-			interleaveSpotIDs[i], interleaveSpotIDs[j] = interleaveSpotIDs[j], interleaveSpotIDs[i]
-		})
-		for i, spotID := range interleaveSpotIDs {
-			if i >= maxInterleaveSpots {
-				break
-			}
-			_, err := qtx.CreatePracticePlanSpotWithIdx(r.Context(), db.CreatePracticePlanSpotWithIdxParams{
-				PracticePlanID: newPlan.ID,
-				SpotID:         spotID,
-				PracticeType:   "interleave",
-				Idx:            int64(i),
-			})
-			if err != nil {
-				s.DatabaseError(w, r, err, "Could not add interleave spot")
-				return
-			}
-		}
-
-		// prioritize infrequent spots with the spots that are the lest recently practiced first
-		slices.SortFunc(potentialInfrequentSpots, func(a, b PotentialInfrequentSpot) int {
-			return cmp.Compare(b.TimeSince, a.TimeSince)
-		})
-
-		// infrequent spots
-		for i, spot := range potentialInfrequentSpots {
-			if i >= maxInfrequentSpots {
-				break
-			}
-			_, err := qtx.CreatePracticePlanSpotWithIdx(r.Context(), db.CreatePracticePlanSpotWithIdxParams{
-				PracticePlanID: newPlan.ID,
-				SpotID:         spot.ID,
-				PracticeType:   "interleave_days",
-				Idx:            int64(i),
-			})
-			if err != nil {
-				s.DatabaseError(w, r, err, "Could not add infrequent spot")
-				return
-			}
-		}
+	planSpotLists := []struct {
+		practiceType string
+		spotIDs      []string
+		errMessage   string
+	}{
+		{"extra_repeat", plan.ExtraRepeatSpotIDs, "Could not add extra repeat spot"},
+		{"interleave", plan.InterleaveSpotIDs, "Could not add interleave spot"},
+		{"interleave_days", plan.InfrequentSpotIDs, "Could not add infrequent spot"},
+		{"new", plan.NewSpotIDs, "Could not add spot"},
 	}
-
-	if r.FormValue("practice_new") == "on" {
-		var newSpotIdx int64 = 0
-		// Put in all the failed spots from the previous day
-		for spotID := range failedNewSpotIDs {
+	for _, list := range planSpotLists {
+		for i, spotID := range list.spotIDs {
 			_, err := qtx.CreatePracticePlanSpotWithIdx(r.Context(), db.CreatePracticePlanSpotWithIdxParams{
 				PracticePlanID: newPlan.ID,
 				SpotID:         spotID,
-				PracticeType:   "new",
-				Idx:            newSpotIdx,
+				PracticeType:   list.practiceType,
+				Idx:            int64(i),
 			})
 			if err != nil {
-				s.DatabaseError(w, r, err, "Could not add spot")
+				s.DatabaseError(w, r, err, list.errMessage)
 				return
 			}
-			newSpotIdx++
-		}
-
-		// then if there's room, put in one spot from each piece, and save the others to be randomized
-		additionalNewSpots := make([]string, 0, len(maybeNewSpotLists)*10)
-		for _, pieceSpotList := range maybeNewSpotLists {
-			if newSpotIdx >= maxNewSpots {
-				break
-			}
-			// shuffle the spots will be random
-			rand.Shuffle(len(pieceSpotList), func(i, j int) {
-				pieceSpotList[i], pieceSpotList[j] = pieceSpotList[j], pieceSpotList[i]
-			})
-			for i, spotID := range pieceSpotList {
-				if newSpotIdx >= maxNewSpots {
-					break
-				}
-				if i == 0 {
-					_, err := qtx.CreatePracticePlanSpotWithIdx(r.Context(), db.CreatePracticePlanSpotWithIdxParams{
-						PracticePlanID: newPlan.ID,
-						SpotID:         spotID,
-						PracticeType:   "new",
-						Idx:            newSpotIdx,
-					})
-					if err != nil {
-						s.DatabaseError(w, r, err, "Could not add spot")
-						return
-					}
-					newSpotIdx++
-				} else {
-					additionalNewSpots = append(additionalNewSpots, spotID)
-				}
-			}
-
-		}
-
-		// new spots
-		// if there's room, shuffle the remaining spots and add them until it's full
-		if newSpotIdx < maxNewSpots {
-			rand.Shuffle(len(additionalNewSpots), func(i, j int) {
-				additionalNewSpots[i], additionalNewSpots[j] = additionalNewSpots[j], additionalNewSpots[i]
-			})
-			for _, spotID := range additionalNewSpots {
-				if newSpotIdx >= maxNewSpots {
-					break
-				}
-				_, err := qtx.CreatePracticePlanSpotWithIdx(r.Context(), db.CreatePracticePlanSpotWithIdxParams{
-					PracticePlanID: newPlan.ID,
-					SpotID:         spotID,
-					PracticeType:   "new",
-					Idx:            newSpotIdx,
-				})
-				if err != nil {
-					s.DatabaseError(w, r, err, "Could not add spot")
-					return
-				}
-				newSpotIdx++
-			}
-
 		}
 	}
 
-	// add pieces
 	// random spots pieces
-	rand.Shuffle(len(randomSpotPieceIDs), func(i, j int) {
-		randomSpotPieceIDs[i], randomSpotPieceIDs[j] = randomSpotPieceIDs[j], randomSpotPieceIDs[i]
-	})
-	for i, pieceID := range randomSpotPieceIDs {
+	for i, pieceID := range plan.RandomSpotPieceIDs {
 		_, err := qtx.CreatePracticePlanPieceWithIdx(r.Context(), db.CreatePracticePlanPieceWithIdxParams{
 			PracticePlanID: newPlan.ID,
 			PieceID:        pieceID,
@@ -520,10 +374,7 @@ func (s *Server) createPracticePlan(w http.ResponseWriter, r *http.Request) {
 		}
 	}
 	// random starting point pieces
-	rand.Shuffle(len(startingPointPieceIDs), func(i, j int) {
-		startingPointPieceIDs[i], startingPointPieceIDs[j] = startingPointPieceIDs[j], startingPointPieceIDs[i]
-	})
-	for i, pieceID := range startingPointPieceIDs {
+	for i, pieceID := range plan.StartingPointPieceIDs {
 		_, err := qtx.CreatePracticePlanPieceWithIdx(r.Context(), db.CreatePracticePlanPieceWithIdxParams{
 			PracticePlanID: newPlan.ID,
 			PieceID:        pieceID,
@@ -570,6 +421,25 @@ func (s *Server) createPracticePlan(w http.ResponseWriter, r *http.Request) {
 	s.ClearLastBreak(r.Context())
 	s.SetLastBreak(r.Context(), newPlan.ID)
 
+	alert := ShowAlertEvent{
+		Title:    "Plan Created",
+		Variant:  "success",
+		Duration: 5000,
+	}
+	if settings.TimeBudget > 0 {
+		alert.Message = fmt.Sprintf("This plan should take about %d minutes.", int(plan.EstimatedDuration.Minutes()+settings.Estimates.Scale.Minutes()*float64(scaleCount)))
+	}
+	if len(strugglingSpots) > 0 {
+		alert.Message = strings.TrimSpace(alert.Message + " These new spots keep failing repeat practice, try splitting them into smaller spots: " + strings.Join(strugglingSpots, ", "))
+		alert.Variant = "warning"
+		alert.Duration = 10000
+	}
+	if alert.Message != "" {
+		if err := htmx.TriggerAfterSettle(r, "ShowAlert", alert); err != nil {
+			log.Default().Println(err)
+		}
+	}
+
 	if r.FormValue("customize") == "on" {
 		htmx.PushURL(r, "/library/plans/"+newPlan.ID+"/edit")
 		ctx := context.WithValue(r.Context(), ck.ActivePlanKey, newPlan.ID)
@@ -657,6 +527,8 @@ func (s *Server) renderPracticePlanPage(w http.ResponseWriter, r *http.Request,
 		planData.InterleaveSpotsCompleted = true
 		planData.Intensity = planPieces[0].Intensity
 	}
+	user := r.Context().Value(ck.UserKey).(db.User)
+	planData.Resumable = canResumePlan(planData.Date, time.Now(), userLocation(user))
 
 	if planData.IsActive {
 		needsBreak, err := s.needsBreak(r.Context(), planID)
@@ -749,7 +621,7 @@ func (s *Server) renderPracticePlanPage(w http.ResponseWriter, r *http.Request,
 			}
 
 			if row.SpotStageStarted.Valid {
-				spot.DaysSinceStarted = int64(time.Since(time.Unix(row.SpotStageStarted.Int64, 0)).Hours() / 24)
+				spot.DaysSinceStarted = stages.CalendarDays(time.Unix(row.SpotStageStarted.Int64, 0), time.Now().In(userLocation(user)))
 			} else {
 				spot.DaysSinceStarted = 0
 				err := queries.FixSpotStageStarted(r.Context(), db.FixSpotStageStartedParams{
@@ -770,6 +642,7 @@ func (s *Server) renderPracticePlanPage(w http.ResponseWriter, r *http.Request,
 			spot.PieceTitle = row.SpotPieceTitle
 			spot.PieceID = row.SpotPieceID.String
 			spot.Completed = row.SpotCompleted
+			spot.Priority = row.SpotPriority.Int64
 
 			if row.SpotPracticeType == "interleave" {
 				planData.InterleaveSpots = append(planData.InterleaveSpots, spot)
@@ -862,6 +735,8 @@ func (s *Server) renderEditPracticePlanPage(w http.ResponseWriter, r *http.Reque
 		planData.InterleaveSpotsCompleted = true
 		planData.Intensity = planPieces[0].Intensity
 	}
+	user := r.Context().Value(ck.UserKey).(db.User)
+	planData.Resumable = canResumePlan(planData.Date, time.Now(), userLocation(user))
 
 	for _, row := range planPieces {
 		if row.PieceID.Valid {
@@ -906,7 +781,7 @@ func (s *Server) renderEditPracticePlanPage(w http.ResponseWriter, r *http.Reque
 			}
 
 			if row.SpotStageStarted.Valid {
-				spot.DaysSinceStarted = int64(time.Since(time.Unix(row.SpotStageStarted.Int64, 0)).Hours() / 24)
+				spot.DaysSinceStarted = stages.CalendarDays(time.Unix(row.SpotStageStarted.Int64, 0), time.Now().In(userLocation(user)))
 			} else {
 				spot.DaysSinceStarted = 0
 				err := queries.FixSpotStageStarted(r.Context(), db.FixSpotStageStartedParams{
@@ -927,6 +802,7 @@ func (s *Server) renderEditPracticePlanPage(w http.ResponseWriter, r *http.Reque
 			spot.PieceTitle = row.SpotPieceTitle
 			spot.PieceID = row.SpotPieceID.String
 			spot.Completed = row.SpotCompleted
+			spot.Priority = row.SpotPriority.Int64
 
 			if row.SpotPracticeType == "interleave" {
 				planData.InterleaveSpots = append(planData.InterleaveSpots, spot)
@@ -1274,7 +1150,7 @@ func (s *Server) resumePracticePlan(w http.ResponseWriter, r *http.Request) {
 		return
 
 	}
-	if time.Since(time.Unix(plan.Date, 0)) > config.RESUME_PLAN_TIME_LIMIT {
+	if !canResumePlan(plan.Date, time.Now(), userLocation(user)) {
 		if err := htmx.Trigger(r, "ShowAlert", ShowAlertEvent{
 			Message:  "You cannot resume a practice plan this old. Please create a new one instead.",
 			Title:    "Too Old",
@@ -1359,7 +1235,7 @@ func (s *Server) stopPracticePlan(w http.ResponseWriter, r *http.Request) {
 		s.DatabaseError(w, r, err, "Could not stop practice plan")
 		return
 	}
-	s.completeInterleaveSpots(w, r, planID, user.ID)
+	s.completeInterleaveSpots(w, r, planID, user)
 
 	err = queries.CompletePracticePlan(r.Context(), db.CompletePracticePlanParams{
 		ID:     plan.ID,
@@ -112,6 +112,13 @@ func (s *Server) pieceRouter(r chi.Router) {
 	r.Get("/import", s.importPiece)
 	r.Get("/import-file", s.uploadPieceFile)
 	r.Post("/import-file", s.importPieceFromFile)
+	r.Get("/import-musicxml", s.importMusicXMLForm)
+	r.Post("/import-musicxml", s.importMusicXML)
+	r.Get("/import-csv", s.csvImportForm(pieceCSVImport))
+	r.Post("/import-csv", s.csvImportUpload(pieceCSVImport))
+	r.Post("/import-csv/preview", s.csvImportPreview(pieceCSVImport))
+	r.Post("/import-csv/confirm", s.csvImportConfirm(pieceCSVImport))
+	r.Get("/export.csv", s.exportPiecesCSV)
 	r.Get("/{pieceID}", s.singlePiece)
 	r.Get("/{pieceID}/edit", s.editPiece)
 	r.Put("/{pieceID}", s.updatePiece)
@@ -119,6 +126,7 @@ func (s *Server) pieceRouter(r chi.Router) {
 	r.Get("/{pieceID}/export.json", s.exportPiece)
 
 	r.Route("/{pieceID}/spots", s.spotsRouter)
+	r.Route("/{pieceID}/sections", s.sectionsRouter)
 
 	r.Get("/{pieceID}/practice/random-single", s.piecePracticeRandomSpotsPage)
 	r.Post("/{pieceID}/practice/random-single", s.finishPracticePieceSpots)
@@ -128,6 +136,8 @@ func (s *Server) pieceRouter(r chi.Router) {
 
 	r.Get("/{pieceID}/practice/starting-point", s.piecePracticeStartingPointPage)
 	r.Post("/{pieceID}/practice/starting-point", s.piecePracticeStartingPointFinished)
+	r.Post("/{pieceID}/practice/starting-point/{sessionID}", s.saveStartingPointNotes)
+	r.Post("/{pieceID}/starting-points/{sessionID}/spots", s.startingPointTroubleSpots)
 
 	r.Get("/{pieceID}/practice/repeat", s.piecePracticeRepeatPage)
 }
@@ -138,10 +148,14 @@ func (s *Server) spotsRouter(r chi.Router) {
 	r.Get("/add-single", s.addSingleSpotPage)
 	r.Get("/add", s.addSpotsFromPDFPage)
 	r.Post("/pdf", s.addSpotsFromPDF)
+	r.Get("/generate", s.generateSpotsPage)
+	r.Post("/generate", s.generateSpots)
+	r.Post("/generate/preview", s.previewGeneratedSpots)
 
 	r.Route("/{spotID}", func(r chi.Router) {
 		r.Get("/", s.singleSpot)
 		r.Get("/edit", s.editSpot)
+		r.Get("/history", s.spotHistory)
 		r.Put("/", s.updateSpot)
 		r.Patch("/", s.updatePartialSpot)
 
@@ -152,10 +166,21 @@ func (s *Server) spotsRouter(r chi.Router) {
 		r.Delete("/", s.deleteSpot)
 		r.Get("/practice/repeat", s.repeatPracticeSpot)
 		r.Post("/practice/repeat", s.repeatPracticeSpotFinished)
+		r.Get("/practice/tempo-ladder", s.tempoLadder)
+		r.Post("/practice/tempo-ladder", s.tempoLadderResult)
 		r.Get("/practice/display", s.getPracticeSpotDisplay)
 	})
 }
 
+func (s *Server) sectionsRouter(r chi.Router) {
+	r.Get("/", s.pieceSections)
+	r.Post("/", s.createSection)
+	r.Get("/create", s.createSectionForm)
+	r.Get("/{sectionID}/edit", s.editSection)
+	r.Put("/{sectionID}", s.updateSection)
+	r.Delete("/{sectionID}", s.deleteSection)
+}
+
 func (s *Server) planRouter(r chi.Router) {
 	r.Get("/", s.planList)
 	r.Post("/", s.createPracticePlan)
@@ -208,6 +233,11 @@ func (s *Server) readingRouter(r chi.Router) {
 	r.Get("/", s.sightReadingItems)
 	r.Post("/", s.createSightReading)
 	r.Post("/bulk", s.bulkCreateSightReading)
+	r.Get("/import-csv", s.csvImportForm(readingCSVImport))
+	r.Post("/import-csv", s.csvImportUpload(readingCSVImport))
+	r.Post("/import-csv/preview", s.csvImportPreview(readingCSVImport))
+	r.Post("/import-csv/confirm", s.csvImportConfirm(readingCSVImport))
+	r.Get("/export.csv", s.exportReadingCSV)
 	r.Get("/create", s.createSightReadingForm)
 	r.Get("/{readingID}", s.singleSightReadingItem)
 	// r.Put("/{itemID}", s.updateScale)
@@ -218,6 +248,8 @@ func (s *Server) readingRouter(r chi.Router) {
 
 func (s *Server) libraryRouter(r chi.Router) {
 	r.Get("/", s.libraryDashboard)
+	r.Get("/search", s.search)
+	r.Get("/stats", s.practiceStats)
 
 	r.Route("/pieces", s.pieceRouter)
 	r.Route("/scales", s.scalesRouter)
@@ -261,4 +293,10 @@ func (s *Server) authRouter(r chi.Router) {
 	r.With(s.LoginRequired).Post("/passkey/register", s.registerPasskey)
 	r.With(s.LoginRequired).Post("/passkey/delete", s.deletePasskeys)
 	r.With(s.LoginRequired).Post("/me/settings", s.updateSettings)
+	r.With(s.LoginRequired).Post("/me/stage-rules", s.updateStageRules)
+	r.With(s.LoginRequired).Get("/me/export", s.exportAccount)
+	r.With(s.LoginRequired).Post("/me/import", s.importAccount)
+	r.With(s.LoginRequired).Post("/me/intensities", s.createIntensityProfile)
+	r.With(s.LoginRequired).Put("/me/intensities/{profileID}", s.updateIntensityProfile)
+	r.With(s.LoginRequired).Delete("/me/intensities/{profileID}", s.deleteIntensityProfile)
 }
@@ -0,0 +1,180 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net"
+	"net/http"
+	"net/netip"
+	"net/url"
+	"practicebetter/internal/config"
+	"slices"
+	"syscall"
+	"time"
+)
+
+var (
+	errFetchBlockedAddress = errors.New("address is not allowed")
+	errFetchBadURL         = errors.New("only http and https links can be imported")
+	errFetchTooLarge       = errors.New("response is too large")
+	errFetchContentType    = errors.New("response has the wrong content type")
+	errFetchStatus         = errors.New("response was not successful")
+	errFetchTooManyHops    = errors.New("too many redirects")
+)
+
+// ranges that the standard library doesn't already consider private but still shouldn't be reachable
+var fetchBlockedPrefixes = []netip.Prefix{
+	netip.MustParsePrefix("0.0.0.0/8"),
+	netip.MustParsePrefix("100.64.0.0/10"),
+	netip.MustParsePrefix("192.0.0.0/24"),
+	netip.MustParsePrefix("198.18.0.0/15"),
+	netip.MustParsePrefix("240.0.0.0/4"),
+	netip.MustParsePrefix("64:ff9b::/96"),
+}
+
+// isPublicAddr reports whether an address is on the public internet, so loopback, private and
+// link local addresses (including cloud metadata services) are rejected
+func isPublicAddr(addr netip.Addr) bool {
+	addr = addr.Unmap()
+	if !addr.IsValid() ||
+		addr.IsLoopback() ||
+		addr.IsPrivate() ||
+		addr.IsLinkLocalUnicast() ||
+		addr.IsLinkLocalMulticast() ||
+		addr.IsInterfaceLocalMulticast() ||
+		addr.IsMulticast() ||
+		addr.IsUnspecified() {
+		return false
+	}
+	for _, prefix := range fetchBlockedPrefixes {
+		if prefix.Contains(addr) {
+			return false
+		}
+	}
+	return true
+}
+
+// safeFetcher downloads user supplied links. Addresses are checked when the connection is made,
+// after dns resolution, so redirects and dns tricks can't reach anything internal.
+type safeFetcher struct {
+	timeout      time.Duration
+	maxBytes     int64
+	contentTypes []string
+	allowAddr    func(netip.Addr) bool
+}
+
+func newSafeFetcher(maxBytes int64, contentTypes ...string) *safeFetcher {
+	return &safeFetcher{
+		timeout:      config.IMPORT_FETCH_TIMEOUT,
+		maxBytes:     maxBytes,
+		contentTypes: contentTypes,
+		allowAddr:    isPublicAddr,
+	}
+}
+
+func (f *safeFetcher) client() *http.Client {
+	dialer := &net.Dialer{
+		Timeout: f.timeout,
+		Control: func(network, address string, _ syscall.RawConn) error {
+			host, _, err := net.SplitHostPort(address)
+			if err != nil {
+				return err
+			}
+			addr, err := netip.ParseAddr(host)
+			if err != nil {
+				return err
+			}
+			if !f.allowAddr(addr) {
+				return fmt.Errorf("%w: %s", errFetchBlockedAddress, addr)
+			}
+			return nil
+		},
+	}
+	return &http.Client{
+		Timeout: f.timeout,
+		Transport: &http.Transport{
+			// a proxy would make the connection checks look at the proxy instead of the real host
+			Proxy:               nil,
+			DialContext:         dialer.DialContext,
+			TLSHandshakeTimeout: f.timeout,
+			DisableKeepAlives:   true,
+		},
+		CheckRedirect: func(req *http.Request, via []*http.Request) error {
+			if len(via) >= config.IMPORT_FETCH_MAX_REDIRECTS {
+				return errFetchTooManyHops
+			}
+			if req.URL.Scheme != "http" && req.URL.Scheme != "https" {
+				return errFetchBadURL
+			}
+			return nil
+		},
+	}
+}
+
+// Get downloads the link and returns the body, as long as it fits the fetcher's limits
+func (f *safeFetcher) Get(ctx context.Context, rawURL string) ([]byte, error) {
+	u, err := url.Parse(rawURL)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return nil, errFetchBadURL
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, f.timeout)
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u.String(), nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := f.client().Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("%w: %s", errFetchStatus, resp.Status)
+	}
+	if len(f.contentTypes) > 0 {
+		mediaType, _, err := mime.ParseMediaType(resp.Header.Get("Content-Type"))
+		if err != nil || !slices.Contains(f.contentTypes, mediaType) {
+			return nil, fmt.Errorf("%w: %q", errFetchContentType, resp.Header.Get("Content-Type"))
+		}
+	}
+	if resp.ContentLength > f.maxBytes {
+		return nil, errFetchTooLarge
+	}
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, f.maxBytes+1))
+	if err != nil {
+		return nil, err
+	}
+	if int64(len(body)) > f.maxBytes {
+		return nil, errFetchTooLarge
+	}
+	return body, nil
+}
+
+// fetchErrorMessage explains a failed download to the user
+func fetchErrorMessage(err error) string {
+	var netErr net.Error
+	switch {
+	case errors.Is(err, errFetchBadURL):
+		return "Only http and https links can be imported"
+	case errors.Is(err, errFetchBlockedAddress):
+		return "That link points to an address that can't be imported from"
+	case errors.Is(err, errFetchTooLarge):
+		return "The linked file is too big"
+	case errors.Is(err, errFetchContentType):
+		return "The link doesn't point to a piece file"
+	case errors.Is(err, errFetchStatus):
+		return "The link could not be downloaded"
+	case errors.Is(err, errFetchTooManyHops):
+		return "The link redirects too many times"
+	case errors.Is(err, context.DeadlineExceeded), errors.As(err, &netErr) && netErr.Timeout():
+		return "The link took too long to respond"
+	default:
+		return "Could not download the link"
+	}
+}
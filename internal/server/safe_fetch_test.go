@@ -0,0 +1,217 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/netip"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+)
+
+// localFetcher can reach the test server on 127.0.0.1 but nothing else that isn't public
+func localFetcher(maxBytes int64, contentTypes ...string) *safeFetcher {
+	fetcher := newSafeFetcher(maxBytes, contentTypes...)
+	fetcher.allowAddr = func(addr netip.Addr) bool {
+		return addr == netip.MustParseAddr("127.0.0.1") || isPublicAddr(addr)
+	}
+	return fetcher
+}
+
+func TestIsPublicAddr(t *testing.T) {
+	tests := []struct {
+		addr   string
+		public bool
+	}{
+		{"93.184.216.34", true},
+		{"2606:2800:220:1:248:1893:25c8:1946", true},
+		{"127.0.0.1", false},
+		{"127.0.0.2", false},
+		{"::1", false},
+		{"::ffff:127.0.0.1", false},
+		{"10.0.0.1", false},
+		{"172.16.5.4", false},
+		{"192.168.1.1", false},
+		{"fd00::1", false},
+		{"169.254.169.254", false},
+		{"fe80::1", false},
+		{"0.0.0.0", false},
+		{"::", false},
+		{"100.64.0.1", false},
+		{"198.18.0.1", false},
+		{"224.0.0.1", false},
+		{"255.255.255.255", false},
+	}
+	for _, tt := range tests {
+		if got := isPublicAddr(netip.MustParseAddr(tt.addr)); got != tt.public {
+			t.Errorf("isPublicAddr(%s) = %v, want %v", tt.addr, got, tt.public)
+		}
+	}
+}
+
+func TestSafeFetcherBlocksLocalAddresses(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		t.Error("the server should never be reached")
+	}))
+	defer srv.Close()
+
+	_, err := newSafeFetcher(1024).Get(context.Background(), srv.URL)
+	if !errors.Is(err, errFetchBlockedAddress) {
+		t.Errorf("fetching a loopback address: err = %v, want %v", err, errFetchBlockedAddress)
+	}
+	// localhost resolves to loopback, which is only checked after dns
+	_, err = newSafeFetcher(1024).Get(context.Background(), strings.Replace(srv.URL, "127.0.0.1", "localhost", 1))
+	if !errors.Is(err, errFetchBlockedAddress) {
+		t.Errorf("fetching localhost: err = %v, want %v", err, errFetchBlockedAddress)
+	}
+}
+
+func TestSafeFetcherBlocksRedirects(t *testing.T) {
+	targets := []string{
+		"http://127.0.0.2/",
+		"http://169.254.169.254/latest/meta-data/",
+		"http://10.0.0.1/",
+	}
+	for _, target := range targets {
+		srv := httptest.NewServer(http.RedirectHandler(target, http.StatusFound))
+		_, err := localFetcher(1024).Get(context.Background(), srv.URL)
+		srv.Close()
+		if !errors.Is(err, errFetchBlockedAddress) {
+			t.Errorf("redirect to %s: err = %v, want %v", target, err, errFetchBlockedAddress)
+		}
+	}
+
+	srv := httptest.NewServer(http.RedirectHandler("file:///etc/passwd", http.StatusFound))
+	defer srv.Close()
+	if _, err := localFetcher(1024).Get(context.Background(), srv.URL); !errors.Is(err, errFetchBadURL) {
+		t.Errorf("redirect to a file: err = %v, want %v", err, errFetchBadURL)
+	}
+}
+
+func TestSafeFetcherBadURL(t *testing.T) {
+	for _, rawURL := range []string{"file:///etc/passwd", "ftp://example.com/piece.json", "http://", "not a url"} {
+		if _, err := localFetcher(1024).Get(context.Background(), rawURL); !errors.Is(err, errFetchBadURL) {
+			t.Errorf("Get(%q): err = %v, want %v", rawURL, err, errFetchBadURL)
+		}
+	}
+}
+
+func TestSafeFetcherMaxBytes(t *testing.T) {
+	body := strings.Repeat("a", 100)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Has("chunked") {
+			// flushing before writing everything leaves out the content length
+			w.WriteHeader(http.StatusOK)
+			w.(http.Flusher).Flush()
+		}
+		w.Write([]byte(body))
+	}))
+	defer srv.Close()
+
+	tests := []struct {
+		name     string
+		query    string
+		maxBytes int64
+		tooLarge bool
+	}{
+		{"with length under the limit", "", 200, false},
+		{"with length at the limit", "", 100, false},
+		{"with length over the limit", "", 99, true},
+		{"chunked under the limit", "?chunked", 200, false},
+		{"chunked at the limit", "?chunked", 100, false},
+		{"chunked over the limit", "?chunked", 99, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := localFetcher(tt.maxBytes).Get(context.Background(), srv.URL+tt.query)
+			if tt.tooLarge {
+				if !errors.Is(err, errFetchTooLarge) {
+					t.Errorf("err = %v, want %v", err, errFetchTooLarge)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if string(got) != body {
+				t.Errorf("got %d bytes, want %d", len(got), len(body))
+			}
+		})
+	}
+}
+
+func TestSafeFetcherTimeout(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-time.After(5 * time.Second):
+		case <-r.Context().Done():
+		}
+	}))
+	defer srv.Close()
+
+	fetcher := localFetcher(1024)
+	fetcher.timeout = 50 * time.Millisecond
+	start := time.Now()
+	_, err := fetcher.Get(context.Background(), srv.URL)
+	if err == nil {
+		t.Fatal("expected a timeout")
+	}
+	if time.Since(start) > 2*time.Second {
+		t.Errorf("the request took %v to time out", time.Since(start))
+	}
+	if message := fetchErrorMessage(err); message != "The link took too long to respond" {
+		t.Errorf("message = %q for %v", message, err)
+	}
+}
+
+func TestSafeFetcherContentType(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", r.URL.Query().Get("type"))
+		w.Write([]byte("{}"))
+	}))
+	defer srv.Close()
+
+	tests := []struct {
+		contentType string
+		ok          bool
+	}{
+		{"application/json", true},
+		{"application/json; charset=utf-8", true},
+		{"text/plain", true},
+		{"text/html", false},
+		{"image/png", false},
+		{"", false},
+	}
+	for _, tt := range tests {
+		_, err := localFetcher(1024, "application/json", "text/plain").Get(context.Background(), srv.URL+"?type="+url.QueryEscape(tt.contentType))
+		if tt.ok && err != nil {
+			t.Errorf("%q: %v", tt.contentType, err)
+		}
+		if !tt.ok && !errors.Is(err, errFetchContentType) {
+			t.Errorf("%q: err = %v, want %v", tt.contentType, err, errFetchContentType)
+		}
+	}
+}
+
+func TestSafeFetcherStatus(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/missing":
+			http.NotFound(w, r)
+		case "/error":
+			http.Error(w, "oops", http.StatusInternalServerError)
+		case "/empty":
+			w.WriteHeader(http.StatusNoContent)
+		}
+	}))
+	defer srv.Close()
+
+	for _, path := range []string{"/missing", "/error", "/empty"} {
+		if _, err := localFetcher(1024).Get(context.Background(), srv.URL+path); !errors.Is(err, errFetchStatus) {
+			t.Errorf("%s: err = %v, want %v", path, err, errFetchStatus)
+		}
+	}
+}
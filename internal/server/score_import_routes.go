@@ -0,0 +1,217 @@
+package server
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"io"
+	"log"
+	"net/http"
+	"path/filepath"
+	"practicebetter/internal/ck"
+	"practicebetter/internal/config"
+	"practicebetter/internal/db"
+	"practicebetter/internal/pages/librarypages"
+	"practicebetter/internal/scoreimport"
+	"strconv"
+	"strings"
+
+	"github.com/gorilla/csrf"
+	"github.com/mavolin/go-htmx"
+	"github.com/nrednav/cuid2"
+)
+
+func (s *Server) importMusicXMLForm(w http.ResponseWriter, r *http.Request) {
+	token := csrf.Token(r)
+	s.HxRender(w, r, librarypages.ImportMusicXMLPage(token), "Import MusicXML")
+}
+
+func (s *Server) importMusicXML(w http.ResponseWriter, r *http.Request) {
+	user := r.Context().Value(ck.UserKey).(db.User)
+
+	data, filename, ok := s.readScoreUpload(w, r)
+	if !ok {
+		return
+	}
+	score, err := scoreimport.ParseMusicXML(data, config.MAX_SCORE_SIZE, config.MAX_PIECE_MEASURES)
+	if err != nil {
+		log.Default().Println(err)
+		if errors.Is(err, scoreimport.ErrTooLarge) {
+			s.importFailedError(w, r, "The score is too big to import")
+		} else {
+			s.importFailedError(w, r, "Could not read the file, make sure it is a MusicXML or MXL file")
+		}
+		return
+	}
+	s.createPieceFromScore(w, r, user.ID, score, filename)
+}
+
+// readScoreUpload reads the uploaded score file. If it returns false the error has already been sent.
+func (s *Server) readScoreUpload(w http.ResponseWriter, r *http.Request) ([]byte, string, bool) {
+	r.Body = http.MaxBytesReader(w, r.Body, config.MAX_SCORE_UPLOAD_SIZE)
+	if err := r.ParseMultipartForm(config.MAX_SCORE_UPLOAD_SIZE); err != nil {
+		log.Default().Println(err)
+		s.importFailedError(w, r, "The uploaded file is too big. Please choose a file that's less than 10MB in size")
+		return nil, "", false
+	}
+	file, fileHeader, err := r.FormFile("file")
+	if err != nil {
+		log.Default().Println(err)
+		s.InvalidInputError(w, r, "Choose a file to import")
+		return nil, "", false
+	}
+	defer file.Close()
+	data, err := io.ReadAll(file)
+	if err != nil {
+		log.Default().Println(err)
+		s.importFailedError(w, r, "Could not read the uploaded file")
+		return nil, "", false
+	}
+	return data, fileHeader.Filename, true
+}
+
+// scoreSpotRanges picks the spots to create from the options in ImportScoreSpotFields
+func scoreSpotRanges(r *http.Request, score scoreimport.Score) ([]scoreimport.SpotRange, string) {
+	switch r.FormValue("spots") {
+	case "every":
+		size, err := strconv.Atoi(r.FormValue("spotSize"))
+		if err != nil || size < 1 {
+			return nil, "Measures per spot must be a positive number"
+		}
+		// count the spots first so a tiny size can't build a huge list of ranges
+		count := score.Measures / size
+		if score.Measures%size != 0 {
+			count++
+		}
+		if count > config.MAX_SCORE_SPOTS {
+			return nil, "That would create too many spots, try more measures per spot"
+		}
+		return score.SpotsEvery(size), ""
+	case "marks":
+		return score.SpotsAtMarks(), ""
+	default:
+		return nil, ""
+	}
+}
+
+// scoreKeyAndMode finds the ids for the score's key and mode, if there are any
+func scoreKeyAndMode(ctx context.Context, queries *db.Queries, score scoreimport.Score) (sql.NullInt64, sql.NullInt64, error) {
+	keyID := sql.NullInt64{Valid: false}
+	modeID := sql.NullInt64{Valid: false}
+	if score.Key == "" || score.Mode == "" {
+		return keyID, modeID, nil
+	}
+	keys, err := queries.ListKeys(ctx)
+	if err != nil {
+		return keyID, modeID, err
+	}
+	for _, key := range keys {
+		if key.Name == score.Key {
+			keyID = sql.NullInt64{Int64: key.ID, Valid: true}
+		}
+	}
+	for _, basic := range []bool{true, false} {
+		modes, err := queries.ListModes(ctx, basic)
+		if err != nil {
+			return keyID, modeID, err
+		}
+		for _, mode := range modes {
+			if mode.Name == score.Mode {
+				modeID = sql.NullInt64{Int64: mode.ID, Valid: true}
+			}
+		}
+	}
+	if !keyID.Valid || !modeID.Valid {
+		return sql.NullInt64{Valid: false}, sql.NullInt64{Valid: false}, nil
+	}
+	return keyID, modeID, nil
+}
+
+// createPieceFromScore saves the piece and any spots the user asked for, then sends them to the new piece
+func (s *Server) createPieceFromScore(w http.ResponseWriter, r *http.Request, userID string, score scoreimport.Score, filename string) {
+	if message := pieceMeasuresMessage(int64(score.Measures)); message != "" {
+		s.InvalidInputError(w, r, message)
+		return
+	}
+	spots, message := scoreSpotRanges(r, score)
+	if message != "" {
+		s.InvalidInputError(w, r, message)
+		return
+	}
+	if len(spots) > config.MAX_SCORE_SPOTS {
+		s.InvalidInputError(w, r, "That would create too many spots, try more measures per spot")
+		return
+	}
+	if score.Title == "" {
+		score.Title = strings.TrimSuffix(filepath.Base(filename), filepath.Ext(filename))
+	}
+
+	tx, err := s.DB.BeginTx(r.Context(), nil)
+	if err != nil {
+		s.DatabaseError(w, r, err, "Could not import piece")
+		return
+	}
+	defer func() {
+		if err := tx.Rollback(); err != nil && !errors.Is(err, sql.ErrTxDone) {
+			log.Default().Println(err)
+		}
+	}()
+	qtx := db.New(s.DB).WithTx(tx)
+
+	keyID, modeID, err := scoreKeyAndMode(r.Context(), qtx, score)
+	if err != nil {
+		s.DatabaseError(w, r, err, "Could not import piece")
+		return
+	}
+	piece, err := qtx.CreatePiece(r.Context(), db.CreatePieceParams{
+		ID:              cuid2.Generate(),
+		Title:           score.Title,
+		Description:     sql.NullString{Valid: false},
+		Composer:        sql.NullString{String: score.Composer, Valid: score.Composer != ""},
+		Measures:        sql.NullInt64{Int64: int64(score.Measures), Valid: score.Measures > 0},
+		BeatsPerMeasure: sql.NullInt64{Int64: int64(score.BeatsPerMeasure), Valid: score.BeatsPerMeasure > 0},
+		GoalTempo:       sql.NullInt64{Int64: int64(score.Tempo), Valid: score.Tempo > 0},
+		UserID:          userID,
+		KeyID:           keyID,
+		ModeID:          modeID,
+	})
+	if err != nil {
+		s.DatabaseError(w, r, err, "Could not import piece")
+		return
+	}
+	for _, spot := range spots {
+		if _, err := qtx.CreateSpot(r.Context(), db.CreateSpotParams{
+			ID:             cuid2.Generate(),
+			Name:           spot.Name,
+			Stage:          "repeat",
+			Measures:       sql.NullString{String: spot.String(), Valid: true},
+			MeasuresStart:  sql.NullInt64{Int64: int64(spot.Start), Valid: true},
+			MeasuresEnd:    sql.NullInt64{Int64: int64(spot.End), Valid: true},
+			AudioPromptUrl: "",
+			ImagePromptUrl: "",
+			NotesPrompt:    "",
+			TextPrompt:     "",
+			CurrentTempo:   sql.NullInt64{Valid: false},
+			PieceID:        piece.ID,
+			UserID:         userID,
+		}); err != nil {
+			s.DatabaseError(w, r, err, "Could not create spots")
+			return
+		}
+	}
+	if err := tx.Commit(); err != nil {
+		s.DatabaseError(w, r, err, "Could not import piece")
+		return
+	}
+
+	if err := htmx.Trigger(r, "ShowAlert", ShowAlertEvent{
+		Message:  "Added Piece: " + piece.Title + " with " + strconv.Itoa(len(spots)) + " spots",
+		Title:    "Piece Imported!",
+		Variant:  "success",
+		Duration: 3000,
+	}); err != nil {
+		log.Default().Println(err)
+	}
+	htmx.Redirect(r, "/library/pieces/"+piece.ID)
+	http.Redirect(w, r, "/library/pieces/"+piece.ID, http.StatusSeeOther)
+}
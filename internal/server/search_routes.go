@@ -0,0 +1,83 @@
+package server
+
+import (
+	"log"
+	"net/http"
+	"practicebetter/internal/ck"
+	"practicebetter/internal/config"
+	"practicebetter/internal/db"
+	"practicebetter/internal/pages/librarypages"
+	"strings"
+
+	"github.com/mavolin/go-htmx"
+)
+
+// ftsQuery turns what the user typed into an fts5 query. Each word is quoted so punctuation can't
+// break the query syntax, and matches as a prefix so results show up while typing.
+func ftsQuery(input string) string {
+	words := strings.Fields(input)
+	terms := make([]string, 0, len(words))
+	for _, word := range words {
+		terms = append(terms, `"`+strings.ReplaceAll(word, `"`, `""`)+`"*`)
+	}
+	return strings.Join(terms, " ")
+}
+
+func (s *Server) search(w http.ResponseWriter, r *http.Request) {
+	user := r.Context().Value(ck.UserKey).(db.User)
+	results := librarypages.SearchResults{
+		Query: strings.TrimSpace(r.URL.Query().Get("q")),
+	}
+
+	if query := ftsQuery(results.Query); query != "" {
+		queries := db.New(s.DB)
+		var err error
+		results.Pieces, err = queries.SearchPieces(r.Context(), db.SearchPiecesParams{
+			Query:  query,
+			UserID: user.ID,
+			Limit:  config.SEARCH_RESULT_LIMIT,
+		})
+		if err != nil {
+			s.DatabaseError(w, r, err, "Could not search pieces")
+			return
+		}
+		results.Spots, err = queries.SearchSpots(r.Context(), db.SearchSpotsParams{
+			Query:  query,
+			UserID: user.ID,
+			Limit:  config.SEARCH_RESULT_LIMIT,
+		})
+		if err != nil {
+			s.DatabaseError(w, r, err, "Could not search spots")
+			return
+		}
+		results.Reading, err = queries.SearchReadingItems(r.Context(), db.SearchReadingItemsParams{
+			Query:  query,
+			UserID: user.ID,
+			Limit:  config.SEARCH_RESULT_LIMIT,
+		})
+		if err != nil {
+			s.DatabaseError(w, r, err, "Could not search sight reading")
+			return
+		}
+		results.Notes, err = queries.SearchPracticeNotes(r.Context(), db.SearchPracticeNotesParams{
+			Query:  query,
+			UserID: user.ID,
+			Limit:  config.SEARCH_RESULT_LIMIT,
+		})
+		if err != nil {
+			s.DatabaseError(w, r, err, "Could not search practice notes")
+			return
+		}
+	}
+
+	// typing in the search box only replaces the results
+	if hxRequest := htmx.Request(r); hxRequest != nil && hxRequest.Target == "search-results" {
+		w.Header().Set("Content-Type", "text/html")
+		if err := librarypages.SearchResultsList(results).Render(r.Context(), w); err != nil {
+			log.Default().Println(err)
+			http.Error(w, "Render Error", http.StatusInternalServerError)
+		}
+		return
+	}
+	s.HxRender(w, r, librarypages.SearchPage(results), "Search")
+}
@@ -0,0 +1,357 @@
+package server
+
+import (
+	"database/sql"
+	"errors"
+	"log"
+	"net/http"
+	"practicebetter/internal/ck"
+	"practicebetter/internal/db"
+	"practicebetter/internal/pages/librarypages"
+	"strconv"
+	"strings"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/gorilla/csrf"
+	"github.com/mavolin/go-htmx"
+	"github.com/nrednav/cuid2"
+)
+
+func (s *Server) pieceSections(w http.ResponseWriter, r *http.Request) {
+	pieceID := chi.URLParam(r, "pieceID")
+	user := r.Context().Value(ck.UserKey).(db.User)
+	s.renderPieceSections(w, r, pieceID, user.ID)
+}
+
+func (s *Server) renderPieceSections(w http.ResponseWriter, r *http.Request, pieceID string, userID string) {
+	queries := db.New(s.DB)
+	piece, err := queries.GetPieceWithoutSpots(r.Context(), db.GetPieceWithoutSpotsParams{
+		ID:     pieceID,
+		UserID: userID,
+	})
+	if err != nil {
+		log.Default().Println(err)
+		http.Error(w, "Could not find matching piece", http.StatusNotFound)
+		return
+	}
+	sections, err := queries.ListPieceSections(r.Context(), db.ListPieceSectionsParams{
+		UserID:  userID,
+		PieceID: pieceID,
+	})
+	if err != nil {
+		s.DatabaseError(w, r, err, "Could not get sections")
+		return
+	}
+	spotsSections, err := queries.ListPieceSpotsSections(r.Context(), db.ListPieceSpotsSectionsParams{
+		UserID:  userID,
+		PieceID: pieceID,
+	})
+	if err != nil {
+		s.DatabaseError(w, r, err, "Could not get section spots")
+		return
+	}
+	spotCounts := make(map[string]int, len(sections))
+	for _, row := range spotsSections {
+		spotCounts[row.SectionID]++
+	}
+	sectionInfo := make([]librarypages.PieceSection, 0, len(sections))
+	for _, section := range sections {
+		sectionInfo = append(sectionInfo, librarypages.PieceSection{
+			Section:   section,
+			SpotCount: spotCounts[section.ID],
+		})
+	}
+
+	token := csrf.Token(r)
+	s.HxRender(w, r, librarypages.PieceSectionsPage(s, token, piece, sectionInfo), piece.Title)
+}
+
+func (s *Server) renderSectionForm(w http.ResponseWriter, r *http.Request, section db.Section, pieceID string, userID string) {
+	queries := db.New(s.DB)
+	piece, err := queries.GetPieceWithoutSpots(r.Context(), db.GetPieceWithoutSpotsParams{
+		ID:     pieceID,
+		UserID: userID,
+	})
+	if err != nil {
+		log.Default().Println(err)
+		http.Error(w, "Could not find matching piece", http.StatusNotFound)
+		return
+	}
+	spots, err := queries.ListPieceSpots(r.Context(), db.ListPieceSpotsParams{
+		UserID:  userID,
+		PieceID: pieceID,
+	})
+	if err != nil {
+		s.DatabaseError(w, r, err, "Could not get spots")
+		return
+	}
+	selectedSpotIDs := make(map[string]bool)
+	if section.ID != "" {
+		spotIDs, err := queries.ListSectionSpotIDs(r.Context(), db.ListSectionSpotIDsParams{
+			SectionID: section.ID,
+			UserID:    userID,
+			PieceID:   pieceID,
+		})
+		if err != nil {
+			s.DatabaseError(w, r, err, "Could not get section spots")
+			return
+		}
+		for _, spotID := range spotIDs {
+			selectedSpotIDs[spotID] = true
+		}
+	}
+
+	token := csrf.Token(r)
+	s.HxRender(w, r, librarypages.SectionFormPage(s, token, piece, section, spots, selectedSpotIDs), piece.Title)
+}
+
+func (s *Server) createSectionForm(w http.ResponseWriter, r *http.Request) {
+	pieceID := chi.URLParam(r, "pieceID")
+	user := r.Context().Value(ck.UserKey).(db.User)
+	s.renderSectionForm(w, r, db.Section{}, pieceID, user.ID)
+}
+
+func (s *Server) editSection(w http.ResponseWriter, r *http.Request) {
+	pieceID := chi.URLParam(r, "pieceID")
+	sectionID := chi.URLParam(r, "sectionID")
+	user := r.Context().Value(ck.UserKey).(db.User)
+	queries := db.New(s.DB)
+	section, err := queries.GetSection(r.Context(), db.GetSectionParams{
+		SectionID: sectionID,
+		UserID:    user.ID,
+		PieceID:   pieceID,
+	})
+	if err != nil {
+		log.Default().Println(err)
+		http.Error(w, "Could not find matching section", http.StatusNotFound)
+		return
+	}
+	s.renderSectionForm(w, r, section, pieceID, user.ID)
+}
+
+type sectionForm struct {
+	Name         string
+	Description  string
+	StartMeasure sql.NullInt64
+	EndMeasure   sql.NullInt64
+	SpotIDs      []string
+}
+
+func parseMeasureField(value string) (sql.NullInt64, bool) {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return sql.NullInt64{}, true
+	}
+	measure, err := strconv.ParseInt(value, 10, 64)
+	if err != nil || measure < 1 {
+		return sql.NullInt64{}, false
+	}
+	return sql.NullInt64{Int64: measure, Valid: true}, true
+}
+
+func parseSectionForm(r *http.Request) (sectionForm, string) {
+	var form sectionForm
+	form.Name = strings.TrimSpace(r.Form.Get("name"))
+	if form.Name == "" {
+		return form, "Your section needs a name"
+	}
+	form.Description = strings.TrimSpace(r.Form.Get("description"))
+	var ok bool
+	if form.StartMeasure, ok = parseMeasureField(r.Form.Get("start_measure")); !ok {
+		return form, "The first measure must be a positive number"
+	}
+	if form.EndMeasure, ok = parseMeasureField(r.Form.Get("end_measure")); !ok {
+		return form, "The last measure must be a positive number"
+	}
+	if form.StartMeasure.Valid && form.EndMeasure.Valid && form.EndMeasure.Int64 < form.StartMeasure.Int64 {
+		return form, "The last measure must come after the first measure"
+	}
+	form.SpotIDs = r.Form["spots"]
+	return form, ""
+}
+
+func (s *Server) createSection(w http.ResponseWriter, r *http.Request) {
+	pieceID := chi.URLParam(r, "pieceID")
+	user := r.Context().Value(ck.UserKey).(db.User)
+	if err := r.ParseForm(); err != nil {
+		log.Default().Println(err)
+		s.InvalidInputError(w, r, "Invalid input")
+		return
+	}
+	form, message := parseSectionForm(r)
+	if message != "" {
+		s.InvalidInputError(w, r, message)
+		return
+	}
+
+	tx, err := s.DB.Begin()
+	if err != nil {
+		s.DatabaseError(w, r, err, "Could not connect to database")
+		return
+	}
+	defer func() {
+		if err := tx.Rollback(); err != nil && !errors.Is(err, sql.ErrTxDone) {
+			log.Default().Println(err)
+		}
+	}()
+	qtx := db.New(s.DB).WithTx(tx)
+
+	section, err := qtx.CreateSection(r.Context(), db.CreateSectionParams{
+		ID:           cuid2.Generate(),
+		Name:         form.Name,
+		Description:  form.Description,
+		UserID:       user.ID,
+		PieceID:      pieceID,
+		StartMeasure: form.StartMeasure,
+		EndMeasure:   form.EndMeasure,
+	})
+	if err != nil {
+		s.DatabaseError(w, r, err, "Could not create section")
+		return
+	}
+	if err := setSectionSpots(r, qtx, section.ID, pieceID, user.ID, form.SpotIDs); err != nil {
+		s.DatabaseError(w, r, err, "Could not add spots to section")
+		return
+	}
+	if err := tx.Commit(); err != nil {
+		s.DatabaseError(w, r, err, "Could not create section")
+		return
+	}
+
+	if err := htmx.Trigger(r, "ShowAlert", ShowAlertEvent{
+		Message:  "Created section " + section.Name,
+		Title:    "Section Created!",
+		Variant:  "success",
+		Duration: 3000,
+	}); err != nil {
+		log.Default().Println(err)
+	}
+	htmx.PushURL(r, "/library/pieces/"+pieceID+"/sections")
+	s.renderPieceSections(w, r, pieceID, user.ID)
+}
+
+func (s *Server) updateSection(w http.ResponseWriter, r *http.Request) {
+	pieceID := chi.URLParam(r, "pieceID")
+	sectionID := chi.URLParam(r, "sectionID")
+	user := r.Context().Value(ck.UserKey).(db.User)
+	if err := r.ParseForm(); err != nil {
+		log.Default().Println(err)
+		s.InvalidInputError(w, r, "Invalid input")
+		return
+	}
+	form, message := parseSectionForm(r)
+	if message != "" {
+		s.InvalidInputError(w, r, message)
+		return
+	}
+
+	tx, err := s.DB.Begin()
+	if err != nil {
+		s.DatabaseError(w, r, err, "Could not connect to database")
+		return
+	}
+	defer func() {
+		if err := tx.Rollback(); err != nil && !errors.Is(err, sql.ErrTxDone) {
+			log.Default().Println(err)
+		}
+	}()
+	qtx := db.New(s.DB).WithTx(tx)
+
+	section, err := qtx.UpdateSection(r.Context(), db.UpdateSectionParams{
+		Name:         form.Name,
+		Description:  form.Description,
+		StartMeasure: form.StartMeasure,
+		EndMeasure:   form.EndMeasure,
+		SectionID:    sectionID,
+		UserID:       user.ID,
+		PieceID:      pieceID,
+	})
+	if err != nil {
+		s.DatabaseError(w, r, err, "Could not update section")
+		return
+	}
+	if err := setSectionSpots(r, qtx, section.ID, pieceID, user.ID, form.SpotIDs); err != nil {
+		s.DatabaseError(w, r, err, "Could not update section spots")
+		return
+	}
+	if err := tx.Commit(); err != nil {
+		s.DatabaseError(w, r, err, "Could not update section")
+		return
+	}
+
+	if err := htmx.Trigger(r, "ShowAlert", ShowAlertEvent{
+		Message:  "Updated section " + section.Name,
+		Title:    "Section Updated!",
+		Variant:  "success",
+		Duration: 3000,
+	}); err != nil {
+		log.Default().Println(err)
+	}
+	htmx.PushURL(r, "/library/pieces/"+pieceID+"/sections")
+	s.renderPieceSections(w, r, pieceID, user.ID)
+}
+
+// setSectionSpots replaces the spots in a section, spots from other pieces are ignored by the query
+func setSectionSpots(r *http.Request, qtx *db.Queries, sectionID, pieceID, userID string, spotIDs []string) error {
+	if err := qtx.ClearSectionSpots(r.Context(), db.ClearSectionSpotsParams{
+		SectionID: sectionID,
+		UserID:    userID,
+		PieceID:   pieceID,
+	}); err != nil {
+		return err
+	}
+	for _, spotID := range spotIDs {
+		if err := qtx.AddSpotToSection(r.Context(), db.AddSpotToSectionParams{
+			SpotID:    spotID,
+			SectionID: sectionID,
+			UserID:    userID,
+			PieceID:   pieceID,
+		}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (s *Server) deleteSection(w http.ResponseWriter, r *http.Request) {
+	pieceID := chi.URLParam(r, "pieceID")
+	sectionID := chi.URLParam(r, "sectionID")
+	user := r.Context().Value(ck.UserKey).(db.User)
+	queries := db.New(s.DB)
+	if err := queries.DeleteSection(r.Context(), db.DeleteSectionParams{
+		SectionID: sectionID,
+		UserID:    user.ID,
+		PieceID:   pieceID,
+	}); err != nil {
+		s.DatabaseError(w, r, err, "Could not delete section")
+		return
+	}
+	if err := htmx.Trigger(r, "ShowAlert", ShowAlertEvent{
+		Message:  "Deleted section",
+		Title:    "Section Deleted",
+		Variant:  "success",
+		Duration: 3000,
+	}); err != nil {
+		log.Default().Println(err)
+	}
+	htmx.PushURL(r, "/library/pieces/"+pieceID+"/sections")
+	s.renderPieceSections(w, r, pieceID, user.ID)
+}
+
+// getPracticeSection returns the section requested for a practice page, or false if the whole piece should be used
+func (s *Server) getPracticeSection(r *http.Request, pieceID string, userID string) (db.Section, bool) {
+	sectionID := r.URL.Query().Get("section")
+	if sectionID == "" {
+		return db.Section{}, false
+	}
+	section, err := db.New(s.DB).GetSection(r.Context(), db.GetSectionParams{
+		SectionID: sectionID,
+		UserID:    userID,
+		PieceID:   pieceID,
+	})
+	if err != nil {
+		log.Default().Println(err)
+		return db.Section{}, false
+	}
+	return section, true
+}
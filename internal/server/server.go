@@ -3,13 +3,13 @@ package server
 import (
 	"context"
 	"database/sql"
+	"errors"
 	"fmt"
 	"log"
 	"net/http"
 	"net/url"
 	"os"
 	"practicebetter/internal/ck"
-	"practicebetter/internal/config"
 	"practicebetter/internal/db"
 	"practicebetter/internal/static"
 	"strconv"
@@ -48,6 +48,30 @@ func getEnvOrPanic(key string) string {
 	return value
 }
 
+// requireFTS5 makes sure sqlite was built with full text search. The search triggers on pieces, spots,
+// reading and plans make every change to those tables fail without it, so a build missing the
+// sqlite_fts5 tag couldn't save anything.
+func requireFTS5(pool *sql.DB) error {
+	rows, err := pool.Query("PRAGMA compile_options")
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var option string
+		if err := rows.Scan(&option); err != nil {
+			return err
+		}
+		if option == "ENABLE_FTS5" {
+			return nil
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return err
+	}
+	return errors.New("sqlite was built without FTS5, build with -tags sqlite_fts5")
+}
+
 func NewServer() *http.Server {
 
 	// SETUP DATABASE
@@ -64,6 +88,10 @@ func NewServer() *http.Server {
 	pool.SetMaxOpenConns(4)
 	pool.SetMaxIdleConns(4)
 
+	if err := requireFTS5(pool); err != nil {
+		panic(err)
+	}
+
 	// SETUP SESSIONS
 	redisUri := getEnvOrPanic("REDIS_URI")
 	redisPool := &redis.Pool{
@@ -136,6 +164,10 @@ func NewServer() *http.Server {
 		Hostname:       hostname,
 	}
 
+	if err := NewServer.backfillSpotMeasureRanges(context.Background()); err != nil {
+		log.Printf("could not fill in spot measure ranges: %v", err)
+	}
+
 	// Declare Server config
 	server := &http.Server{
 		Addr:         fmt.Sprintf("0.0.0.0:%d", NewServer.port),
@@ -148,6 +180,50 @@ func NewServer() *http.Server {
 	return server
 }
 
+// backfillSpotMeasureRanges reads the measures of spots saved before their first and last measure
+// were stored. Spots saved since then get their range when they're saved, so this only has to run
+// once, and measures that can't be read are left without a range instead of being tried every start.
+func (s *Server) backfillSpotMeasureRanges(ctx context.Context) error {
+	const migration = "spot_measure_ranges"
+	tx, err := s.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err := tx.Rollback(); err != nil && !errors.Is(err, sql.ErrTxDone) {
+			log.Default().Println(err)
+		}
+	}()
+	qtx := db.New(s.DB).WithTx(tx)
+
+	if _, err := qtx.GetDataMigration(ctx, migration); err == nil {
+		return nil
+	} else if !errors.Is(err, sql.ErrNoRows) {
+		return err
+	}
+	spots, err := qtx.ListSpotsWithoutMeasureRange(ctx)
+	if err != nil {
+		return err
+	}
+	for _, spot := range spots {
+		measuresStart, measuresEnd := spotMeasureRange(spot.Measures)
+		if !measuresStart.Valid {
+			continue
+		}
+		if err := qtx.UpdateSpotMeasureRange(ctx, db.UpdateSpotMeasureRangeParams{
+			MeasuresStart: measuresStart,
+			MeasuresEnd:   measuresEnd,
+			SpotID:        spot.ID,
+		}); err != nil {
+			return err
+		}
+	}
+	if err := qtx.RecordDataMigration(ctx, migration); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
 func (s *Server) SendEmail(to, subject, body string) {
 
 	email := mail.NewMSG()
@@ -223,7 +299,7 @@ func (s *Server) GetActivePracticePlanID(ctx context.Context) (string, bool) {
 		return "", false
 	}
 	if user.ActivePracticePlanID.Valid {
-		if !user.ActivePracticePlanStarted.Valid || time.Since(time.Unix(user.ActivePracticePlanStarted.Int64, 0)) > config.RESUME_PLAN_TIME_LIMIT {
+		if !user.ActivePracticePlanStarted.Valid || !canResumePlan(user.ActivePracticePlanStarted.Int64, time.Now(), userLocation(user)) {
 			err := queries.ClearActivePracticePlan(ctx, user.ID)
 			if err != nil {
 				log.Default().Printf("failed to clear active practice plan: %v\n", err)
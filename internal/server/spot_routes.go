@@ -1,6 +1,7 @@
 package server
 
 import (
+	"context"
 	"database/sql"
 	"encoding/json"
 	"errors"
@@ -11,7 +12,9 @@ import (
 	"practicebetter/internal/components"
 	"practicebetter/internal/config"
 	"practicebetter/internal/db"
+	"practicebetter/internal/measurerange"
 	"practicebetter/internal/pages/librarypages"
+	"practicebetter/internal/stages"
 	"strconv"
 	"time"
 
@@ -48,7 +51,7 @@ func (s *Server) singleSpot(w http.ResponseWriter, r *http.Request) {
 	}
 
 	token := csrf.Token(r)
-	s.HxRender(w, r, librarypages.SingleSpot(s, spot, token), spot.Name+" - "+spot.PieceTitle)
+	s.HxRender(w, r, librarypages.SingleSpot(s, spot, spotTempoChart(r.Context(), queries, user.ID, spot), token), spot.Name+" - "+spot.PieceTitle)
 }
 
 func (s *Server) addSpotsFromPDFPage(w http.ResponseWriter, r *http.Request) {
@@ -152,6 +155,7 @@ func (s *Server) addSpot(w http.ResponseWriter, r *http.Request) {
 		measures.String = measuresVal
 		measures.Valid = true
 	}
+	measuresStart, measuresEnd := spotMeasureRange(measures)
 	spot, err := queries.CreateSpot(r.Context(), db.CreateSpotParams{
 		UserID:         user.ID,
 		PieceID:        pieceID,
@@ -164,6 +168,8 @@ func (s *Server) addSpot(w http.ResponseWriter, r *http.Request) {
 		TextPrompt:     r.FormValue("textPrompt"),
 		CurrentTempo:   currentTempo,
 		Measures:       measures,
+		MeasuresStart:  measuresStart,
+		MeasuresEnd:    measuresEnd,
 	})
 	if err != nil {
 		s.DatabaseError(w, r, err, "Could not add spot")
@@ -279,6 +285,18 @@ func (s *Server) addSpotsFromPDF(w http.ResponseWriter, r *http.Request) {
 	}
 }
 
+// spotMeasureRange is the first and last measure of a spot, or null if its measures can't be read
+func spotMeasureRange(measures sql.NullString) (sql.NullInt64, sql.NullInt64) {
+	if !measures.Valid {
+		return sql.NullInt64{Valid: false}, sql.NullInt64{Valid: false}
+	}
+	measureRange, ok := measurerange.ParseSpot(measures.String)
+	if !ok {
+		return sql.NullInt64{Valid: false}, sql.NullInt64{Valid: false}
+	}
+	return sql.NullInt64{Int64: int64(measureRange.Start), Valid: true}, sql.NullInt64{Int64: int64(measureRange.End), Valid: true}
+}
+
 func makeSpotFormDataFromSpot(row db.GetSpotRow) SpotFormData {
 	var spot SpotFormData
 	spot.ID = &row.ID
@@ -403,7 +421,21 @@ func (s *Server) updateSpot(w http.ResponseWriter, r *http.Request) {
 	} else {
 		stageStarted = time.Now().Unix()
 	}
-	err = queries.UpdateSpot(r.Context(), db.UpdateSpotParams{
+	measuresStart, measuresEnd := spotMeasureRange(measures)
+	// the tempo may have been set somewhere else, like the piece form, so keep it before it's replaced
+	recordSpotTempo(r.Context(), queries, user.ID, spotID)
+	tx, err := s.DB.BeginTx(r.Context(), nil)
+	if err != nil {
+		s.DatabaseError(w, r, err, "Could not update spot")
+		return
+	}
+	defer func() {
+		if err := tx.Rollback(); err != nil && !errors.Is(err, sql.ErrTxDone) {
+			log.Default().Println(err)
+		}
+	}()
+	qtx := queries.WithTx(tx)
+	err = qtx.UpdateSpot(r.Context(), db.UpdateSpotParams{
 		Name:           r.FormValue("name"),
 		Stage:          r.FormValue("stage"),
 		StageStarted:   sql.NullInt64{Int64: stageStarted, Valid: true},
@@ -413,6 +445,8 @@ func (s *Server) updateSpot(w http.ResponseWriter, r *http.Request) {
 		TextPrompt:     r.FormValue("textPrompt"),
 		CurrentTempo:   currentTempo,
 		Measures:       measures,
+		MeasuresStart:  measuresStart,
+		MeasuresEnd:    measuresEnd,
 		SpotID:         spotID,
 		UserID:         user.ID,
 		PieceID:        pieceID,
@@ -421,6 +455,16 @@ func (s *Server) updateSpot(w http.ResponseWriter, r *http.Request) {
 		s.DatabaseError(w, r, err, "Could not update spot")
 		return
 	}
+	// the stage can be changed by hand on the edit form, which belongs in the history like any other move
+	if err := recordSpotStageChange(r.Context(), qtx, user.ID, spotID, spotStageInfo.Stage, ""); err != nil {
+		s.DatabaseError(w, r, err, "Could not update spot")
+		return
+	}
+	if err := tx.Commit(); err != nil {
+		s.DatabaseError(w, r, err, "Could not update spot")
+		return
+	}
+	recordSpotTempo(r.Context(), queries, user.ID, spotID)
 	spot, err := queries.GetSpot(r.Context(), db.GetSpotParams{
 		SpotID:  spotID,
 		UserID:  user.ID,
@@ -442,7 +486,7 @@ func (s *Server) updateSpot(w http.ResponseWriter, r *http.Request) {
 		log.Default().Println(err)
 	}
 	token := csrf.Token(r)
-	s.HxRender(w, r, librarypages.SingleSpot(s, spot, token), spot.Name+" - "+spot.PieceTitle)
+	s.HxRender(w, r, librarypages.SingleSpot(s, spot, spotTempoChart(r.Context(), queries, user.ID, spot), token), spot.Name+" - "+spot.PieceTitle)
 }
 
 type UpdatedSpot struct {
@@ -507,18 +551,22 @@ func (s *Server) updatePartialSpot(w http.ResponseWriter, r *http.Request) {
 		measures.Valid = true
 	}
 
+	measuresStart, measuresEnd := spotMeasureRange(measures)
 	updatedSpot, err := queries.UpdatePartialSpot(r.Context(), db.UpdatePartialSpotParams{
-		Name:         r.FormValue("name"),
-		CurrentTempo: currentTempo,
-		Measures:     measures,
-		SpotID:       spotID,
-		UserID:       user.ID,
-		PieceID:      pieceID,
+		Name:          r.FormValue("name"),
+		CurrentTempo:  currentTempo,
+		Measures:      measures,
+		MeasuresStart: measuresStart,
+		MeasuresEnd:   measuresEnd,
+		SpotID:        spotID,
+		UserID:        user.ID,
+		PieceID:       pieceID,
 	})
 	if err != nil {
 		s.DatabaseError(w, r, err, "Could not update spot")
 		return
 	}
+	recordSpotTempo(r.Context(), queries, user.ID, spotID)
 
 	updatedSpotInfo := UpdatedSpot{
 		Name:           updatedSpot.Name,
@@ -650,6 +698,11 @@ type RepeatPracticeInfo struct {
 	DurationMinutes int64
 	Success         bool
 	ToStage         string
+	// Attempts is every time the spot was played, Successes the clean ones, and Resets how many
+	// times a mistake sent the count back to zero. Older clients don't send them.
+	Attempts  int64
+	Successes int64
+	Resets    int64
 }
 
 func (s *Server) repeatPracticeSpotFinished(w http.ResponseWriter, r *http.Request) {
@@ -677,6 +730,25 @@ func (s *Server) repeatPracticeSpotFinished(w http.ResponseWriter, r *http.Reque
 		http.Error(w, err.Error(), http.StatusBadRequest)
 		return
 	}
+	if info.Attempts < 0 || info.Successes < 0 || info.Resets < 0 || info.Successes > info.Attempts {
+		http.Error(w, "Invalid attempt counts", http.StatusBadRequest)
+		return
+	}
+	hasCounts := info.Attempts > 0
+
+	// make sure the spot is the user's before saving anything against it
+	if _, err := qtx.GetSpotStageStarted(r.Context(), db.GetSpotStageStartedParams{
+		SpotID:  spotID,
+		UserID:  user.ID,
+		PieceID: pieceID,
+	}); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			http.Error(w, "Could not find matching spot", http.StatusNotFound)
+			return
+		}
+		s.DatabaseError(w, r, err, "Could not get spot")
+		return
+	}
 
 	// TODO: better error handling
 	activePracticePlanID, ok := s.GetActivePracticePlanID(r.Context())
@@ -700,59 +772,70 @@ func (s *Server) repeatPracticeSpotFinished(w http.ResponseWriter, r *http.Reque
 		}
 	}
 
+	if info.DurationMinutes > 0 {
+		if err := qtx.CreatePracticeSession(r.Context(), db.CreatePracticeSessionParams{
+			ID:              cuid2.Generate(),
+			UserID:          user.ID,
+			PracticeType:    "repeat",
+			DurationMinutes: info.DurationMinutes,
+			SpotID:          sql.NullString{String: spotID, Valid: true},
+			PieceID:         sql.NullString{String: pieceID, Valid: true},
+			PracticePlanID:  sql.NullString{String: activePracticePlanID, Valid: ok && activePracticePlanID != ""},
+		}); err != nil {
+			s.DatabaseError(w, r, err, "Could not save practice session")
+			return
+		}
+	}
+
+	fromStage, err := qtx.GetSpotStage(r.Context(), db.GetSpotStageParams{
+		SpotID: spotID,
+		UserID: user.ID,
+	})
+	if err != nil {
+		s.DatabaseError(w, r, err, "Could not get spot")
+		return
+	}
+
+	if err := qtx.CreateSpotEvent(r.Context(), db.CreateSpotEventParams{
+		ID:             cuid2.Generate(),
+		SpotID:         spotID,
+		UserID:         user.ID,
+		EventType:      "repeat",
+		PracticeType:   sql.NullString{String: "repeat", Valid: true},
+		Success:        sql.NullBool{Bool: info.Success, Valid: true},
+		Attempts:       sql.NullInt64{Int64: info.Attempts, Valid: hasCounts},
+		Successes:      sql.NullInt64{Int64: info.Successes, Valid: hasCounts},
+		Resets:         sql.NullInt64{Int64: info.Resets, Valid: hasCounts},
+		PracticePlanID: sql.NullString{String: activePracticePlanID, Valid: activePracticePlanID != ""},
+	}); err != nil {
+		s.DatabaseError(w, r, err, "Could not save spot history")
+		return
+	}
+
 	// TODO: update last practiced by default
 	if info.Success {
-		switch info.ToStage {
-		case "random":
-			if err := qtx.PromoteSpotToRandom(r.Context(), db.PromoteSpotToRandomParams{
-				UserID:  user.ID,
-				PieceID: pieceID,
-				SpotID:  spotID,
-			}); err != nil {
-				log.Default().Println(err)
-				http.Error(w, "Could not promote to random", http.StatusInternalServerError)
-				return
-			}
-			if err := qtx.UpdatePiecePracticed(r.Context(), db.UpdatePiecePracticedParams{
-				UserID:  user.ID,
-				PieceID: pieceID,
-			}); err != nil {
-				s.DatabaseError(w, r, err, "Could not update piece practiced")
-				return
-			}
-		case "extra_repeat":
-			if err := qtx.PromoteSpotToExtraRepeat(r.Context(), db.PromoteSpotToExtraRepeatParams{
-				UserID:  user.ID,
-				PieceID: pieceID,
-				SpotID:  spotID,
-			}); err != nil {
-				log.Default().Println(err)
-				http.Error(w, "Could not promote to more repeat", http.StatusInternalServerError)
-				return
-			}
-			if err := qtx.UpdatePiecePracticed(r.Context(), db.UpdatePiecePracticedParams{
-				UserID:  user.ID,
-				PieceID: pieceID,
-			}); err != nil {
-				s.DatabaseError(w, r, err, "Could not update piece practiced")
-				return
-			}
-		default:
-			if err := qtx.UpdateSpotPracticed(r.Context(), db.UpdateSpotPracticedParams{
-				SpotID: spotID,
-				UserID: user.ID,
-			}); err != nil {
-				log.Default().Println(err)
-				http.Error(w, "Could not promote to more repeat", http.StatusInternalServerError)
-				return
-			}
-			if err := qtx.UpdatePiecePracticed(r.Context(), db.UpdatePiecePracticedParams{
-				UserID:  user.ID,
-				PieceID: pieceID,
-			}); err != nil {
-				s.DatabaseError(w, r, err, "Could not update piece practiced")
-				return
-			}
+		rules, err := getStageRules(r.Context(), qtx, user.ID)
+		if err != nil {
+			s.DatabaseError(w, r, err, "Could not get stage rules")
+			return
+		}
+		toStage := stages.New(rules, time.Now().In(userLocation(user))).Repeat(fromStage, info.Success, info.ToStage)
+		if err := applyStageChange(r.Context(), qtx, user.ID, spotID, fromStage, toStage); err != nil {
+			log.Default().Println(err)
+			http.Error(w, "Could not update spot", http.StatusInternalServerError)
+			return
+		}
+		if err := qtx.UpdatePiecePracticed(r.Context(), db.UpdatePiecePracticedParams{
+			UserID:  user.ID,
+			PieceID: pieceID,
+		}); err != nil {
+			s.DatabaseError(w, r, err, "Could not update piece practiced")
+			return
+		}
+
+		if err := recordSpotStageChange(r.Context(), qtx, user.ID, spotID, fromStage, activePracticePlanID); err != nil {
+			s.DatabaseError(w, r, err, "Could not save spot history")
+			return
 		}
 	}
 
@@ -964,3 +1047,64 @@ func (s *Server) getPracticeSpotDisplay(w http.ResponseWriter, r *http.Request)
 		http.Error(w, "Render Error", http.StatusInternalServerError)
 	}
 }
+
+func recordSpotEvaluation(ctx context.Context, qtx *db.Queries, userID string, spotID string, practiceType string, evaluation string, planID string) error {
+	return qtx.CreateSpotEvent(ctx, db.CreateSpotEventParams{
+		ID:             cuid2.Generate(),
+		SpotID:         spotID,
+		UserID:         userID,
+		EventType:      "evaluation",
+		PracticeType:   sql.NullString{String: practiceType, Valid: true},
+		Evaluation:     sql.NullString{String: evaluation, Valid: true},
+		PracticePlanID: sql.NullString{String: planID, Valid: planID != ""},
+	})
+}
+
+// records a stage change event only if the spot has actually left fromStage,
+// the promote/demote queries leave the stage alone when they don't apply
+func recordSpotStageChange(ctx context.Context, qtx *db.Queries, userID string, spotID string, fromStage string, planID string) error {
+	return qtx.CreateSpotStageChangeEvent(ctx, db.CreateSpotStageChangeEventParams{
+		ID:             cuid2.Generate(),
+		UserID:         userID,
+		FromStage:      fromStage,
+		PracticePlanID: sql.NullString{String: planID, Valid: planID != ""},
+		SpotID:         spotID,
+	})
+}
+
+func (s *Server) spotHistory(w http.ResponseWriter, r *http.Request) {
+	pieceID := chi.URLParam(r, "pieceID")
+	spotID := chi.URLParam(r, "spotID")
+	user := r.Context().Value(ck.UserKey).(db.User)
+	queries := db.New(s.DB)
+
+	spot, err := queries.GetSpot(r.Context(), db.GetSpotParams{
+		SpotID:  spotID,
+		UserID:  user.ID,
+		PieceID: pieceID,
+	})
+	if err != nil {
+		log.Default().Println(err)
+		if err := htmx.Trigger(r, "ShowAlert", ShowAlertEvent{
+			Message:  "Could not find matching spot",
+			Title:    "Error",
+			Variant:  "error",
+			Duration: 3000,
+		}); err != nil {
+			log.Default().Println(err)
+		}
+		http.Error(w, "Not Found", http.StatusNotFound)
+		return
+	}
+
+	events, err := queries.ListSpotEvents(r.Context(), db.ListSpotEventsParams{
+		SpotID: spotID,
+		UserID: user.ID,
+	})
+	if err != nil {
+		s.DatabaseError(w, r, err, "Could not get spot history")
+		return
+	}
+
+	s.HxRender(w, r, librarypages.SpotHistory(spot, events), spot.Name+" History - "+spot.PieceTitle)
+}
@@ -0,0 +1,115 @@
+package server
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"log"
+	"net/http"
+	"practicebetter/internal/ck"
+	"practicebetter/internal/db"
+	"practicebetter/internal/pages/authpages"
+	"practicebetter/internal/stages"
+	"strconv"
+
+	"github.com/gorilla/csrf"
+	"github.com/mavolin/go-htmx"
+)
+
+// getStageRules gets the user's stage rules, users that have never saved any get the defaults
+func getStageRules(ctx context.Context, qtx *db.Queries, userID string) (db.StageRule, error) {
+	rules, err := qtx.GetStageRules(ctx, userID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return stages.DefaultRules(userID), nil
+	}
+	return rules, err
+}
+
+// applyStageChange moves the spot to its new stage, or just marks it practiced if it stays put.
+// The move only happens if the spot is still in fromStage.
+func applyStageChange(ctx context.Context, qtx *db.Queries, userID string, spotID string, fromStage string, toStage string) error {
+	if fromStage == toStage {
+		return qtx.UpdateSpotPracticed(ctx, db.UpdateSpotPracticedParams{
+			SpotID: spotID,
+			UserID: userID,
+		})
+	}
+	return qtx.ChangeSpotStage(ctx, db.ChangeSpotStageParams{
+		FromStage: fromStage,
+		ToStage:   toStage,
+		SpotID:    spotID,
+		UserID:    userID,
+	})
+}
+
+func (s *Server) updateStageRules(w http.ResponseWriter, r *http.Request) {
+	user := r.Context().Value(ck.UserKey).(db.User)
+	if err := r.ParseForm(); err != nil {
+		log.Default().Println(err)
+		s.InvalidInputError(w, r, "Invalid input")
+		return
+	}
+
+	rules := db.StageRule{
+		UserID:             user.ID,
+		RandomDemoteTo:     r.Form.Get("random_demote_to"),
+		InterleaveDemoteTo: r.Form.Get("interleave_demote_to"),
+		InfrequentDemoteTo: r.Form.Get("infrequent_demote_to"),
+	}
+	fields := []struct {
+		key  string
+		dest *int64
+	}{
+		{"random_promote_excellent", &rules.RandomPromoteExcellent},
+		{"random_promote_min_days", &rules.RandomPromoteMinDays},
+		{"random_demote_poor", &rules.RandomDemotePoor},
+		{"random_demote_stale_days", &rules.RandomDemoteStaleDays},
+		{"interleave_promote_excellent", &rules.InterleavePromoteExcellent},
+		{"interleave_promote_min_days", &rules.InterleavePromoteMinDays},
+		{"interleave_stale_days", &rules.InterleaveStaleDays},
+	}
+	for _, field := range fields {
+		value, err := strconv.ParseInt(r.Form.Get(field.key), 10, 64)
+		if err != nil {
+			s.InvalidInputError(w, r, "All of the stage rules need to be numbers")
+			return
+		}
+		*field.dest = value
+	}
+	if message := stages.Validate(rules); message != "" {
+		s.InvalidInputError(w, r, message)
+		return
+	}
+
+	queries := db.New(s.DB)
+	rules, err := queries.UpsertStageRules(r.Context(), db.UpsertStageRulesParams{
+		UserID:                     user.ID,
+		RandomPromoteExcellent:     rules.RandomPromoteExcellent,
+		RandomPromoteMinDays:       rules.RandomPromoteMinDays,
+		RandomDemotePoor:           rules.RandomDemotePoor,
+		RandomDemoteStaleDays:      rules.RandomDemoteStaleDays,
+		RandomDemoteTo:             rules.RandomDemoteTo,
+		InterleavePromoteExcellent: rules.InterleavePromoteExcellent,
+		InterleavePromoteMinDays:   rules.InterleavePromoteMinDays,
+		InterleaveStaleDays:        rules.InterleaveStaleDays,
+		InterleaveDemoteTo:         rules.InterleaveDemoteTo,
+		InfrequentDemoteTo:         rules.InfrequentDemoteTo,
+	})
+	if err != nil {
+		s.DatabaseError(w, r, err, "Could not save stage rules")
+		return
+	}
+
+	if err := htmx.TriggerAfterSettle(r, "ShowAlert", ShowAlertEvent{
+		Message:  "Successfully updated stage rules",
+		Title:    "Rules Updated!",
+		Variant:  "success",
+		Duration: 3000,
+	}); err != nil {
+		log.Default().Println(err)
+	}
+	if err := authpages.StageRulesForm(rules, csrf.Token(r)).Render(r.Context(), w); err != nil {
+		log.Default().Println(err)
+		http.Error(w, "Render Error", http.StatusInternalServerError)
+	}
+}
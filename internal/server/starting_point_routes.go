@@ -0,0 +1,264 @@
+package server
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"log"
+	"net/http"
+	"practicebetter/internal/ck"
+	"practicebetter/internal/config"
+	"practicebetter/internal/db"
+	"practicebetter/internal/measurerange"
+	"practicebetter/internal/pages/librarypages"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/mavolin/go-htmx"
+	"github.com/nrednav/cuid2"
+)
+
+// joinMeasureRanges writes ranges the same way the starting point summary does, like "1-4, 7"
+func joinMeasureRanges(ranges []measurerange.Range) string {
+	parts := make([]string, 0, len(ranges))
+	for _, r := range ranges {
+		parts = append(parts, r.String())
+	}
+	return strings.Join(parts, ", ")
+}
+
+type StartingPointNotes struct {
+	Rating          string `json:"rating"`
+	TroubleMeasures string `json:"troubleMeasures"`
+}
+
+func (s *Server) saveStartingPointNotes(w http.ResponseWriter, r *http.Request) {
+	pieceID := chi.URLParam(r, "pieceID")
+	sessionID := chi.URLParam(r, "sessionID")
+	user := r.Context().Value(ck.UserKey).(db.User)
+	var notes StartingPointNotes
+	if err := json.NewDecoder(r.Body).Decode(&notes); err != nil {
+		log.Default().Println(err)
+		http.Error(w, "Invalid notes", http.StatusBadRequest)
+		return
+	}
+
+	rating := sql.NullString{String: notes.Rating, Valid: notes.Rating != ""}
+	if rating.Valid && rating.String != "poor" && rating.String != "fine" && rating.String != "excellent" {
+		http.Error(w, "Invalid rating", http.StatusBadRequest)
+		return
+	}
+
+	queries := db.New(s.DB)
+	piece, err := queries.GetPieceWithoutSpots(r.Context(), db.GetPieceWithoutSpotsParams{
+		ID:     pieceID,
+		UserID: user.ID,
+	})
+	if err != nil {
+		log.Default().Println(err)
+		http.Error(w, "Could not find piece", http.StatusNotFound)
+		return
+	}
+	trouble := ""
+	if strings.TrimSpace(notes.TroubleMeasures) != "" {
+		ranges, err := measurerange.ParseList(notes.TroubleMeasures)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		for _, troubleRange := range ranges {
+			if piece.Measures.Valid && int64(troubleRange.End) > piece.Measures.Int64 {
+				http.Error(w, troubleRange.String()+" goes past the end of the piece", http.StatusBadRequest)
+				return
+			}
+		}
+		trouble = joinMeasureRanges(ranges)
+	}
+
+	if err := queries.UpdateStartingPointSessionNotes(r.Context(), db.UpdateStartingPointSessionNotesParams{
+		Rating:          rating,
+		TroubleMeasures: trouble,
+		SessionID:       sessionID,
+		UserID:          user.ID,
+		PieceID:         pieceID,
+	}); err != nil {
+		log.Default().Println(err)
+		http.Error(w, "Could not save notes", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(map[string]string{"status": "ok", "troubleMeasures": trouble}); err != nil {
+		log.Default().Println(err)
+	}
+}
+
+// startingPointTroubleSpots turns each range of trouble bars from a session into a new spot. Ranges
+// that already have a spot with the same measures are skipped.
+func (s *Server) startingPointTroubleSpots(w http.ResponseWriter, r *http.Request) {
+	pieceID := chi.URLParam(r, "pieceID")
+	sessionID := chi.URLParam(r, "sessionID")
+	user := r.Context().Value(ck.UserKey).(db.User)
+
+	tx, err := s.DB.BeginTx(r.Context(), nil)
+	if err != nil {
+		s.DatabaseError(w, r, err, "Could not create spots")
+		return
+	}
+	defer func() {
+		if err := tx.Rollback(); err != nil && !errors.Is(err, sql.ErrTxDone) {
+			log.Default().Println(err)
+		}
+	}()
+	qtx := db.New(s.DB).WithTx(tx)
+
+	session, err := qtx.GetStartingPointSession(r.Context(), db.GetStartingPointSessionParams{
+		SessionID: sessionID,
+		UserID:    user.ID,
+		PieceID:   pieceID,
+	})
+	if err != nil {
+		s.DatabaseError(w, r, err, "Could not find practice session")
+		return
+	}
+	if session.TroubleConverted {
+		s.InvalidInputError(w, r, "These trouble bars have already been turned into spots")
+		return
+	}
+	ranges, err := measurerange.ParseList(session.TroubleMeasures)
+	if err != nil {
+		s.InvalidInputError(w, r, "There are no trouble bars to turn into spots")
+		return
+	}
+	spots, err := qtx.ListPieceSpots(r.Context(), db.ListPieceSpotsParams{
+		UserID:  user.ID,
+		PieceID: pieceID,
+	})
+	if err != nil {
+		s.DatabaseError(w, r, err, "Could not get spots")
+		return
+	}
+	existing := make(map[measurerange.Range]bool, len(spots))
+	for _, spot := range spots {
+		if spot.MeasuresStart.Valid && spot.MeasuresEnd.Valid {
+			existing[measurerange.Range{Start: int(spot.MeasuresStart.Int64), End: int(spot.MeasuresEnd.Int64)}] = true
+		}
+	}
+
+	created := 0
+	for _, troubleRange := range ranges {
+		if existing[troubleRange] {
+			continue
+		}
+		if _, err := qtx.CreateSpot(r.Context(), db.CreateSpotParams{
+			ID:             cuid2.Generate(),
+			Name:           troubleRange.Name(),
+			Stage:          "repeat",
+			Measures:       sql.NullString{String: troubleRange.String(), Valid: true},
+			MeasuresStart:  sql.NullInt64{Int64: int64(troubleRange.Start), Valid: true},
+			MeasuresEnd:    sql.NullInt64{Int64: int64(troubleRange.End), Valid: true},
+			AudioPromptUrl: "",
+			ImagePromptUrl: "",
+			NotesPrompt:    "",
+			TextPrompt:     "",
+			CurrentTempo:   sql.NullInt64{Valid: false},
+			PieceID:        pieceID,
+			UserID:         user.ID,
+		}); err != nil {
+			s.DatabaseError(w, r, err, "Could not create spots")
+			return
+		}
+		existing[troubleRange] = true
+		created++
+	}
+	if err := qtx.MarkStartingPointTroubleConverted(r.Context(), db.MarkStartingPointTroubleConvertedParams{
+		SessionID: sessionID,
+		UserID:    user.ID,
+		PieceID:   pieceID,
+	}); err != nil {
+		s.DatabaseError(w, r, err, "Could not update practice session")
+		return
+	}
+	if err := tx.Commit(); err != nil {
+		s.DatabaseError(w, r, err, "Could not create spots")
+		return
+	}
+
+	message := "Added " + strconv.Itoa(created) + " spots from your trouble bars"
+	if created == 0 {
+		message = "Those trouble bars already have spots"
+	}
+	if err := htmx.Trigger(r, "ShowAlert", ShowAlertEvent{
+		Message:  message,
+		Title:    "Spots Created",
+		Variant:  "success",
+		Duration: 3000,
+	}); err != nil {
+		log.Default().Println(err)
+	}
+	htmx.Redirect(r, "/library/pieces/"+pieceID)
+	http.Redirect(w, r, "/library/pieces/"+pieceID, http.StatusSeeOther)
+}
+
+// startingPointHistory sums up the piece's recent starting point sessions for the piece page.
+// Measures that haven't been drilled in the last few days, or at all, are neglected.
+func startingPointHistory(ctx context.Context, queries *db.Queries, userID string, pieceID string, measures int) (librarypages.StartingPointHistory, error) {
+	now := time.Now()
+	history := librarypages.StartingPointHistory{
+		Measures:  make([]librarypages.DrilledMeasure, 0),
+		Recent:    make([]string, 0),
+		Neglected: make([]string, 0),
+		Sessions:  make([]librarypages.StartingPointSessionInfo, 0, config.MAX_STARTING_POINT_SESSIONS),
+	}
+	sessions, err := queries.ListPieceStartingPointSessions(ctx, db.ListPieceStartingPointSessionsParams{
+		UserID:  userID,
+		PieceID: pieceID,
+		Since:   now.AddDate(0, 0, -config.STARTING_POINT_HISTORY_DAYS).Unix(),
+	})
+	if err != nil {
+		return history, err
+	}
+	if len(sessions) == 0 {
+		return history, nil
+	}
+
+	history.Measures = numberedMeasures(measures, func(measure *librarypages.DrilledMeasure, n int) {
+		measure.Number = n
+	})
+	measures = len(history.Measures)
+	// sessions are newest first, so the first one to reach a measure is the last time it was drilled
+	for _, session := range sessions {
+		ranges, err := measurerange.ParseList(session.MeasuresPracticed)
+		if err != nil {
+			continue
+		}
+		for _, practiced := range ranges {
+			for n := practiced.Start; n <= min(practiced.End, measures); n++ {
+				if history.Measures[n-1].LastDrilled == 0 {
+					history.Measures[n-1].LastDrilled = session.Date
+				}
+			}
+		}
+		if len(history.Sessions) < config.MAX_STARTING_POINT_SESSIONS {
+			history.Sessions = append(history.Sessions, librarypages.StartingPointSessionInfo{
+				ID:                session.ID,
+				Date:              session.Date,
+				MeasuresPracticed: session.MeasuresPracticed,
+				Rating:            session.Rating.String,
+				TroubleMeasures:   session.TroubleMeasures,
+				CanMakeSpots:      session.TroubleMeasures != "" && !session.TroubleConverted,
+			})
+		}
+	}
+
+	recentSince := now.AddDate(0, 0, -config.STARTING_POINT_RECENT_DAYS).Unix()
+	for i := range history.Measures {
+		history.Measures[i].Recent = history.Measures[i].LastDrilled > recentSince
+	}
+	history.Recent = measureRuns(history.Measures, func(m librarypages.DrilledMeasure) bool { return m.Recent })
+	history.Neglected = measureRuns(history.Measures, func(m librarypages.DrilledMeasure) bool { return !m.Recent })
+	return history, nil
+}
@@ -0,0 +1,108 @@
+package server
+
+import (
+	"context"
+	"net/http"
+	"practicebetter/internal/activity"
+	"practicebetter/internal/ck"
+	"practicebetter/internal/config"
+	"practicebetter/internal/db"
+	"practicebetter/internal/pages/librarypages"
+	"practicebetter/internal/readiness"
+	"time"
+)
+
+// practiceCalendar groups the user's recorded practice time by day. Plans and spots that were
+// practiced count too, even without recorded time.
+func practiceCalendar(ctx context.Context, queries *db.Queries, userID string, loc *time.Location) (activity.Calendar, error) {
+	minutes, err := queries.ListUserPracticeMinutes(ctx, userID)
+	if err != nil {
+		return activity.Calendar{}, err
+	}
+	sessions := make([]activity.Session, 0, len(minutes))
+	for _, m := range minutes {
+		sessions = append(sessions, activity.Session{Date: m.Date, Minutes: m.DurationMinutes})
+	}
+	practiced, err := queries.ListUserPracticeTimes(ctx, userID)
+	if err != nil {
+		return activity.Calendar{}, err
+	}
+	return activity.New(loc, sessions, practiced), nil
+}
+
+func (s *Server) practiceStats(w http.ResponseWriter, r *http.Request) {
+	user := r.Context().Value(ck.UserKey).(db.User)
+	queries := db.New(s.DB)
+	loc := userLocation(user)
+	calendar, err := practiceCalendar(r.Context(), queries, user.ID, loc)
+	if err != nil {
+		s.DatabaseError(w, r, err, "Could not get practice history")
+		return
+	}
+
+	now := time.Now()
+	stats := librarypages.PracticeStats{
+		WeekMinutes: calendar.WeekMinutes(now),
+		Weeks:       make([][]activity.Day, 0, config.STATS_CALENDAR_WEEKS),
+		Timezone:    loc.String(),
+	}
+	stats.CurrentStreak, stats.LongestStreak = calendar.Streaks(now)
+	days := calendar.Days(calendar.WeekStart(now).AddDate(0, 0, -7*(config.STATS_CALENDAR_WEEKS-1)), now)
+	for i := 0; i < len(days); i += 7 {
+		stats.Weeks = append(stats.Weeks, days[i:min(i+7, len(days))])
+	}
+	// days before the first practice would pull the averages down
+	first := len(days)
+	for i, day := range days {
+		if day.Practiced {
+			first = min(first, i)
+			stats.PracticedDays++
+		}
+		stats.TotalMinutes += day.Minutes
+	}
+	stats.Weekdays = activity.WeekdayAverages(days[first:])
+
+	s.HxRender(w, r, librarypages.PracticeStatsPage(stats), "Practice Stats")
+}
+
+// pieceReadiness rebuilds the piece's daily stage counts from its spots' stage changes and projects
+// when they will all be completed
+func pieceReadiness(ctx context.Context, queries *db.Queries, userID string, pieceID string, loc *time.Location) (readiness.Forecast, error) {
+	spotRows, err := queries.ListPieceSpotStages(ctx, db.ListPieceSpotStagesParams{
+		UserID:  userID,
+		PieceID: pieceID,
+	})
+	if err != nil {
+		return readiness.Forecast{}, err
+	}
+	changeRows, err := queries.ListPieceStageChanges(ctx, db.ListPieceStageChangesParams{
+		UserID:  userID,
+		PieceID: pieceID,
+	})
+	if err != nil {
+		return readiness.Forecast{}, err
+	}
+
+	now := time.Now()
+	spots := make([]readiness.Spot, 0, len(spotRows))
+	for _, row := range spotRows {
+		spot := readiness.Spot{ID: row.ID, Stage: row.Stage, Since: now.Unix()}
+		if row.StageStarted.Valid {
+			spot.Since = row.StageStarted.Int64
+		}
+		spots = append(spots, spot)
+	}
+	changes := make([]readiness.Change, 0, len(changeRows))
+	for _, row := range changeRows {
+		changes = append(changes, readiness.Change{
+			SpotID: row.SpotID,
+			From:   row.FromStage.String,
+			To:     row.ToStage.String,
+			Date:   row.Date,
+		})
+	}
+
+	forecast := readiness.Project(readiness.Series(loc, spots, changes, now), config.READINESS_RATE_DAYS)
+	forecast.Days = forecast.Days[max(len(forecast.Days)-config.READINESS_CHART_DAYS, 0):]
+	return forecast, nil
+}
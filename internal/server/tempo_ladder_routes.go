@@ -0,0 +1,284 @@
+package server
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"log"
+	"net/http"
+	"practicebetter/internal/ck"
+	"practicebetter/internal/config"
+	"practicebetter/internal/db"
+	"practicebetter/internal/pages/librarypages"
+	"practicebetter/internal/tempo"
+	"strconv"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/gorilla/csrf"
+	"github.com/mavolin/go-htmx"
+	"github.com/nrednav/cuid2"
+)
+
+// recordSpotTempo adds the spot's current tempo to its tempo history if it has changed. The history is
+// extra information, so failures are only logged.
+func recordSpotTempo(ctx context.Context, queries *db.Queries, userID string, spotID string) {
+	if err := queries.CreateSpotTempoChange(ctx, db.CreateSpotTempoChangeParams{
+		ID:     cuid2.Generate(),
+		UserID: userID,
+		SpotID: spotID,
+	}); err != nil {
+		log.Default().Println(err)
+	}
+}
+
+// spotTempoChart gets the most recent part of a spot's tempo history for charting
+func spotTempoChart(ctx context.Context, queries *db.Queries, userID string, spot db.GetSpotRow) librarypages.TempoChart {
+	tempos, err := queries.ListSpotTempos(ctx, db.ListSpotTemposParams{
+		SpotID: spot.ID,
+		UserID: userID,
+	})
+	if err != nil {
+		log.Default().Println(err)
+	}
+	return tempoChart(tempos, spot.PieceGoalTempo.Int64)
+}
+
+// tempoChart charts the most recent part of a tempo history that's already been loaded
+func tempoChart(tempos []db.SpotTempo, goal int64) librarypages.TempoChart {
+	chart := librarypages.TempoChart{
+		Points: make([]librarypages.TempoPoint, 0),
+		Goal:   goal,
+	}
+	for _, t := range tempos[max(len(tempos)-config.MAX_TEMPO_CHART_POINTS, 0):] {
+		chart.Points = append(chart.Points, librarypages.TempoPoint{
+			Tempo:  t.Tempo,
+			Date:   t.Date,
+			Missed: t.Success.Valid && !t.Success.Bool,
+		})
+	}
+	return chart
+}
+
+// tempoLadderSettings reads the step size and fallback from the ladder form, or the defaults if they
+// aren't there, and returns a message for the user if they can't be used
+func tempoLadderSettings(r *http.Request, goal int64) (tempo.Ladder, string) {
+	ladder := tempo.Ladder{
+		Step:          config.TEMPO_LADDER_STEP,
+		FallbackSteps: config.TEMPO_LADDER_FALLBACK_STEPS,
+		Goal:          goal,
+	}
+	if value := r.FormValue("step"); value != "" {
+		step, err := strconv.ParseInt(value, 10, 64)
+		if err != nil || step < config.TEMPO_LADDER_MIN_STEP || step > config.TEMPO_LADDER_MAX_STEP {
+			return ladder, "The step size must be between " + strconv.Itoa(config.TEMPO_LADDER_MIN_STEP) + " and " + strconv.Itoa(config.TEMPO_LADDER_MAX_STEP)
+		}
+		ladder.Step = step
+	}
+	if value := r.FormValue("fallbackSteps"); value != "" {
+		fallback, err := strconv.ParseInt(value, 10, 64)
+		if err != nil || fallback < config.TEMPO_LADDER_MIN_FALLBACK_STEPS || fallback > config.TEMPO_LADDER_MAX_FALLBACK_STEPS {
+			return ladder, "Steps back must be between " + strconv.Itoa(config.TEMPO_LADDER_MIN_FALLBACK_STEPS) + " and " + strconv.Itoa(config.TEMPO_LADDER_MAX_FALLBACK_STEPS)
+		}
+		ladder.FallbackSteps = fallback
+	}
+	return ladder, ""
+}
+
+// tempoLadderInfo works out the next marking from the runs in the current ladder session. The first
+// run starts at the spot's current tempo.
+func tempoLadderInfo(ctx context.Context, queries *db.Queries, userID string, spot db.GetSpotRow, ladder tempo.Ladder) (librarypages.TempoLadderInfo, error) {
+	info := librarypages.TempoLadderInfo{
+		PieceID:       spot.PieceID,
+		SpotID:        spot.ID,
+		Step:          ladder.Step,
+		FallbackSteps: ladder.FallbackSteps,
+		Goal:          ladder.Goal,
+		Attempts:      make([]librarypages.TempoAttempt, 0),
+	}
+	tempos, err := queries.ListSpotTempos(ctx, db.ListSpotTemposParams{
+		SpotID: spot.ID,
+		UserID: userID,
+	})
+	if err != nil {
+		return info, err
+	}
+	info.Chart = tempoChart(tempos, spot.PieceGoalTempo.Int64)
+	since := time.Now().Add(-config.TEMPO_LADDER_SESSION).Unix()
+	attempts := make([]tempo.Attempt, 0)
+	for _, t := range tempos {
+		if t.Source != "ladder" || t.Date <= since {
+			continue
+		}
+		attempts = append(attempts, tempo.Attempt{Tempo: t.Tempo, Success: t.Success.Bool})
+		info.Attempts = append(info.Attempts, librarypages.TempoAttempt{Tempo: t.Tempo, Success: t.Success.Bool})
+	}
+
+	start := int64(config.TEMPO_LADDER_START)
+	if spot.CurrentTempo.Valid {
+		start = spot.CurrentTempo.Int64
+	} else if ladder.Goal > 0 {
+		start = min(start, ladder.Goal)
+	}
+	info.Suggested = ladder.Next(start, attempts)
+	info.Reached = ladder.Reached(tempo.Best(attempts))
+	return info, nil
+}
+
+func (s *Server) tempoLadder(w http.ResponseWriter, r *http.Request) {
+	pieceID := chi.URLParam(r, "pieceID")
+	spotID := chi.URLParam(r, "spotID")
+	user := r.Context().Value(ck.UserKey).(db.User)
+	queries := db.New(s.DB)
+
+	spot, err := queries.GetSpot(r.Context(), db.GetSpotParams{
+		SpotID:  spotID,
+		UserID:  user.ID,
+		PieceID: pieceID,
+	})
+	if err != nil {
+		s.DatabaseError(w, r, err, "Could not find matching spot")
+		return
+	}
+	ladder, message := tempoLadderSettings(r, spot.PieceGoalTempo.Int64)
+	if message != "" {
+		s.InvalidInputError(w, r, message)
+		return
+	}
+	info, err := tempoLadderInfo(r.Context(), queries, user.ID, spot, ladder)
+	if err != nil {
+		s.DatabaseError(w, r, err, "Could not get tempo history")
+		return
+	}
+	s.HxRender(w, r, librarypages.TempoLadderPage(spot, csrf.Token(r), info), "Tempo Ladder - "+spot.Name)
+}
+
+// tempoLadderResult saves one run up the ladder. Clean runs faster than the spot's current tempo
+// become its new current tempo.
+func (s *Server) tempoLadderResult(w http.ResponseWriter, r *http.Request) {
+	pieceID := chi.URLParam(r, "pieceID")
+	spotID := chi.URLParam(r, "spotID")
+	user := r.Context().Value(ck.UserKey).(db.User)
+	if err := r.ParseForm(); err != nil {
+		log.Default().Println(err)
+		s.InvalidInputError(w, r, "Invalid input")
+		return
+	}
+	played, err := strconv.ParseInt(r.FormValue("tempo"), 10, 64)
+	if err != nil || played < tempo.MinTempo || played > tempo.MaxTempo {
+		s.InvalidInputError(w, r, "The tempo must be between "+strconv.Itoa(tempo.MinTempo)+" and "+strconv.Itoa(tempo.MaxTempo))
+		return
+	}
+	result := r.FormValue("result")
+	if result != "clean" && result != "miss" {
+		s.InvalidInputError(w, r, "Invalid result")
+		return
+	}
+	success := result == "clean"
+
+	tx, err := s.DB.BeginTx(r.Context(), nil)
+	if err != nil {
+		s.DatabaseError(w, r, err, "Could not save tempo")
+		return
+	}
+	defer func() {
+		if err := tx.Rollback(); err != nil && !errors.Is(err, sql.ErrTxDone) {
+			log.Default().Println(err)
+		}
+	}()
+	qtx := db.New(s.DB).WithTx(tx)
+
+	spot, err := qtx.GetSpot(r.Context(), db.GetSpotParams{
+		SpotID:  spotID,
+		UserID:  user.ID,
+		PieceID: pieceID,
+	})
+	if err != nil {
+		s.DatabaseError(w, r, err, "Could not find matching spot")
+		return
+	}
+	ladder, message := tempoLadderSettings(r, spot.PieceGoalTempo.Int64)
+	if message != "" {
+		s.InvalidInputError(w, r, message)
+		return
+	}
+	if err := qtx.CreateSpotLadderTempo(r.Context(), db.CreateSpotLadderTempoParams{
+		ID:      cuid2.Generate(),
+		SpotID:  spot.ID,
+		UserID:  user.ID,
+		Tempo:   played,
+		Success: sql.NullBool{Bool: success, Valid: true},
+	}); err != nil {
+		s.DatabaseError(w, r, err, "Could not save tempo")
+		return
+	}
+	if success {
+		if err := qtx.RaiseSpotTempo(r.Context(), db.RaiseSpotTempoParams{
+			Tempo:   sql.NullInt64{Int64: played, Valid: true},
+			SpotID:  spot.ID,
+			UserID:  user.ID,
+			PieceID: pieceID,
+		}); err != nil {
+			s.DatabaseError(w, r, err, "Could not update spot tempo")
+			return
+		}
+		if !spot.CurrentTempo.Valid || played > spot.CurrentTempo.Int64 {
+			spot.CurrentTempo = sql.NullInt64{Int64: played, Valid: true}
+		}
+	}
+	info, err := tempoLadderInfo(r.Context(), qtx, user.ID, spot, ladder)
+	if err != nil {
+		s.DatabaseError(w, r, err, "Could not get tempo history")
+		return
+	}
+	if err := tx.Commit(); err != nil {
+		s.DatabaseError(w, r, err, "Could not save tempo")
+		return
+	}
+
+	if success && ladder.Reached(played) {
+		if err := htmx.Trigger(r, "ShowAlert", ShowAlertEvent{
+			Message:  "You played " + spot.Name + " cleanly at " + strconv.FormatInt(played, 10) + " bpm",
+			Title:    "Goal Tempo Reached!",
+			Variant:  "success",
+			Duration: 3000,
+		}); err != nil {
+			log.Default().Println(err)
+		}
+	}
+	w.Header().Set("Content-Type", "text/html")
+	if err := librarypages.TempoLadderPanel(csrf.Token(r), info).Render(r.Context(), w); err != nil {
+		log.Default().Println(err)
+		http.Error(w, "Render Error", http.StatusInternalServerError)
+	}
+}
+
+// pieceTempoProgress compares where each spot's tempo history started with its latest tempo
+func pieceTempoProgress(tempos []db.ListPieceSpotTemposRow, spots []librarypages.PiecePageSpot, goal int64) librarypages.PieceTempoProgress {
+	progress := librarypages.PieceTempoProgress{
+		Goal:  goal,
+		Spots: make([]librarypages.SpotTempoProgress, 0),
+	}
+	// tempos are grouped by spot, oldest first
+	first := make(map[string]int64, len(spots))
+	latest := make(map[string]int64, len(spots))
+	for _, t := range tempos {
+		if _, ok := first[t.SpotID]; !ok {
+			first[t.SpotID] = t.Tempo
+		}
+		latest[t.SpotID] = t.Tempo
+	}
+	for _, spot := range spots {
+		current, ok := latest[spot.ID]
+		if !ok {
+			continue
+		}
+		progress.Spots = append(progress.Spots, librarypages.SpotTempoProgress{
+			SpotID:  spot.ID,
+			Name:    spot.Name,
+			Start:   first[spot.ID],
+			Current: current,
+		})
+	}
+	return progress
+}
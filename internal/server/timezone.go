@@ -0,0 +1,59 @@
+package server
+
+import (
+	"context"
+	"log"
+	"net/http"
+	"practicebetter/internal/config"
+	"practicebetter/internal/db"
+	"time"
+	// the server may not have timezone data installed
+	_ "time/tzdata"
+)
+
+// timezoneCookie is set by the browser to the user's IANA timezone, like "America/New_York"
+const timezoneCookie = "timezone"
+
+// userLocation is the timezone from the user's settings, or the server's if they don't have one
+func userLocation(user db.User) *time.Location {
+	if user.ConfigTimezone == "" {
+		return time.Local
+	}
+	loc, err := time.LoadLocation(user.ConfigTimezone)
+	if err != nil {
+		return time.Local
+	}
+	return loc
+}
+
+// setBrowserTimezone saves the timezone the browser reports for a user who hasn't chosen one. It
+// only happens at login, so a timezone cleared in the settings stays cleared until the next login.
+func setBrowserTimezone(ctx context.Context, queries *db.Queries, r *http.Request, userID string) {
+	cookie, err := r.Cookie(timezoneCookie)
+	if err != nil || cookie.Value == "" {
+		return
+	}
+	if _, err := time.LoadLocation(cookie.Value); err != nil {
+		return
+	}
+	// only users without a timezone are updated
+	if err := queries.SetUserTimezone(ctx, db.SetUserTimezoneParams{
+		ConfigTimezone: cookie.Value,
+		UserID:         userID,
+	}); err != nil {
+		log.Default().Println(err)
+	}
+}
+
+func sameDay(a time.Time, b time.Time, loc *time.Location) bool {
+	ay, am, ad := a.In(loc).Date()
+	by, bm, bd := b.In(loc).Date()
+	return ay == by && am == bm && ad == bd
+}
+
+// canResumePlan is whether a plan from this time can still be practiced. Plans belong to the day they
+// were made in the user's timezone, with some extra time for practicing late at night.
+func canResumePlan(date int64, now time.Time, loc *time.Location) bool {
+	made := time.Unix(date, 0)
+	return sameDay(made, now, loc) || now.Sub(made) <= config.RESUME_PLAN_TIME_LIMIT
+}
@@ -0,0 +1,130 @@
+package stages
+
+import (
+	"practicebetter/internal/db"
+	"practicebetter/internal/scheduler"
+	"time"
+)
+
+// Engine decides which stage a spot should be in after it is practiced. Every decision returns the
+// stage the spot should end up in, which is the stage it started in if it shouldn't move.
+type Engine struct {
+	Rules db.StageRule
+	// Now is in the user's timezone, days in a stage are counted on their calendar
+	Now time.Time
+}
+
+func New(rules db.StageRule, now time.Time) Engine {
+	return Engine{Rules: rules, Now: now}
+}
+
+// CalendarDays is the number of calendar days from start to now in now's timezone, so a spot that
+// started its stage late last night has been in it for a day this morning
+func CalendarDays(start time.Time, now time.Time) int64 {
+	sy, sm, sd := start.In(now.Location()).Date()
+	ny, nm, nd := now.Date()
+	// midnight UTC on both dates so daylight saving time doesn't change the length of a day
+	return int64(time.Date(ny, nm, nd, 0, 0, 0, 0, time.UTC).Sub(time.Date(sy, sm, sd, 0, 0, 0, 0, time.UTC)) / (24 * time.Hour))
+}
+
+// daysInStage is the number of calendar days since the spot entered its stage, zero if that is unknown
+func (e Engine) daysInStage(stageStarted time.Time) int64 {
+	if stageStarted.IsZero() || stageStarted.After(e.Now) {
+		return 0
+	}
+	return CalendarDays(stageStarted, e.Now)
+}
+
+// Repeat handles a finished repeat practice session. Successful spots move to the stage the
+// practicer chose, as long as it is a promotion.
+func (e Engine) Repeat(from string, success bool, requested string) string {
+	if !success {
+		return from
+	}
+	switch {
+	case from == Repeat && (requested == ExtraRepeat || requested == Random):
+		return requested
+	case from == ExtraRepeat && requested == Random:
+		return Random
+	default:
+		return from
+	}
+}
+
+// RandomResult is how a spot went in a random spots session
+type RandomResult struct {
+	Excellent int64
+	Fine      int64
+	Poor      int64
+	// History is every random spots evaluation since the spot became a random spot, oldest first,
+	// ending with this session. When it's empty only this session's counts are used.
+	History []string
+	// the practicer can turn down a recommended promotion or demotion
+	Promote bool
+	Demote  bool
+}
+
+// excellentInARow counts the excellent evaluations at the end of the list
+func excellentInARow(evaluations []string) int64 {
+	var count int64
+	for i := len(evaluations) - 1; i >= 0 && evaluations[i] == "excellent"; i-- {
+		count++
+	}
+	return count
+}
+
+// RandomSpots handles a spot from a finished random spots session
+func (e Engine) RandomSpots(from string, result RandomResult, stageStarted time.Time) string {
+	if from != Random {
+		return from
+	}
+	days := e.daysInStage(stageStarted)
+	earned := result.Excellent >= e.Rules.RandomPromoteExcellent && result.Poor == 0 && result.Fine < 2
+	if len(result.History) > 0 {
+		earned = excellentInARow(result.History) >= e.Rules.RandomPromoteExcellent
+	}
+	if result.Promote && earned && days >= e.Rules.RandomPromoteMinDays {
+		return Interleave
+	}
+	if result.Demote &&
+		(result.Poor >= e.Rules.RandomDemotePoor ||
+			(result.Excellent == 0 && result.Fine > 0 && result.Poor > 0 && days >= e.Rules.RandomDemoteStaleDays)) {
+		return e.Rules.RandomDemoteTo
+	}
+	return from
+}
+
+// Interleave handles a spot from the interleave list of a finished practice plan. The evaluations
+// are everything since the spot became an interleave spot, oldest first, ending with this one.
+func (e Engine) Interleave(from string, evaluations []string, stageStarted time.Time) string {
+	if from != Interleave || len(evaluations) == 0 {
+		return from
+	}
+	days := e.daysInStage(stageStarted)
+	evaluation := evaluations[len(evaluations)-1]
+	if excellentInARow(evaluations) >= e.Rules.InterleavePromoteExcellent &&
+		!stageStarted.IsZero() &&
+		days >= e.Rules.InterleavePromoteMinDays {
+		return InterleaveDays
+	}
+	if evaluation == "poor" ||
+		(evaluation == "fine" && !stageStarted.IsZero() && days >= e.Rules.InterleaveStaleDays) {
+		return e.Rules.InterleaveDemoteTo
+	}
+	return from
+}
+
+// Infrequent handles an infrequent spot after its scheduler has reviewed it
+func (e Engine) Infrequent(from string, outcome scheduler.Outcome) string {
+	if from != InterleaveDays {
+		return from
+	}
+	switch outcome {
+	case scheduler.Demote:
+		return e.Rules.InfrequentDemoteTo
+	case scheduler.Complete:
+		return Completed
+	default:
+		return from
+	}
+}
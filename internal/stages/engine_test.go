@@ -0,0 +1,322 @@
+package stages
+
+import (
+	"practicebetter/internal/db"
+	"practicebetter/internal/scheduler"
+	"testing"
+	"time"
+)
+
+var now = time.Date(2024, time.August, 14, 12, 0, 0, 0, time.UTC)
+
+func daysAgo(days int) time.Time {
+	return now.AddDate(0, 0, -days)
+}
+
+// customRules are stricter than the defaults and demote to different stages
+func customRules() db.StageRule {
+	rules := DefaultRules("u1")
+	rules.RandomPromoteExcellent = 5
+	rules.RandomPromoteMinDays = 10
+	rules.RandomDemotePoor = 2
+	rules.RandomDemoteStaleDays = 3
+	rules.RandomDemoteTo = Repeat
+	rules.InterleavePromoteExcellent = 3
+	rules.InterleavePromoteMinDays = 14
+	rules.InterleaveStaleDays = 20
+	rules.InterleaveDemoteTo = ExtraRepeat
+	rules.InfrequentDemoteTo = Random
+	return rules
+}
+
+func TestRepeat(t *testing.T) {
+	tests := []struct {
+		name      string
+		from      string
+		success   bool
+		requested string
+		want      string
+	}{
+		{"promote to extra repeat", Repeat, true, ExtraRepeat, ExtraRepeat},
+		{"promote to random", Repeat, true, Random, Random},
+		{"extra repeat to random", ExtraRepeat, true, Random, Random},
+		{"stay after failing", Repeat, false, Random, Repeat},
+		{"extra repeat stays after failing", ExtraRepeat, false, Random, ExtraRepeat},
+		{"stay when nothing is requested", Repeat, true, "", Repeat},
+		{"can't go back to repeat", ExtraRepeat, true, Repeat, ExtraRepeat},
+		{"can't skip past random", Repeat, true, Interleave, Repeat},
+		{"only repeat stages move", Random, true, Random, Random},
+	}
+	for _, rules := range []db.StageRule{DefaultRules("u1"), customRules()} {
+		engine := New(rules, now)
+		for _, tt := range tests {
+			if got := engine.Repeat(tt.from, tt.success, tt.requested); got != tt.want {
+				t.Errorf("%s: Repeat(%q, %v, %q) = %q, want %q", tt.name, tt.from, tt.success, tt.requested, got, tt.want)
+			}
+		}
+	}
+}
+
+func TestRandomSpots(t *testing.T) {
+	tests := []struct {
+		name   string
+		rules  db.StageRule
+		from   string
+		result RandomResult
+		days   int
+		want   string
+	}{
+		{
+			name:   "promote with enough excellent ratings",
+			rules:  DefaultRules("u1"),
+			from:   Random,
+			result: RandomResult{Excellent: 3, Promote: true},
+			days:   6,
+			want:   Interleave,
+		},
+		{
+			name:   "promote from an excellent streak",
+			rules:  DefaultRules("u1"),
+			from:   Random,
+			result: RandomResult{Excellent: 1, History: []string{"poor", "excellent", "excellent", "excellent"}, Promote: true},
+			days:   7,
+			want:   Interleave,
+		},
+		{
+			name:   "stay when the streak is broken",
+			rules:  DefaultRules("u1"),
+			from:   Random,
+			result: RandomResult{Excellent: 3, History: []string{"excellent", "excellent", "fine", "excellent"}, Promote: true},
+			days:   7,
+			want:   Random,
+		},
+		{
+			name:   "stay when the promotion is turned down",
+			rules:  DefaultRules("u1"),
+			from:   Random,
+			result: RandomResult{Excellent: 3},
+			days:   6,
+			want:   Random,
+		},
+		{
+			name:   "stay when promoted too soon",
+			rules:  DefaultRules("u1"),
+			from:   Random,
+			result: RandomResult{Excellent: 3, Promote: true},
+			days:   5,
+			want:   Random,
+		},
+		{
+			name:   "stay with a poor rating",
+			rules:  DefaultRules("u1"),
+			from:   Random,
+			result: RandomResult{Excellent: 3, Poor: 1, Promote: true},
+			days:   6,
+			want:   Random,
+		},
+		{
+			name:   "stay with too many fine ratings",
+			rules:  DefaultRules("u1"),
+			from:   Random,
+			result: RandomResult{Excellent: 3, Fine: 2, Promote: true},
+			days:   6,
+			want:   Random,
+		},
+		{
+			name:   "demote with too many poor ratings",
+			rules:  DefaultRules("u1"),
+			from:   Random,
+			result: RandomResult{Poor: 3, Demote: true},
+			days:   1,
+			want:   ExtraRepeat,
+		},
+		{
+			name:   "stay when the demotion is turned down",
+			rules:  DefaultRules("u1"),
+			from:   Random,
+			result: RandomResult{Poor: 3},
+			days:   1,
+			want:   Random,
+		},
+		{
+			name:   "demote when stuck",
+			rules:  DefaultRules("u1"),
+			from:   Random,
+			result: RandomResult{Fine: 2, Poor: 1, Demote: true},
+			days:   7,
+			want:   ExtraRepeat,
+		},
+		{
+			name:   "stay when struggling but not stuck for long",
+			rules:  DefaultRules("u1"),
+			from:   Random,
+			result: RandomResult{Fine: 2, Poor: 1, Demote: true},
+			days:   6,
+			want:   Random,
+		},
+		{
+			name:   "stay with fine practicing",
+			rules:  DefaultRules("u1"),
+			from:   Random,
+			result: RandomResult{Excellent: 1, Fine: 2, Promote: true, Demote: true},
+			days:   20,
+			want:   Random,
+		},
+		{
+			name:   "only random spots move",
+			rules:  DefaultRules("u1"),
+			from:   Interleave,
+			result: RandomResult{Excellent: 3, Promote: true},
+			days:   6,
+			want:   Interleave,
+		},
+		{
+			name:   "custom rules need more excellent ratings",
+			rules:  customRules(),
+			from:   Random,
+			result: RandomResult{Excellent: 4, Promote: true},
+			days:   10,
+			want:   Random,
+		},
+		{
+			name:   "custom rules promote",
+			rules:  customRules(),
+			from:   Random,
+			result: RandomResult{Excellent: 5, Promote: true},
+			days:   10,
+			want:   Interleave,
+		},
+		{
+			name:   "custom rules need more days",
+			rules:  customRules(),
+			from:   Random,
+			result: RandomResult{Excellent: 5, Promote: true},
+			days:   9,
+			want:   Random,
+		},
+		{
+			name:   "custom rules demote sooner and further",
+			rules:  customRules(),
+			from:   Random,
+			result: RandomResult{Poor: 2, Demote: true},
+			days:   1,
+			want:   Repeat,
+		},
+		{
+			name:   "custom rules demote when stuck sooner",
+			rules:  customRules(),
+			from:   Random,
+			result: RandomResult{Fine: 1, Poor: 1, Demote: true},
+			days:   3,
+			want:   Repeat,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := New(tt.rules, now).RandomSpots(tt.from, tt.result, daysAgo(tt.days))
+			if got != tt.want {
+				t.Errorf("RandomSpots = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestInterleave(t *testing.T) {
+	tests := []struct {
+		name        string
+		rules       db.StageRule
+		from        string
+		evaluations []string
+		// negative when the stage start is unknown
+		days int
+		want string
+	}{
+		{"promote after an excellent rating", DefaultRules("u1"), Interleave, []string{"excellent"}, 5, InterleaveDays},
+		{"stay when promoted too soon", DefaultRules("u1"), Interleave, []string{"excellent"}, 4, Interleave},
+		{"stay when the stage start is unknown", DefaultRules("u1"), Interleave, []string{"excellent"}, -1, Interleave},
+		{"demote after a poor rating", DefaultRules("u1"), Interleave, []string{"excellent", "poor"}, 2, Random},
+		{"demote when stuck at fine", DefaultRules("u1"), Interleave, []string{"fine"}, 12, Random},
+		{"stay at fine for a while", DefaultRules("u1"), Interleave, []string{"fine"}, 11, Interleave},
+		{"fine isn't stuck when the start is unknown", DefaultRules("u1"), Interleave, []string{"fine"}, -1, Interleave},
+		{"stay without evaluations", DefaultRules("u1"), Interleave, nil, 20, Interleave},
+		{"only interleave spots move", DefaultRules("u1"), Random, []string{"excellent"}, 20, Random},
+		{"custom rules need excellent in a row", customRules(), Interleave, []string{"excellent", "fine", "excellent", "excellent"}, 14, Interleave},
+		{"custom rules promote", customRules(), Interleave, []string{"fine", "excellent", "excellent", "excellent"}, 14, InterleaveDays},
+		{"custom rules need more days", customRules(), Interleave, []string{"excellent", "excellent", "excellent"}, 13, Interleave},
+		{"custom rules demote further", customRules(), Interleave, []string{"poor"}, 1, ExtraRepeat},
+		{"custom rules wait longer at fine", customRules(), Interleave, []string{"fine"}, 19, Interleave},
+		{"custom rules demote when stuck", customRules(), Interleave, []string{"fine"}, 20, ExtraRepeat},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var started time.Time
+			if tt.days >= 0 {
+				started = daysAgo(tt.days)
+			}
+			got := New(tt.rules, now).Interleave(tt.from, tt.evaluations, started)
+			if got != tt.want {
+				t.Errorf("Interleave = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestInfrequent(t *testing.T) {
+	tests := []struct {
+		name    string
+		rules   db.StageRule
+		from    string
+		outcome scheduler.Outcome
+		want    string
+	}{
+		{"stay", DefaultRules("u1"), InterleaveDays, scheduler.Keep, InterleaveDays},
+		{"complete", DefaultRules("u1"), InterleaveDays, scheduler.Complete, Completed},
+		{"demote", DefaultRules("u1"), InterleaveDays, scheduler.Demote, Interleave},
+		{"custom rules demote further", customRules(), InterleaveDays, scheduler.Demote, Random},
+		{"custom rules complete", customRules(), InterleaveDays, scheduler.Complete, Completed},
+		{"only infrequent spots move", DefaultRules("u1"), Interleave, scheduler.Complete, Interleave},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := New(tt.rules, now).Infrequent(tt.from, tt.outcome); got != tt.want {
+				t.Errorf("Infrequent = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCalendarDays(t *testing.T) {
+	newYork, err := time.LoadLocation("America/New_York")
+	if err != nil {
+		t.Skip("no timezone data")
+	}
+	tests := []struct {
+		name  string
+		start time.Time
+		now   time.Time
+		want  int64
+	}{
+		{"same day", time.Date(2024, time.August, 14, 1, 0, 0, 0, newYork), time.Date(2024, time.August, 14, 23, 0, 0, 0, newYork), 0},
+		{"late last night", time.Date(2024, time.August, 13, 23, 30, 0, 0, newYork), time.Date(2024, time.August, 14, 8, 0, 0, 0, newYork), 1},
+		{"a week of calendar days", time.Date(2024, time.August, 7, 22, 0, 0, 0, newYork), time.Date(2024, time.August, 14, 7, 0, 0, 0, newYork), 7},
+		// 02:00 UTC is still the day before in New York
+		{"counted in now's timezone", time.Date(2024, time.August, 14, 2, 0, 0, 0, time.UTC), time.Date(2024, time.August, 14, 20, 0, 0, 0, newYork), 1},
+		{"across daylight saving time", time.Date(2024, time.March, 9, 12, 0, 0, 0, newYork), time.Date(2024, time.March, 11, 0, 30, 0, 0, newYork), 2},
+	}
+	for _, tt := range tests {
+		if got := CalendarDays(tt.start, tt.now); got != tt.want {
+			t.Errorf("%s: CalendarDays = %d, want %d", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestDaysInStageUsesTheCalendar(t *testing.T) {
+	// promoted late at night, practiced early the next morning after the minimum days
+	loc := time.FixedZone("test", -5*60*60)
+	promoted := time.Date(2024, time.August, 8, 23, 0, 0, 0, loc)
+	now := time.Date(2024, time.August, 14, 7, 0, 0, 0, loc)
+	result := RandomResult{Excellent: 3, Promote: true}
+	if got := New(DefaultRules("u1"), now).RandomSpots(Random, result, promoted); got != Interleave {
+		t.Errorf("RandomSpots = %q, want %q", got, Interleave)
+	}
+}
@@ -0,0 +1,98 @@
+package stages
+
+import (
+	"practicebetter/internal/config"
+	"practicebetter/internal/db"
+	"slices"
+	"strconv"
+)
+
+const (
+	Repeat         = "repeat"
+	ExtraRepeat    = "extra_repeat"
+	Random         = "random"
+	Interleave     = "interleave"
+	InterleaveDays = "interleave_days"
+	Completed      = "completed"
+)
+
+// All is every stage a spot can be in, matching the check on spots.stage
+var All = []string{Repeat, ExtraRepeat, Random, Interleave, InterleaveDays, Completed}
+
+// where each kind of practicing can send a demoted spot, in order from the earliest stage
+var (
+	RandomDemoteTargets     = []string{Repeat, ExtraRepeat}
+	InterleaveDemoteTargets = []string{Repeat, ExtraRepeat, Random}
+	InfrequentDemoteTargets = []string{Repeat, ExtraRepeat, Random, Interleave}
+)
+
+// DefaultRules are the rules for users that have never changed them, they match the table defaults
+func DefaultRules(userID string) db.StageRule {
+	return db.StageRule{
+		UserID:                     userID,
+		RandomPromoteExcellent:     3,
+		RandomPromoteMinDays:       6,
+		RandomDemotePoor:           3,
+		RandomDemoteStaleDays:      7,
+		RandomDemoteTo:             ExtraRepeat,
+		InterleavePromoteExcellent: 1,
+		InterleavePromoteMinDays:   config.INTERLEAVE_SPOT_MIN_DAYS,
+		InterleaveStaleDays:        config.INTERLEAVE_SPOT_MAX_DAYS,
+		InterleaveDemoteTo:         Random,
+		InfrequentDemoteTo:         Interleave,
+	}
+}
+
+// Validate returns a message for the user if the rules can't be saved, or an empty string if they can
+func Validate(rules db.StageRule) string {
+	counts := []struct {
+		label string
+		value int64
+		min   int64
+		max   int64
+	}{
+		{"excellent ratings to promote a random spot", rules.RandomPromoteExcellent, 1, 20},
+		{"days before a random spot can be promoted", rules.RandomPromoteMinDays, 0, 60},
+		{"poor ratings to demote a random spot", rules.RandomDemotePoor, 1, 20},
+		{"days before a struggling random spot is demoted", rules.RandomDemoteStaleDays, 0, 60},
+		{"excellent ratings in a row to promote an interleave spot", rules.InterleavePromoteExcellent, 1, 10},
+		{"days before an interleave spot can be promoted", rules.InterleavePromoteMinDays, 0, 60},
+		{"days before a fine interleave spot is demoted", rules.InterleaveStaleDays, 0, 60},
+	}
+	for _, count := range counts {
+		if count.value < count.min || count.value > count.max {
+			return "The number of " + count.label + " must be between " +
+				strconv.FormatInt(count.min, 10) + " and " + strconv.FormatInt(count.max, 10)
+		}
+	}
+	if !slices.Contains(RandomDemoteTargets, rules.RandomDemoteTo) {
+		return "Invalid stage for demoted random spots"
+	}
+	if !slices.Contains(InterleaveDemoteTargets, rules.InterleaveDemoteTo) {
+		return "Invalid stage for demoted interleave spots"
+	}
+	if !slices.Contains(InfrequentDemoteTargets, rules.InfrequentDemoteTo) {
+		return "Invalid stage for demoted infrequent spots"
+	}
+	return ""
+}
+
+// Label is the name of a stage to show to the user
+func Label(stage string) string {
+	switch stage {
+	case Repeat:
+		return "Repeat Practice"
+	case ExtraRepeat:
+		return "Extra Repeat Practice"
+	case Random:
+		return "Random Practice"
+	case Interleave:
+		return "Interleaved Practice"
+	case InterleaveDays:
+		return "Infrequent"
+	case Completed:
+		return "Completed"
+	default:
+		return "Unknown"
+	}
+}
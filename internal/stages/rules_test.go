@@ -0,0 +1,46 @@
+package stages
+
+import (
+	"practicebetter/internal/db"
+	"testing"
+)
+
+func TestValidate(t *testing.T) {
+	tests := []struct {
+		name  string
+		edit  func(rules *db.StageRule)
+		valid bool
+	}{
+		{"defaults", func(rules *db.StageRule) {}, true},
+		{"custom", func(rules *db.StageRule) { *rules = customRules() }, true},
+		{"no excellent ratings to promote", func(rules *db.StageRule) { rules.RandomPromoteExcellent = 0 }, false},
+		{"too many excellent ratings to promote", func(rules *db.StageRule) { rules.RandomPromoteExcellent = 21 }, false},
+		{"negative days to promote", func(rules *db.StageRule) { rules.RandomPromoteMinDays = -1 }, false},
+		{"too many days to promote", func(rules *db.StageRule) { rules.RandomPromoteMinDays = 61 }, false},
+		{"no poor ratings to demote", func(rules *db.StageRule) { rules.RandomDemotePoor = 0 }, false},
+		{"negative stale days", func(rules *db.StageRule) { rules.RandomDemoteStaleDays = -1 }, false},
+		{"too many interleave excellent ratings", func(rules *db.StageRule) { rules.InterleavePromoteExcellent = 11 }, false},
+		{"negative interleave days", func(rules *db.StageRule) { rules.InterleavePromoteMinDays = -1 }, false},
+		{"too many interleave stale days", func(rules *db.StageRule) { rules.InterleaveStaleDays = 61 }, false},
+		{"no days is fine", func(rules *db.StageRule) { rules.RandomPromoteMinDays = 0; rules.InterleaveStaleDays = 0 }, true},
+		{"random demoted to random", func(rules *db.StageRule) { rules.RandomDemoteTo = Random }, false},
+		{"random demoted to a made up stage", func(rules *db.StageRule) { rules.RandomDemoteTo = "practice" }, false},
+		{"interleave demoted to interleave", func(rules *db.StageRule) { rules.InterleaveDemoteTo = Interleave }, false},
+		{"interleave demoted to repeat", func(rules *db.StageRule) { rules.InterleaveDemoteTo = Repeat }, true},
+		{"infrequent demoted to completed", func(rules *db.StageRule) { rules.InfrequentDemoteTo = Completed }, false},
+		{"infrequent demoted to nothing", func(rules *db.StageRule) { rules.InfrequentDemoteTo = "" }, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rules := DefaultRules("u1")
+			tt.edit(&rules)
+			message := Validate(rules)
+			if tt.valid && message != "" {
+				t.Errorf("Validate rejected valid rules: %s", message)
+			}
+			if !tt.valid && message == "" {
+				t.Error("Validate accepted invalid rules")
+			}
+		})
+	}
+}
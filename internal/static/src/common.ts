@@ -14,11 +14,42 @@ export type PracticeSummaryItem = {
   excellent: number;
   fine: number;
   poor: number;
+  // every evaluation in the order they were given
+  evaluations: SpotEvaluation[];
   day: number;
 };
 
+export type SpotEvaluation = "excellent" | "fine" | "poor";
+
+// excellentInARow counts the excellent evaluations at the end of the list
+export function excellentInARow(evaluations: SpotEvaluation[]) {
+  let count = 0;
+  for (let i = evaluations.length - 1; i >= 0; i--) {
+    if (evaluations[i] !== "excellent") {
+      break;
+    }
+    count++;
+  }
+  return count;
+}
+
 export type RandomMode = "setup" | "practice" | "summary";
 
+// the parts of the user's stage rules that apply to random practicing
+export type RandomSpotRules = {
+  randomPromoteExcellent: number;
+  randomPromoteMinDays: number;
+  randomDemotePoor: number;
+  randomDemoteStaleDays: number;
+};
+
+export const defaultRandomSpotRules: RandomSpotRules = {
+  randomPromoteExcellent: 3,
+  randomPromoteMinDays: 6,
+  randomDemotePoor: 3,
+  randomDemoteStaleDays: 7,
+};
+
 export function uniqueID() {
   return `${Math.floor(Math.random() * Math.random() * Date.now())}`;
 }
@@ -314,6 +314,9 @@ function handleConfirm(e: HTMXConfirmEvent) {
 
 globalThis.addEventListener("htmx:confirm", handleConfirm);
 
+// the server uses this to decide which day practice happened on
+document.cookie = `timezone=${Intl.DateTimeFormat().resolvedOptions().timeZone}; path=/; max-age=31536000; samesite=lax`;
+
 function closeAndScroll(event: HTMXRequestEvent) {
   if (!event.detail?.target || !(event.detail.target instanceof HTMLElement)) {
     return;
@@ -12,7 +12,14 @@ try {
   register(
     RandomSpots,
     "random-spots",
-    ["initialspots", "pieceid", "csrf", "initialsessions", "planid"],
+    [
+      "initialspots",
+      "pieceid",
+      "csrf",
+      "initialsessions",
+      "planid",
+      "rules",
+    ],
     {
       shadow: false,
     },
@@ -36,7 +43,15 @@ try {
   register(
     StartingPoint,
     "starting-point",
-    ["initialmeasures", "initialbeats", "preconfigured", "pieceid", "csrf"],
+    [
+      "initialmeasures",
+      "initialbeats",
+      "preconfigured",
+      "pieceid",
+      "csrf",
+      "initiallowerbound",
+      "initialupperbound",
+    ],
     { shadow: false },
   );
 } catch (err) {
@@ -134,6 +149,18 @@ function handleFinishedStartingPointPracticing(
             },
           }),
         );
+        res
+          .json()
+          .then((data: { sessionId?: string }) => {
+            if (data.sessionId) {
+              globalThis.dispatchEvent(
+                new CustomEvent("SavedStartingPointSession", {
+                  detail: { sessionId: data.sessionId, pieceid },
+                }),
+              );
+            }
+          })
+          .catch(console.error);
       } else {
         res.text().then(console.error).catch(console.error);
         globalThis.dispatchEvent(
@@ -169,7 +196,16 @@ globalThis.addEventListener(
 );
 
 function handleFinishedRepeatPracticing(evt: FinishedRepeatPracticingEvent) {
-  const { durationMinutes, csrf, endpoint, success, toStage } = evt.detail;
+  const {
+    durationMinutes,
+    csrf,
+    endpoint,
+    success,
+    toStage,
+    attempts,
+    successes,
+    resets,
+  } = evt.detail;
   if (!durationMinutes || !csrf || !endpoint) {
     console.error("event missing data");
     return;
@@ -180,7 +216,14 @@ function handleFinishedRepeatPracticing(evt: FinishedRepeatPracticingEvent) {
       "X-CSRF-Token": csrf,
       "Content-Type": "application/json",
     },
-    body: JSON.stringify({ durationMinutes, success, toStage }),
+    body: JSON.stringify({
+      durationMinutes,
+      success,
+      toStage,
+      attempts,
+      successes,
+      resets,
+    }),
   })
     .then((res) => {
       if (res.ok) {
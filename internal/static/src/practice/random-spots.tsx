@@ -6,7 +6,13 @@ import {
   useRef,
   useState,
 } from "preact/hooks";
-import { type PracticeSummaryItem, type RandomMode } from "../common";
+import {
+  defaultRandomSpotRules,
+  type PracticeSummaryItem,
+  type RandomMode,
+  type RandomSpotRules,
+  type SpotEvaluation,
+} from "../common";
 import { type BasicSpot } from "../validators";
 import { ScaleCrossFadeContent } from "../ui/transitions";
 import { CreateSpots } from "./create-spots";
@@ -21,12 +27,14 @@ export function RandomSpots({
   csrf,
   planid,
   piecetitle,
+  rules,
 }: {
   initialspots?: string;
   pieceid?: string;
   csrf?: string;
   planid?: string;
   piecetitle?: string;
+  rules?: string;
 }) {
   const [spots, setSpots] = useState<BasicSpot[]>([]);
   const [summary, setSummary] = useState<PracticeSummaryItem[]>([]);
@@ -51,6 +59,19 @@ export function RandomSpots({
     return spots;
   }, [initialspots]);
 
+  const stageRules = useMemo(() => {
+    if (!rules) return defaultRandomSpotRules;
+    try {
+      return {
+        ...defaultRandomSpotRules,
+        ...(JSON.parse(rules) as Partial<RandomSpotRules>),
+      };
+    } catch (err) {
+      console.error(err);
+      return defaultRandomSpotRules;
+    }
+  }, [rules]);
+
   const updateSpot = useCallback(
     (updatedSpot: BasicSpot) => {
       setSpots((spots) => {
@@ -155,6 +176,7 @@ export function RandomSpots({
                 planid={planid}
                 csrf={csrf}
                 startTime={startTime}
+                rules={stageRules}
               />
             ),
           }[mode]
@@ -404,6 +426,14 @@ function hash(s: string) {
   return (h ^ (h >>> 16)) >>> 0;
 }
 
+type SpotPracticeResults = {
+  excellent: number;
+  fine: number;
+  poor: number;
+  // missing from sessions saved before evaluations were kept
+  evaluations?: SpotEvaluation[];
+};
+
 type SpotNeglectInfo = {
   reps: number;
   id: string;
@@ -460,7 +490,7 @@ export function SinglePractice({
   const [neglectInfo, setNeglectInfo] = useState<SpotNeglectInfo[]>([]);
   const [eligibleSpots, setEligibleSpots] = useState<BasicSpot[]>(spots);
   const [practiceSummary, setPracticeSummary] = useState<
-    Map<string, { excellent: number; fine: number; poor: number }>
+    Map<string, SpotPracticeResults>
   >(new Map());
   // This counter ensures that the animation runs, even if the same spot is generated twice in a row.
   const [counter, setCounter] = useState(0);
@@ -540,6 +570,7 @@ export function SinglePractice({
         excellent: 0,
         fine: 0,
         poor: 0,
+        evaluations: [],
       };
       let day = 0;
       if (spot.stageStarted) {
@@ -553,6 +584,8 @@ export function SinglePractice({
         excellent: results.excellent,
         fine: results.fine,
         poor: results.poor,
+        // sessions saved before evaluations were kept only have the counts
+        evaluations: results.evaluations ?? [],
         id: spot.id ?? "Missing spot id",
         day,
       });
@@ -635,7 +668,7 @@ export function SinglePractice({
         new Map(
           JSON.parse(summary) as [
             string,
-            { excellent: number; fine: number; poor: number },
+            SpotPracticeResults,
           ][],
         ),
       );
@@ -726,7 +759,7 @@ export function SinglePractice({
   }, [currentSpot, eligibleSpots, neglectInfo.length, spots]);
 
   const addSpotRep = useCallback(
-    (id: string | undefined, quality: "excellent" | "fine" | "poor") => {
+    (id: string | undefined, quality: SpotEvaluation) => {
       const currentSpotIdx = neglectInfo.findIndex((spot) => spot.id === id);
       if (currentSpotIdx > -1) {
         const nextNeglectInfo = [...neglectInfo];
@@ -740,8 +773,10 @@ export function SinglePractice({
         excellent: 0,
         fine: 0,
         poor: 0,
+        evaluations: [],
       };
       summary[quality] += 1;
+      summary.evaluations = [...(summary.evaluations ?? []), quality];
       practiceSummary.set(id, summary);
       if (pieceid) {
         saveToStorage(
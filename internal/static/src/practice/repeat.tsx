@@ -17,6 +17,13 @@ import * as Switch from "@radix-ui/react-switch";
 
 type RepeatMode = "prepare" | "practice" | "break_success" | "break_fail";
 
+// how the practicing went, saved with the session
+type RepeatCounts = {
+  attempts: number;
+  successes: number;
+  resets: number;
+};
+
 // TODO: add event listener to update spot
 export function Repeat({
   initialspot,
@@ -37,6 +44,11 @@ export function Repeat({
   const [startTime, setStartTime] = useState<number>(0);
   const [spot, setSpot] = useState<BasicSpot | null>(null);
   const [kidMode, setKidMode] = useState<boolean>(kidmode);
+  const countsRef = useRef<RepeatCounts>({
+    attempts: 0,
+    successes: 0,
+    resets: 0,
+  });
 
   useEffect(() => {
     if (initialspot) {
@@ -50,9 +62,20 @@ export function Repeat({
 
   const startPracticing = useCallback(() => {
     setStartTime(Date.now());
+    countsRef.current = { attempts: 0, successes: 0, resets: 0 };
     setMode("practice");
   }, [setMode, setStartTime]);
 
+  const recordAttempt = useCallback((correct: boolean, reset: boolean) => {
+    countsRef.current.attempts++;
+    if (correct) {
+      countsRef.current.successes++;
+    }
+    if (reset) {
+      countsRef.current.resets++;
+    }
+  }, []);
+
   const setModePrepare = useCallback(() => {
     setMode("prepare");
   }, [setMode]);
@@ -78,6 +101,7 @@ export function Repeat({
             csrf,
             toStage: "",
             endpoint: `/library/pieces/${pieceid}/spots/${spot.id}/practice/repeat`,
+            ...countsRef.current,
           },
         }),
       );
@@ -106,6 +130,7 @@ export function Repeat({
               csrf,
               toStage,
               endpoint: `/library/pieces/${pieceid}/spots/${spot.id}/practice/repeat`,
+              ...countsRef.current,
             },
           }),
         );
@@ -127,6 +152,7 @@ export function Repeat({
             csrf,
             toStage: "",
             endpoint: `/library/pieces/${pieceid}/spots/${spot.id}/practice/repeat`,
+            ...countsRef.current,
           },
         }),
       );
@@ -160,6 +186,7 @@ export function Repeat({
                 startTime={startTime}
                 onSuccess={setModeBreakSuccess}
                 onFail={setModeBreakFail}
+                recordAttempt={recordAttempt}
                 spot={spot}
                 pieceid={pieceid}
                 piecetitle={piecetitle}
@@ -356,6 +383,7 @@ function RepeatPracticeTitle(props: { stage?: string | null }) {
 function RepeatPractice({
   onSuccess,
   onFail,
+  recordAttempt,
   startTime,
   spot,
   pieceid,
@@ -366,6 +394,7 @@ function RepeatPractice({
 }: {
   onSuccess: () => void;
   onFail: () => void;
+  recordAttempt: (correct: boolean, reset: boolean) => void;
   startTime: number;
   spot?: BasicSpot | null;
   pieceid?: string;
@@ -385,6 +414,7 @@ function RepeatPractice({
   }, []);
 
   const succeed = useCallback(() => {
+    recordAttempt(true, false);
     if (numCompleted === 4) {
       setCompleted((curr) => curr + 1);
       setTimeout(onSuccess, 300);
@@ -395,9 +425,10 @@ function RepeatPractice({
         setWaitedLongEnough(true);
       }, 1000);
     }
-  }, [numCompleted, onSuccess, setWaitedLongEnough]);
+  }, [numCompleted, onSuccess, setWaitedLongEnough, recordAttempt]);
 
   const fail = useCallback(() => {
+    recordAttempt(false, numCompleted > 0);
     setCompleted(0);
     setWaitedLongEnough(false);
     setTimeout(() => {
@@ -407,7 +438,7 @@ function RepeatPractice({
     if (Date.now() - startTime > 5 * 60 * 1000 + 30 * 1000) {
       onFail();
     }
-  }, [setCompleted, onFail, startTime]);
+  }, [setCompleted, onFail, startTime, numCompleted, recordAttempt]);
 
   return (
     <>
@@ -4,6 +4,7 @@ import { uniqueID } from "../common";
 import dayjs from "dayjs";
 import { BreakDialog, ResumeDialog } from "./practice-dialogs";
 import { SummaryActions } from "./summary";
+import { type SavedStartingPointSessionEvent } from "../types";
 
 type Section = {
   startingPoint: {
@@ -55,19 +56,27 @@ function calculateMeasuresPracticed(summary: Section[]) {
 }
 
 // TODO: add option for time signature changes
-// TODO: add option to focus on smaller section
+function parseBound(bound: string): number | null {
+  const boundInt = parseInt(bound, 10);
+  return isNaN(boundInt) ? null : boundInt;
+}
+
 export function StartingPoint({
   initialmeasures = "100",
   initialbeats = "4",
   pieceid = "",
   csrf = "",
   planid = "",
+  initiallowerbound = "",
+  initialupperbound = "",
 }: {
   initialmeasures?: string;
   initialbeats?: string;
   pieceid?: string;
   csrf?: string;
   planid?: string;
+  initiallowerbound?: string;
+  initialupperbound?: string;
 }) {
   const [measures, setMeasures] = useState<number>(
     parseInt(initialmeasures, 10),
@@ -82,8 +91,12 @@ export function StartingPoint({
   const [startTime, setStartTime] = useState<Date | null>(null);
   const [numSessions, setNumSessions] = useState(2);
 
-  const [lowerBound, setLowerBound] = useState<number | null>(null);
-  const [upperBound, setUpperBound] = useState<number | null>(null);
+  const [lowerBound, setLowerBound] = useState<number | null>(
+    parseBound(initiallowerbound),
+  );
+  const [upperBound, setUpperBound] = useState<number | null>(
+    parseBound(initialupperbound),
+  );
 
   const setModePractice = useCallback(() => {
     setSummary([]);
@@ -238,6 +251,7 @@ export function StartingPoint({
                 practice={setModePractice}
                 pieceid={pieceid}
                 planid={planid}
+                csrf={csrf}
               />
             ),
           }[mode]
@@ -811,6 +825,7 @@ export function Summary({
   practice,
   pieceid,
   planid,
+  csrf,
 }: {
   summary: Section[];
   measuresPracticed: [number, number][];
@@ -818,7 +833,22 @@ export function Summary({
   practice: () => void;
   pieceid?: string;
   planid?: string;
+  csrf?: string;
 }) {
+  const [sessionId, setSessionId] = useState<string | null>(null);
+
+  useEffect(() => {
+    function onSaved(evt: SavedStartingPointSessionEvent) {
+      if (evt.detail.pieceid === pieceid) {
+        setSessionId(evt.detail.sessionId);
+      }
+    }
+    globalThis.addEventListener("SavedStartingPointSession", onSaved);
+    return () => {
+      globalThis.removeEventListener("SavedStartingPointSession", onSaved);
+    };
+  }, [pieceid, setSessionId]);
+
   return (
     <>
       <div className="flex w-full flex-col justify-center gap-4 pb-8 pt-4 sm:flex-row sm:gap-6 sm:pt-8">
@@ -850,6 +880,9 @@ export function Summary({
             </div>
           </div>
         </div>
+        {pieceid && csrf && sessionId && (
+          <SessionNotes pieceid={pieceid} csrf={csrf} sessionId={sessionId} />
+        )}
         <h2 className="inline pr-2 text-xl font-semibold text-black">
           Section Summary
         </h2>
@@ -899,3 +932,111 @@ export function Summary({
     </>
   );
 }
+
+type SessionRating = "poor" | "fine" | "excellent";
+
+// SessionNotes lets the player rate the session and mark trouble bars, which can then be turned into
+// spots
+function SessionNotes({
+  pieceid,
+  csrf,
+  sessionId,
+}: {
+  pieceid: string;
+  csrf: string;
+  sessionId: string;
+}) {
+  const [rating, setRating] = useState<SessionRating | null>(null);
+  const [troubleMeasures, setTroubleMeasures] = useState("");
+  const [savedTrouble, setSavedTrouble] = useState<string | null>(null);
+  const [error, setError] = useState("");
+
+  const saveNotes = useCallback(() => {
+    setError("");
+    fetch(`/library/pieces/${pieceid}/practice/starting-point/${sessionId}`, {
+      method: "POST",
+      headers: {
+        "X-CSRF-Token": csrf,
+        "Content-Type": "application/json",
+      },
+      body: JSON.stringify({ rating: rating ?? "", troubleMeasures }),
+    })
+      .then((res) => {
+        if (res.ok) {
+          res
+            .json()
+            .then((data: { troubleMeasures: string }) => {
+              setSavedTrouble(data.troubleMeasures);
+              setTroubleMeasures(data.troubleMeasures);
+            })
+            .catch(console.error);
+        } else {
+          res
+            .text()
+            .then((text) => setError(text.trim()))
+            .catch(console.error);
+        }
+      })
+      .catch((err) => {
+        console.error(err);
+        setError("Your notes could not be saved.");
+      });
+  }, [pieceid, csrf, sessionId, rating, troubleMeasures]);
+
+  return (
+    <div className="flex w-full max-w-xl flex-col gap-3 rounded-xl border border-neutral-500 bg-white/80 px-6 py-4 shadow">
+      <h2 className="text-center text-xl font-semibold text-black">
+        How did it go?
+      </h2>
+      <div className="flex justify-center gap-2">
+        {(["poor", "fine", "excellent"] as SessionRating[]).map((r) => (
+          <button
+            key={r}
+            type="button"
+            className={`action-button focusable capitalize ${
+              rating === r ? "violet" : "neutral"
+            }`}
+            onClick={() => setRating(r)}
+          >
+            {r}
+          </button>
+        ))}
+      </div>
+      <label className="flex flex-col gap-1 text-sm font-medium">
+        Trouble bars
+        <input
+          type="text"
+          className="basic-field w-full"
+          placeholder="5-6, 12"
+          value={troubleMeasures}
+          onInput={(e) => setTroubleMeasures(e.currentTarget.value)}
+        />
+      </label>
+      {error && <p className="text-sm text-red-800">{error}</p>}
+      <div className="flex flex-wrap justify-end gap-2">
+        {savedTrouble && (
+          <form
+            method="POST"
+            action={`/library/pieces/${pieceid}/starting-points/${sessionId}/spots`}
+          >
+            <input type="hidden" name="gorilla.csrf.Token" value={csrf} />
+            <button type="submit" className="action-button green focusable">
+              <span
+                className="-ml-1 size-6 icon-[ph--circles-three-plus-thin]"
+                aria-hidden="true"
+              />
+              Make Spots
+            </button>
+          </form>
+        )}
+        <button
+          type="button"
+          className="action-button violet focusable"
+          onClick={saveNotes}
+        >
+          {savedTrouble === null ? "Save Notes" : "Update Notes"}
+        </button>
+      </div>
+    </div>
+  );
+}
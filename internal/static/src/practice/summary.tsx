@@ -1,4 +1,10 @@
-import { cn, type PracticeSummaryItem } from "../common";
+import {
+  cn,
+  defaultRandomSpotRules,
+  excellentInARow,
+  type PracticeSummaryItem,
+  type RandomSpotRules,
+} from "../common";
 import { BackToPiece, BackToPlan } from "../ui/links";
 import { useCallback, useEffect, useRef, useState } from "preact/hooks";
 import { useAutoAnimate } from "@formkit/auto-animate/preact";
@@ -16,6 +22,7 @@ export default function Summary({
   startTime,
   initialSpotIds,
   planid = "",
+  rules = defaultRandomSpotRules,
 }: {
   summary: PracticeSummaryItem[];
   setup: () => void;
@@ -25,35 +32,59 @@ export default function Summary({
   startTime?: Date;
   initialSpotIds?: string[];
   planid?: string;
+  rules?: RandomSpotRules;
 }) {
   const dialogRef = useRef<HTMLDialogElement>(null);
   const [promotionSpots, setPromotionSpots] = useState<PracticeSummaryItem[]>(
     [],
   );
   const [demotionSpots, setDemotionSpots] = useState<PracticeSummaryItem[]>([]);
+  // spots the player chose to keep in random practicing, the server won't move these
+  const [keptSpotIds, setKeptSpotIds] = useState<Set<string>>(new Set());
+  const [submitPending, setSubmitPending] = useState(false);
   const [hasSetup, setHasSetup] = useState(false);
 
   const submit = useCallback(() => {
     if (pieceid && csrf && startTime) {
       const seenIds = new Set();
-      const spots: { id: string; promote: boolean; demote: boolean }[] = [];
-      for (const spot of promotionSpots) {
-        if (seenIds.has(spot.id)) continue;
-        if (!initialSpotIds?.includes(spot.id)) continue;
-        spots.push({ id: spot.id, promote: true, demote: false });
+      const spots: {
+        id: string;
+        promote: boolean;
+        demote: boolean;
+        excellent: number;
+        fine: number;
+        poor: number;
+        evaluations: PracticeSummaryItem["evaluations"];
+        keep: boolean;
+      }[] = [];
+      // the server checks the counts against the rules again before moving anything
+      const pushSpot = (
+        spot: PracticeSummaryItem,
+        promote: boolean,
+        demote: boolean,
+      ) => {
+        if (seenIds.has(spot.id)) return;
+        if (!initialSpotIds?.includes(spot.id)) return;
+        spots.push({
+          id: spot.id,
+          promote,
+          demote,
+          excellent: spot.excellent,
+          fine: spot.fine,
+          poor: spot.poor,
+          evaluations: spot.evaluations,
+          keep: keptSpotIds.has(spot.id),
+        });
         seenIds.add(spot.id);
+      };
+      for (const spot of promotionSpots) {
+        pushSpot(spot, true, false);
       }
       for (const spot of demotionSpots) {
-        if (seenIds.has(spot.id)) continue;
-        if (!initialSpotIds?.includes(spot.id)) continue;
-        spots.push({ id: spot.id, promote: false, demote: true });
-        seenIds.add(spot.id);
+        pushSpot(spot, false, true);
       }
       for (const spot of summary) {
-        if (seenIds.has(spot.id)) continue;
-        if (!initialSpotIds?.includes(spot.id)) continue;
-        spots.push({ id: spot.id, promote: false, demote: false });
-        seenIds.add(spot.id);
+        pushSpot(spot, false, false);
       }
       const durationMinutes = Math.ceil(
         (new Date().getTime() - startTime.getTime()) / 1000 / 60,
@@ -77,6 +108,7 @@ export default function Summary({
     summary,
     initialSpotIds,
     startTime,
+    keptSpotIds,
   ]);
 
   const close = useCallback(() => {
@@ -98,14 +130,31 @@ export default function Summary({
   }, [close, submit]);
 
   const rejectPromotions = useCallback(() => {
+    setKeptSpotIds(
+      (prev) =>
+        new Set([
+          ...prev,
+          ...promotionSpots.map((spot) => spot.id),
+          ...demotionSpots.map((spot) => spot.id),
+        ]),
+    );
     setPromotionSpots([]);
     setDemotionSpots([]);
-    submit();
-    close();
-  }, [close, submit, setPromotionSpots, setDemotionSpots]);
+    setSubmitPending(true);
+  }, [promotionSpots, demotionSpots, setPromotionSpots, setDemotionSpots]);
+
+  // submit once the rejected spots are in state, otherwise it would send the old lists
+  useEffect(() => {
+    if (submitPending) {
+      setSubmitPending(false);
+      submit();
+      close();
+    }
+  }, [submitPending, submit, close]);
 
   const removePromotionSpot = useCallback(
     (id: string) => {
+      setKeptSpotIds((prev) => new Set([...prev, id]));
       setPromotionSpots((prev) => prev.filter((spot) => spot.id !== id));
     },
     [setPromotionSpots],
@@ -113,18 +162,18 @@ export default function Summary({
 
   const removeDemotionSpot = useCallback(
     (id: string) => {
+      setKeptSpotIds((prev) => new Set([...prev, id]));
       setDemotionSpots((prev) => prev.filter((spot) => spot.id !== id));
     },
     [setDemotionSpots],
   );
 
   /*
-   * Spot Promotion/Demotion rules
-   * - just three excellents, recommend promotion beyond day four
-   * - always evict after five net excellents (minus poor)
-   * - after day five, demote if no excellents
-   * - always evict after three poors
-   * - after day three, demote after three poors
+   * Spot Promotion/Demotion rules, the numbers come from the user's stage rules
+   * - enough excellents in a row, recommend promotion after the minimum days. The server also
+   *   counts the excellents in a row from earlier sessions.
+   * - demote after enough poors
+   * - after enough days, demote if there are no excellents
    */
   useEffect(() => {
     if (hasSetup) {
@@ -135,16 +184,20 @@ export default function Summary({
     const promote: PracticeSummaryItem[] = [];
     const demote: PracticeSummaryItem[] = [];
     for (const item of summary) {
-      if (
-        item.excellent > 2 &&
-        item.poor === 0 &&
-        item.fine < 2 &&
-        item.day > 5
-      ) {
+      const promoteRule =
+        item.evaluations.length > 0
+          ? excellentInARow(item.evaluations) >= rules.randomPromoteExcellent
+          : item.excellent >= rules.randomPromoteExcellent &&
+            item.poor === 0 &&
+            item.fine < 2;
+      if (promoteRule && item.day >= rules.randomPromoteMinDays) {
         promote.push(item);
       } else if (
-        item.poor > 2 ||
-        (item.excellent === 0 && item.fine > 0 && item.poor > 0 && item.day > 6)
+        item.poor >= rules.randomDemotePoor ||
+        (item.excellent === 0 &&
+          item.fine > 0 &&
+          item.poor > 0 &&
+          item.day >= rules.randomDemoteStaleDays)
       ) {
         demote.push(item);
       }
@@ -164,6 +217,7 @@ export default function Summary({
     summary,
     submit,
     hasSetup,
+    rules,
   ]);
 
   // eslint-disable-next-line @typescript-eslint/no-unsafe-assignment
@@ -204,7 +258,7 @@ export default function Summary({
             <h4 className="text-center text-lg font-bold">Demote Spots</h4>
             <p className="text-sm text-neutral-800">
               These spots could use a little more attention. Let’s send them
-              back to repeat practicing for now.
+              back for more repeat practicing for now.
             </p>
             {demotionSpots.length > 0 ? (
               <ul
@@ -71,7 +71,16 @@ export interface FocusInputEvent extends Event {
 
 export interface FinishedSpotPracticingEvent extends Event {
   detail: {
-    spots: { id: string; promote: boolean; demote: boolean }[];
+    spots: {
+      id: string;
+      promote: boolean;
+      demote: boolean;
+      excellent: number;
+      fine: number;
+      poor: number;
+      evaluations: ("excellent" | "fine" | "poor")[];
+      keep: boolean;
+    }[];
     durationMinutes: number;
     csrf: string;
     endpoint: string;
@@ -87,6 +96,13 @@ export interface FinishedStartingPointPracticingEvent extends Event {
   };
 }
 
+export interface SavedStartingPointSessionEvent extends Event {
+  detail: {
+    sessionId: string;
+    pieceid: string;
+  };
+}
+
 export interface FinishedRepeatPracticingEvent extends Event {
   detail: {
     durationMinutes: number;
@@ -94,6 +110,9 @@ export interface FinishedRepeatPracticingEvent extends Event {
     endpoint: string;
     toStage: string;
     success: boolean;
+    attempts: number;
+    successes: number;
+    resets: number;
   };
 }
 
@@ -116,6 +135,7 @@ declare global {
     FocusInput: FocusInputEvent;
     FinishedSpotPracticing: FinishedSpotPracticingEvent;
     FinishedStartingPointPracticing: FinishedStartingPointPracticingEvent;
+    SavedStartingPointSession: SavedStartingPointSessionEvent;
     FinishedRepeatPracticing: FinishedRepeatPracticingEvent;
   }
 }
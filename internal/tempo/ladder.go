@@ -0,0 +1,63 @@
+package tempo
+
+// MinTempo is the slowest marking the ladder will suggest
+const MinTempo = 20
+
+// MaxTempo is the fastest marking the ladder accepts
+const MaxTempo = 400
+
+// Attempt is one run through a spot at a metronome marking
+type Attempt struct {
+	Tempo   int64
+	Success bool
+}
+
+// Ladder works a spot up toward a goal tempo a few beats at a time
+type Ladder struct {
+	// Step is how many beats per minute to go up after a clean run
+	Step int64
+	// FallbackSteps is how many steps to go back down after a miss
+	FallbackSteps int64
+	// Goal is where the ladder stops climbing, zero if there isn't one
+	Goal int64
+}
+
+// Next suggests the marking for the next run. The first run is at the starting tempo. A clean run
+// goes up a step, but not past the goal, and a miss goes back to a slower tempo to try again from.
+func (l Ladder) Next(start int64, attempts []Attempt) int64 {
+	if len(attempts) == 0 {
+		return clamp(start)
+	}
+	last := attempts[len(attempts)-1]
+	if !last.Success {
+		return clamp(last.Tempo - l.Step*l.FallbackSteps)
+	}
+	if l.Goal > 0 && last.Tempo >= l.Goal {
+		return clamp(last.Tempo)
+	}
+	next := last.Tempo + l.Step
+	if l.Goal > 0 {
+		next = min(next, l.Goal)
+	}
+	return clamp(next)
+}
+
+// Reached is whether a clean run at this tempo means the spot is up to its goal
+func (l Ladder) Reached(tempo int64) bool {
+	return l.Goal > 0 && tempo >= l.Goal
+}
+
+// Best is the fastest clean run, zero if there hasn't been one
+func Best(attempts []Attempt) int64 {
+	var best int64
+	for _, attempt := range attempts {
+		if attempt.Success {
+			best = max(best, attempt.Tempo)
+		}
+	}
+	return best
+}
+
+func clamp(tempo int64) int64 {
+	return min(max(tempo, MinTempo), MaxTempo)
+}
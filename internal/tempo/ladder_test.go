@@ -0,0 +1,81 @@
+package tempo_test
+
+import (
+	"practicebetter/internal/tempo"
+	"testing"
+)
+
+func TestLadderNext(t *testing.T) {
+	tests := []struct {
+		name     string
+		ladder   tempo.Ladder
+		start    int64
+		attempts []tempo.Attempt
+		want     int64
+	}{
+		{"first run at the start", tempo.Ladder{Step: 4, FallbackSteps: 2}, 60, nil, 60},
+		{"start below the slowest tempo", tempo.Ladder{Step: 4, FallbackSteps: 2}, 5, nil, tempo.MinTempo},
+		{"start above the fastest tempo", tempo.Ladder{Step: 4, FallbackSteps: 2}, 1000, nil, tempo.MaxTempo},
+		{"clean run goes up a step", tempo.Ladder{Step: 4, FallbackSteps: 2}, 60, []tempo.Attempt{{Tempo: 100, Success: true}}, 104},
+		{"only the last run counts", tempo.Ladder{Step: 4, FallbackSteps: 2}, 60, []tempo.Attempt{{Tempo: 100, Success: false}, {Tempo: 92, Success: true}}, 96},
+		{"no goal climbs to the fastest tempo", tempo.Ladder{Step: 10, FallbackSteps: 2}, 60, []tempo.Attempt{{Tempo: 395, Success: true}}, tempo.MaxTempo},
+
+		{"miss goes back the fallback steps", tempo.Ladder{Step: 4, FallbackSteps: 2}, 60, []tempo.Attempt{{Tempo: 100, Success: false}}, 92},
+		{"miss after a climb", tempo.Ladder{Step: 5, FallbackSteps: 3}, 60, []tempo.Attempt{{Tempo: 100, Success: true}, {Tempo: 105, Success: false}}, 90},
+		{"miss with no fallback retries the same tempo", tempo.Ladder{Step: 4, FallbackSteps: 0}, 60, []tempo.Attempt{{Tempo: 100, Success: false}}, 100},
+		{"miss near the bottom stops at the slowest tempo", tempo.Ladder{Step: 10, FallbackSteps: 5}, 60, []tempo.Attempt{{Tempo: 40, Success: false}}, tempo.MinTempo},
+		{"miss at the goal still falls back", tempo.Ladder{Step: 4, FallbackSteps: 2, Goal: 120}, 60, []tempo.Attempt{{Tempo: 120, Success: false}}, 112},
+
+		{"step is cut short at the goal", tempo.Ladder{Step: 4, FallbackSteps: 2, Goal: 120}, 60, []tempo.Attempt{{Tempo: 118, Success: true}}, 120},
+		{"clean at the goal stays there", tempo.Ladder{Step: 4, FallbackSteps: 2, Goal: 120}, 60, []tempo.Attempt{{Tempo: 120, Success: true}}, 120},
+		{"clean past the goal stays there", tempo.Ladder{Step: 4, FallbackSteps: 2, Goal: 120}, 60, []tempo.Attempt{{Tempo: 130, Success: true}}, 130},
+		{"goal past the fastest tempo", tempo.Ladder{Step: 4, FallbackSteps: 2, Goal: 1000}, 60, []tempo.Attempt{{Tempo: 398, Success: true}}, tempo.MaxTempo},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.ladder.Next(tt.start, tt.attempts); got != tt.want {
+				t.Errorf("Next() = %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestLadderReached(t *testing.T) {
+	tests := []struct {
+		name  string
+		goal  int64
+		tempo int64
+		want  bool
+	}{
+		{"no goal", 0, 200, false},
+		{"below the goal", 120, 119, false},
+		{"at the goal", 120, 120, true},
+		{"past the goal", 120, 140, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := (tempo.Ladder{Goal: tt.goal}).Reached(tt.tempo); got != tt.want {
+				t.Errorf("Reached(%d) = %v, want %v", tt.tempo, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestBest(t *testing.T) {
+	tests := []struct {
+		name     string
+		attempts []tempo.Attempt
+		want     int64
+	}{
+		{"no runs", nil, 0},
+		{"only misses", []tempo.Attempt{{Tempo: 100}, {Tempo: 90}}, 0},
+		{"fastest clean run", []tempo.Attempt{{Tempo: 100, Success: true}, {Tempo: 108, Success: false}, {Tempo: 104, Success: true}, {Tempo: 96, Success: true}}, 104},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tempo.Best(tt.attempts); got != tt.want {
+				t.Errorf("Best() = %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
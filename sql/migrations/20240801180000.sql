@@ -0,0 +1,28 @@
+-- Create "practice_sessions" table
+CREATE TABLE `practice_sessions` (
+  `id` text NOT NULL,
+  `user_id` text NOT NULL,
+  `practice_type` text NOT NULL,
+  `duration_minutes` integer NOT NULL,
+  `date` integer NOT NULL,
+  `spot_id` text NULL,
+  `piece_id` text NULL,
+  `user_scale_id` text NULL,
+  `reading_id` text NULL,
+  `practice_plan_id` text NULL,
+  PRIMARY KEY (`id`),
+  CONSTRAINT `0` FOREIGN KEY (`practice_plan_id`) REFERENCES `practice_plans` (`id`) ON UPDATE NO ACTION ON DELETE SET NULL,
+  CONSTRAINT `1` FOREIGN KEY (`reading_id`) REFERENCES `reading` (`id`) ON UPDATE NO ACTION ON DELETE SET NULL,
+  CONSTRAINT `2` FOREIGN KEY (`user_scale_id`) REFERENCES `user_scales` (`id`) ON UPDATE NO ACTION ON DELETE SET NULL,
+  CONSTRAINT `3` FOREIGN KEY (`piece_id`) REFERENCES `pieces` (`id`) ON UPDATE NO ACTION ON DELETE SET NULL,
+  CONSTRAINT `4` FOREIGN KEY (`spot_id`) REFERENCES `spots` (`id`) ON UPDATE NO ACTION ON DELETE SET NULL,
+  CONSTRAINT `5` FOREIGN KEY (`user_id`) REFERENCES `users` (`id`) ON UPDATE NO ACTION ON DELETE CASCADE,
+  CHECK (practice_type IN ('repeat', 'random_spots', 'starting_point', 'interleave', 'infrequent', 'scale', 'reading')),
+  CHECK (duration_minutes >= 0)
+);
+-- Create index "practice_sessions_user_date" to table: "practice_sessions"
+CREATE INDEX `practice_sessions_user_date` ON `practice_sessions` (`user_id`, `date`);
+-- Create index "practice_sessions_spot_id" to table: "practice_sessions"
+CREATE INDEX `practice_sessions_spot_id` ON `practice_sessions` (`spot_id`);
+-- Create index "practice_sessions_piece_id" to table: "practice_sessions"
+CREATE INDEX `practice_sessions_piece_id` ON `practice_sessions` (`piece_id`);
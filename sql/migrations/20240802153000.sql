@@ -0,0 +1,23 @@
+-- Create "spot_events" table
+CREATE TABLE `spot_events` (
+  `id` text NOT NULL,
+  `spot_id` text NOT NULL,
+  `user_id` text NOT NULL,
+  `event_type` text NOT NULL,
+  `practice_type` text NULL,
+  `evaluation` text NULL,
+  `success` boolean NULL,
+  `from_stage` text NULL,
+  `to_stage` text NULL,
+  `practice_plan_id` text NULL,
+  `date` integer NOT NULL,
+  PRIMARY KEY (`id`),
+  CONSTRAINT `0` FOREIGN KEY (`practice_plan_id`) REFERENCES `practice_plans` (`id`) ON UPDATE NO ACTION ON DELETE SET NULL,
+  CONSTRAINT `1` FOREIGN KEY (`user_id`) REFERENCES `users` (`id`) ON UPDATE NO ACTION ON DELETE CASCADE,
+  CONSTRAINT `2` FOREIGN KEY (`spot_id`) REFERENCES `spots` (`id`) ON UPDATE NO ACTION ON DELETE CASCADE,
+  CHECK (event_type IN ('evaluation', 'repeat', 'stage_change')),
+  CHECK (evaluation IS NULL OR evaluation IN ('poor', 'fine', 'excellent')),
+  CHECK (success IS NULL OR success IN (0, 1))
+);
+-- Create index "spot_events_spot_id_date" to table: "spot_events"
+CREATE INDEX `spot_events_spot_id_date` ON `spot_events` (`spot_id`, `date`);
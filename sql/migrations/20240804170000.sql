@@ -0,0 +1,67 @@
+-- Disable the enforcement of foreign-keys constraints
+PRAGMA foreign_keys = off;
+-- Create "new_users" table
+CREATE TABLE `new_users` (
+  `id` text NOT NULL,
+  `fullname` text NOT NULL DEFAULT '',
+  `email` text NOT NULL,
+  `email_verified` boolean NULL DEFAULT 0,
+  `active_practice_plan_id` text NULL,
+  `active_practice_plan_started` integer NULL,
+  `config_default_plan_intensity` text NOT NULL DEFAULT 'medium',
+  `config_time_between_breaks` integer NOT NULL DEFAULT 30,
+  PRIMARY KEY (`id`),
+  CONSTRAINT `0` FOREIGN KEY (`active_practice_plan_id`) REFERENCES `practice_plans` (`id`) ON UPDATE NO ACTION ON DELETE SET NULL,
+  CHECK (config_time_between_breaks > 5),
+  CHECK (config_time_between_breaks < 100),
+  CHECK (email_verified IN (0, 1))
+);
+-- Copy rows from old table "users" to new temporary table "new_users"
+INSERT INTO `new_users` (`id`, `fullname`, `email`, `email_verified`, `active_practice_plan_id`, `active_practice_plan_started`, `config_default_plan_intensity`, `config_time_between_breaks`) SELECT `id`, `fullname`, `email`, `email_verified`, `active_practice_plan_id`, `active_practice_plan_started`, `config_default_plan_intensity`, `config_time_between_breaks` FROM `users`;
+-- Drop "users" table after copying rows
+DROP TABLE `users`;
+-- Rename temporary table "new_users" to "users"
+ALTER TABLE `new_users` RENAME TO `users`;
+-- Create index "users_email" to table: "users"
+CREATE UNIQUE INDEX `users_email` ON `users` (`email`);
+-- Create "new_practice_plans" table
+CREATE TABLE `new_practice_plans` (
+  `id` text NOT NULL,
+  `user_id` text NOT NULL,
+  `intensity` text NOT NULL,
+  `date` integer NOT NULL,
+  `completed` boolean NOT NULL DEFAULT 0,
+  `practice_notes` text NULL,
+  `last_practiced` integer NULL,
+  PRIMARY KEY (`id`),
+  CONSTRAINT `0` FOREIGN KEY (`user_id`) REFERENCES `users` (`id`) ON UPDATE NO ACTION ON DELETE CASCADE
+);
+-- Copy rows from old table "practice_plans" to new temporary table "new_practice_plans"
+INSERT INTO `new_practice_plans` (`id`, `user_id`, `intensity`, `date`, `completed`, `practice_notes`, `last_practiced`) SELECT `id`, `user_id`, `intensity`, `date`, `completed`, `practice_notes`, `last_practiced` FROM `practice_plans`;
+-- Drop "practice_plans" table after copying rows
+DROP TABLE `practice_plans`;
+-- Rename temporary table "new_practice_plans" to "practice_plans"
+ALTER TABLE `new_practice_plans` RENAME TO `practice_plans`;
+-- Create index "practice_plans_user_id" to table: "practice_plans"
+CREATE INDEX `practice_plans_user_id` ON `practice_plans` (`user_id`);
+-- Create "intensity_profiles" table
+CREATE TABLE `intensity_profiles` (
+  `id` text NOT NULL,
+  `user_id` text NOT NULL,
+  `name` text NOT NULL,
+  `max_new_spots` integer NOT NULL,
+  `max_interleave_spots` integer NOT NULL,
+  `max_infrequent_spots` integer NOT NULL,
+  `max_sight_reading` integer NOT NULL,
+  PRIMARY KEY (`id`),
+  CONSTRAINT `0` FOREIGN KEY (`user_id`) REFERENCES `users` (`id`) ON UPDATE NO ACTION ON DELETE CASCADE,
+  CHECK (LENGTH(name) > 0),
+  CHECK (max_new_spots >= 0 AND max_new_spots <= 50),
+  CHECK (max_interleave_spots >= 0 AND max_interleave_spots <= 100),
+  CHECK (max_infrequent_spots >= 0 AND max_infrequent_spots <= 100),
+  CHECK (max_sight_reading >= 0 AND max_sight_reading <= 20)
+);
+-- Create index "intensity_profiles_user_id_name" to table: "intensity_profiles"
+CREATE UNIQUE INDEX `intensity_profiles_user_id_name` ON `intensity_profiles` (`user_id`, `name`);
+-- Enable back the enforcement of foreign-keys constraints
+PRAGMA foreign_keys = on;
@@ -0,0 +1,2 @@
+-- Add column "config_default_time_budget" to table: "users"
+ALTER TABLE `users` ADD COLUMN `config_default_time_budget` integer NOT NULL DEFAULT 45;
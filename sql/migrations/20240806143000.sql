@@ -0,0 +1,45 @@
+-- Disable the enforcement of foreign-keys constraints
+PRAGMA foreign_keys = off;
+-- Create "new_sections" table
+CREATE TABLE `new_sections` (
+  `id` text NOT NULL,
+  `name` text NOT NULL,
+  `description` text NOT NULL DEFAULT '',
+  `piece_id` text NOT NULL,
+  `start_measure` integer NULL,
+  `end_measure` integer NULL,
+  PRIMARY KEY (`id`),
+  CONSTRAINT `0` FOREIGN KEY (`piece_id`) REFERENCES `pieces` (`id`) ON UPDATE NO ACTION ON DELETE CASCADE,
+  CHECK (start_measure IS NULL OR start_measure > 0),
+  CHECK (end_measure IS NULL OR start_measure IS NULL OR end_measure >= start_measure)
+);
+-- Copy rows from old table "sections" to new temporary table "new_sections"
+INSERT INTO `new_sections` (`id`, `name`, `description`, `piece_id`) SELECT `id`, `name`, `description`, `piece_id` FROM `sections`;
+-- Drop "sections" table after copying rows
+DROP TABLE `sections`;
+-- Rename temporary table "new_sections" to "sections"
+ALTER TABLE `new_sections` RENAME TO `sections`;
+-- Create index "sections_piece_id" to table: "sections"
+CREATE INDEX `sections_piece_id` ON `sections` (`piece_id`);
+-- Create "new_spots_sections" table
+CREATE TABLE `new_spots_sections` (
+  `spot_id` text NOT NULL,
+  `section_id` text NOT NULL,
+  `piece_id` text NOT NULL,
+  PRIMARY KEY (`spot_id`, `section_id`),
+  CONSTRAINT `0` FOREIGN KEY (`piece_id`) REFERENCES `pieces` (`id`) ON UPDATE NO ACTION ON DELETE CASCADE,
+  CONSTRAINT `1` FOREIGN KEY (`section_id`) REFERENCES `sections` (`id`) ON UPDATE NO ACTION ON DELETE CASCADE,
+  CONSTRAINT `2` FOREIGN KEY (`spot_id`) REFERENCES `spots` (`id`) ON UPDATE NO ACTION ON DELETE CASCADE
+);
+-- Copy rows from old table "spots_sections" to new temporary table "new_spots_sections"
+INSERT INTO `new_spots_sections` (`spot_id`, `section_id`, `piece_id`) SELECT `spot_id`, `section_id`, `piece_id` FROM `spots_sections`;
+-- Drop "spots_sections" table after copying rows
+DROP TABLE `spots_sections`;
+-- Rename temporary table "new_spots_sections" to "spots_sections"
+ALTER TABLE `new_spots_sections` RENAME TO `spots_sections`;
+-- Create index "spots_sections_section_id" to table: "spots_sections"
+CREATE INDEX `spots_sections_section_id` ON `spots_sections` (`section_id`);
+-- Create index "spots_sections_piece_id" to table: "spots_sections"
+CREATE INDEX `spots_sections_piece_id` ON `spots_sections` (`piece_id`);
+-- Enable back the enforcement of foreign-keys constraints
+PRAGMA foreign_keys = on;
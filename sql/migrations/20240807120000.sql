@@ -0,0 +1,2 @@
+-- Add column "config_scheduler" to table: "users"
+ALTER TABLE `users` ADD COLUMN `config_scheduler` text NOT NULL DEFAULT 'classic';
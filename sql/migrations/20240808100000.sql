@@ -0,0 +1,26 @@
+-- Create "stage_rules" table
+CREATE TABLE `stage_rules` (
+  `user_id` text NOT NULL,
+  `random_promote_excellent` integer NOT NULL DEFAULT 3,
+  `random_promote_min_days` integer NOT NULL DEFAULT 6,
+  `random_demote_poor` integer NOT NULL DEFAULT 3,
+  `random_demote_stale_days` integer NOT NULL DEFAULT 7,
+  `random_demote_to` text NOT NULL DEFAULT 'extra_repeat',
+  `interleave_promote_excellent` integer NOT NULL DEFAULT 1,
+  `interleave_promote_min_days` integer NOT NULL DEFAULT 5,
+  `interleave_stale_days` integer NOT NULL DEFAULT 12,
+  `interleave_demote_to` text NOT NULL DEFAULT 'random',
+  `infrequent_demote_to` text NOT NULL DEFAULT 'interleave',
+  PRIMARY KEY (`user_id`),
+  CONSTRAINT `0` FOREIGN KEY (`user_id`) REFERENCES `users` (`id`) ON UPDATE NO ACTION ON DELETE CASCADE,
+  CHECK (random_promote_excellent > 0 AND random_promote_excellent <= 20),
+  CHECK (random_promote_min_days >= 0 AND random_promote_min_days <= 60),
+  CHECK (random_demote_poor > 0 AND random_demote_poor <= 20),
+  CHECK (random_demote_stale_days >= 0 AND random_demote_stale_days <= 60),
+  CHECK (random_demote_to IN ('repeat', 'extra_repeat')),
+  CHECK (interleave_promote_excellent > 0 AND interleave_promote_excellent <= 10),
+  CHECK (interleave_promote_min_days >= 0 AND interleave_promote_min_days <= 60),
+  CHECK (interleave_stale_days >= 0 AND interleave_stale_days <= 60),
+  CHECK (interleave_demote_to IN ('repeat', 'extra_repeat', 'random')),
+  CHECK (infrequent_demote_to IN ('repeat', 'extra_repeat', 'random', 'interleave'))
+);
@@ -0,0 +1,94 @@
+-- Create full text search tables, kept in sync by triggers
+CREATE VIRTUAL TABLE pieces_search USING fts5 (
+    piece_id UNINDEXED,
+    title,
+    composer
+);
+
+CREATE TRIGGER pieces_search_insert AFTER INSERT ON pieces BEGIN
+    INSERT INTO pieces_search (piece_id, title, composer)
+    VALUES (new.id, new.title, COALESCE(new.composer, ''));
+END;
+
+CREATE TRIGGER pieces_search_update AFTER UPDATE OF title, composer ON pieces BEGIN
+    UPDATE pieces_search
+    SET title = new.title, composer = COALESCE(new.composer, '')
+    WHERE piece_id = old.id;
+END;
+
+CREATE TRIGGER pieces_search_delete AFTER DELETE ON pieces BEGIN
+    DELETE FROM pieces_search WHERE piece_id = old.id;
+END;
+
+CREATE VIRTUAL TABLE spots_search USING fts5 (
+    spot_id UNINDEXED,
+    name,
+    text_prompt
+);
+
+CREATE TRIGGER spots_search_insert AFTER INSERT ON spots BEGIN
+    INSERT INTO spots_search (spot_id, name, text_prompt)
+    VALUES (new.id, new.name, new.text_prompt);
+END;
+
+CREATE TRIGGER spots_search_update AFTER UPDATE OF name, text_prompt ON spots BEGIN
+    UPDATE spots_search
+    SET name = new.name, text_prompt = new.text_prompt
+    WHERE spot_id = old.id;
+END;
+
+CREATE TRIGGER spots_search_delete AFTER DELETE ON spots BEGIN
+    DELETE FROM spots_search WHERE spot_id = old.id;
+END;
+
+CREATE VIRTUAL TABLE reading_search USING fts5 (
+    reading_id UNINDEXED,
+    title,
+    composer,
+    info
+);
+
+CREATE TRIGGER reading_search_insert AFTER INSERT ON reading BEGIN
+    INSERT INTO reading_search (reading_id, title, composer, info)
+    VALUES (new.id, new.title, COALESCE(new.composer, ''), COALESCE(new.info, ''));
+END;
+
+CREATE TRIGGER reading_search_update AFTER UPDATE OF title, composer, info ON reading BEGIN
+    UPDATE reading_search
+    SET title = new.title, composer = COALESCE(new.composer, ''), info = COALESCE(new.info, '')
+    WHERE reading_id = old.id;
+END;
+
+CREATE TRIGGER reading_search_delete AFTER DELETE ON reading BEGIN
+    DELETE FROM reading_search WHERE reading_id = old.id;
+END;
+
+CREATE VIRTUAL TABLE practice_notes_search USING fts5 (
+    practice_plan_id UNINDEXED,
+    practice_notes
+);
+
+CREATE TRIGGER practice_notes_search_insert AFTER INSERT ON practice_plans BEGIN
+    INSERT INTO practice_notes_search (practice_plan_id, practice_notes)
+    VALUES (new.id, COALESCE(new.practice_notes, ''));
+END;
+
+CREATE TRIGGER practice_notes_search_update AFTER UPDATE OF practice_notes ON practice_plans BEGIN
+    UPDATE practice_notes_search
+    SET practice_notes = COALESCE(new.practice_notes, '')
+    WHERE practice_plan_id = old.id;
+END;
+
+CREATE TRIGGER practice_notes_search_delete AFTER DELETE ON practice_plans BEGIN
+    DELETE FROM practice_notes_search WHERE practice_plan_id = old.id;
+END;
+
+-- Index everything that already exists
+INSERT INTO pieces_search (piece_id, title, composer)
+SELECT id, title, COALESCE(composer, '') FROM pieces;
+INSERT INTO spots_search (spot_id, name, text_prompt)
+SELECT id, name, text_prompt FROM spots;
+INSERT INTO reading_search (reading_id, title, composer, info)
+SELECT id, title, COALESCE(composer, ''), COALESCE(info, '') FROM reading;
+INSERT INTO practice_notes_search (practice_plan_id, practice_notes)
+SELECT id, COALESCE(practice_notes, '') FROM practice_plans;
@@ -0,0 +1,4 @@
+-- Add column "measures_start" to table: "spots"
+ALTER TABLE `spots` ADD COLUMN `measures_start` integer NULL;
+-- Add column "measures_end" to table: "spots"
+ALTER TABLE `spots` ADD COLUMN `measures_end` integer NULL;
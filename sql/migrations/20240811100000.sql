@@ -0,0 +1,19 @@
+-- Create "starting_point_sessions" table
+CREATE TABLE `starting_point_sessions` (
+  `id` text NOT NULL,
+  `user_id` text NOT NULL,
+  `piece_id` text NOT NULL,
+  `date` integer NOT NULL,
+  `measures_practiced` text NOT NULL,
+  `rating` text NULL,
+  `trouble_measures` text NOT NULL DEFAULT '',
+  `trouble_converted` boolean NOT NULL DEFAULT 0,
+  `practice_plan_id` text NULL,
+  PRIMARY KEY (`id`),
+  CONSTRAINT `0` FOREIGN KEY (`practice_plan_id`) REFERENCES `practice_plans` (`id`) ON UPDATE NO ACTION ON DELETE SET NULL,
+  CONSTRAINT `1` FOREIGN KEY (`piece_id`) REFERENCES `pieces` (`id`) ON UPDATE NO ACTION ON DELETE CASCADE,
+  CONSTRAINT `2` FOREIGN KEY (`user_id`) REFERENCES `users` (`id`) ON UPDATE NO ACTION ON DELETE CASCADE,
+  CHECK (rating IS NULL OR rating IN ('poor', 'fine', 'excellent'))
+);
+-- Create index "starting_point_sessions_piece_id_date" to table: "starting_point_sessions"
+CREATE INDEX `starting_point_sessions_piece_id_date` ON `starting_point_sessions` (`piece_id`, `date`);
@@ -0,0 +1,6 @@
+-- Add column "attempts" to table: "spot_events"
+ALTER TABLE `spot_events` ADD COLUMN `attempts` integer NULL;
+-- Add column "successes" to table: "spot_events"
+ALTER TABLE `spot_events` ADD COLUMN `successes` integer NULL;
+-- Add column "resets" to table: "spot_events"
+ALTER TABLE `spot_events` ADD COLUMN `resets` integer NULL;
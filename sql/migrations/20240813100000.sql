@@ -0,0 +1,17 @@
+-- Create "spot_tempos" table
+CREATE TABLE `spot_tempos` (
+  `id` text NOT NULL,
+  `spot_id` text NOT NULL,
+  `user_id` text NOT NULL,
+  `tempo` integer NOT NULL,
+  `source` text NOT NULL,
+  `success` boolean NULL,
+  `date` integer NOT NULL,
+  PRIMARY KEY (`id`),
+  CONSTRAINT `0` FOREIGN KEY (`user_id`) REFERENCES `users` (`id`) ON UPDATE NO ACTION ON DELETE CASCADE,
+  CONSTRAINT `1` FOREIGN KEY (`spot_id`) REFERENCES `spots` (`id`) ON UPDATE NO ACTION ON DELETE CASCADE,
+  CHECK (source IN ('edit', 'ladder')),
+  CHECK (success IS NULL OR success IN (0, 1))
+);
+-- Create index "spot_tempos_spot_id_date" to table: "spot_tempos"
+CREATE INDEX `spot_tempos_spot_id_date` ON `spot_tempos` (`spot_id`, `date`);
@@ -0,0 +1,2 @@
+-- Add column "config_timezone" to table: "users"
+ALTER TABLE `users` ADD COLUMN `config_timezone` text NOT NULL DEFAULT '';
@@ -0,0 +1,6 @@
+-- Create "data_migrations" table
+CREATE TABLE `data_migrations` (
+  `name` text NOT NULL,
+  `applied` integer NOT NULL,
+  PRIMARY KEY (`name`)
+);
@@ -0,0 +1,266 @@
+-- name: CountUserLibraryItems :one
+SELECT CAST(
+    (SELECT COUNT(*) FROM pieces WHERE pieces.user_id = :user_id)
+    + (SELECT COUNT(*) FROM reading WHERE reading.user_id = :user_id)
+    + (SELECT COUNT(*) FROM user_scales WHERE user_scales.user_id = :user_id)
+    + (SELECT COUNT(*) FROM practice_plans WHERE practice_plans.user_id = :user_id)
+    AS INTEGER
+) AS library_items;
+
+-- name: ListUserPiecesForExport :many
+SELECT *
+FROM pieces
+WHERE pieces.user_id = ?
+ORDER BY pieces.rowid;
+
+-- name: ListUserSectionsForExport :many
+SELECT sections.*
+FROM sections
+INNER JOIN pieces ON pieces.id = sections.piece_id
+WHERE pieces.user_id = ?
+ORDER BY sections.rowid;
+
+-- name: ListUserSpotsForExport :many
+SELECT spots.*
+FROM spots
+INNER JOIN pieces ON pieces.id = spots.piece_id
+WHERE pieces.user_id = ?
+ORDER BY spots.rowid;
+
+-- name: ListUserSpotsSectionsForExport :many
+SELECT spots_sections.*
+FROM spots_sections
+INNER JOIN pieces ON pieces.id = spots_sections.piece_id
+WHERE pieces.user_id = ?
+ORDER BY spots_sections.rowid;
+
+-- name: ListUserScalesForExport :many
+SELECT *
+FROM user_scales
+WHERE user_scales.user_id = ?
+ORDER BY user_scales.rowid;
+
+-- name: ListUserReadingForExport :many
+SELECT *
+FROM reading
+WHERE reading.user_id = ?
+ORDER BY reading.rowid;
+
+-- name: ListUserPracticePlansForExport :many
+SELECT *
+FROM practice_plans
+WHERE practice_plans.user_id = ?
+ORDER BY practice_plans.rowid;
+
+-- name: ListUserPracticePlanSpotsForExport :many
+SELECT practice_plan_spots.*
+FROM practice_plan_spots
+INNER JOIN practice_plans ON practice_plans.id = practice_plan_spots.practice_plan_id
+WHERE practice_plans.user_id = ?
+ORDER BY practice_plan_spots.rowid;
+
+-- name: ListUserPracticePlanPiecesForExport :many
+SELECT practice_plan_pieces.*
+FROM practice_plan_pieces
+INNER JOIN practice_plans ON practice_plans.id = practice_plan_pieces.practice_plan_id
+WHERE practice_plans.user_id = ?
+ORDER BY practice_plan_pieces.rowid;
+
+-- name: ListUserPracticePlanScalesForExport :many
+SELECT practice_plan_scales.*
+FROM practice_plan_scales
+INNER JOIN practice_plans ON practice_plans.id = practice_plan_scales.practice_plan_id
+WHERE practice_plans.user_id = ?
+ORDER BY practice_plan_scales.rowid;
+
+-- name: ListUserPracticePlanReadingForExport :many
+SELECT practice_plan_reading.*
+FROM practice_plan_reading
+INNER JOIN practice_plans ON practice_plans.id = practice_plan_reading.practice_plan_id
+WHERE practice_plans.user_id = ?
+ORDER BY practice_plan_reading.rowid;
+
+-- name: ListUserPracticeSessionsForExport :many
+SELECT *
+FROM practice_sessions
+WHERE practice_sessions.user_id = ?
+ORDER BY practice_sessions.rowid;
+
+-- name: ListUserSpotEventsForExport :many
+SELECT *
+FROM spot_events
+WHERE spot_events.user_id = ?
+ORDER BY spot_events.rowid;
+
+-- name: ListUserStartingPointSessionsForExport :many
+SELECT *
+FROM starting_point_sessions
+WHERE starting_point_sessions.user_id = ?
+ORDER BY starting_point_sessions.rowid;
+
+-- name: ImportPiece :exec
+INSERT INTO pieces (
+    id,
+    title,
+    description,
+    composer,
+    measures,
+    beats_per_measure,
+    goal_tempo,
+    user_id,
+    last_practiced,
+    stage,
+    key_id,
+    mode_id
+) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?);
+
+-- name: ImportSection :exec
+INSERT INTO sections (
+    id,
+    name,
+    description,
+    piece_id,
+    start_measure,
+    end_measure
+) VALUES (?, ?, ?, ?, ?, ?);
+
+-- name: ImportSpot :exec
+INSERT INTO spots (
+    id,
+    piece_id,
+    name,
+    stage,
+    measures,
+    audio_prompt_url,
+    image_prompt_url,
+    notes_prompt,
+    text_prompt,
+    current_tempo,
+    last_practiced,
+    stage_started,
+    skip_days,
+    priority,
+    section_id,
+    measures_start,
+    measures_end
+) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?);
+
+-- name: ImportSpotsSection :exec
+INSERT INTO spots_sections (
+    spot_id,
+    section_id,
+    piece_id
+) VALUES (?, ?, ?);
+
+-- name: ImportUserScale :exec
+INSERT INTO user_scales (
+    id,
+    user_id,
+    scale_id,
+    practice_notes,
+    last_practiced,
+    reference,
+    working
+) VALUES (?, ?, ?, ?, ?, ?, ?);
+
+-- name: ImportReading :exec
+INSERT INTO reading (
+    id,
+    title,
+    info,
+    completed,
+    composer,
+    user_id
+) VALUES (?, ?, ?, ?, ?, ?);
+
+-- name: ImportPracticePlan :exec
+INSERT INTO practice_plans (
+    id,
+    user_id,
+    intensity,
+    date,
+    completed,
+    practice_notes,
+    last_practiced
+) VALUES (?, ?, ?, ?, ?, ?, ?);
+
+-- name: ImportPracticePlanSpot :exec
+INSERT INTO practice_plan_spots (
+    practice_plan_id,
+    spot_id,
+    practice_type,
+    evaluation,
+    completed,
+    idx
+) VALUES (?, ?, ?, ?, ?, ?);
+
+-- name: ImportPracticePlanPiece :exec
+INSERT INTO practice_plan_pieces (
+    practice_plan_id,
+    piece_id,
+    practice_type,
+    completed,
+    sessions,
+    idx
+) VALUES (?, ?, ?, ?, ?, ?);
+
+-- name: ImportPracticePlanScale :exec
+INSERT INTO practice_plan_scales (
+    practice_plan_id,
+    user_scale_id,
+    completed,
+    idx
+) VALUES (?, ?, ?, ?);
+
+-- name: ImportPracticePlanReading :exec
+INSERT INTO practice_plan_reading (
+    practice_plan_id,
+    reading_id,
+    completed,
+    idx
+) VALUES (?, ?, ?, ?);
+
+-- name: ImportPracticeSession :exec
+INSERT INTO practice_sessions (
+    id,
+    user_id,
+    practice_type,
+    duration_minutes,
+    date,
+    spot_id,
+    piece_id,
+    user_scale_id,
+    reading_id,
+    practice_plan_id
+) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?);
+
+-- name: ImportSpotEvent :exec
+INSERT INTO spot_events (
+    id,
+    spot_id,
+    user_id,
+    event_type,
+    practice_type,
+    evaluation,
+    success,
+    from_stage,
+    to_stage,
+    practice_plan_id,
+    date,
+    attempts,
+    successes,
+    resets
+) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?);
+
+-- name: ImportStartingPointSession :exec
+INSERT INTO starting_point_sessions (
+    id,
+    user_id,
+    piece_id,
+    date,
+    measures_practiced,
+    rating,
+    trouble_measures,
+    trouble_converted,
+    practice_plan_id
+) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?);
@@ -0,0 +1,8 @@
+-- name: GetDataMigration :one
+SELECT *
+FROM data_migrations
+WHERE name = ?;
+
+-- name: RecordDataMigration :exec
+INSERT INTO data_migrations (name, applied)
+VALUES (?, unixepoch('now'));
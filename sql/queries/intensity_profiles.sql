@@ -0,0 +1,49 @@
+-- name: ListUserIntensityProfiles :many
+SELECT *
+FROM intensity_profiles
+WHERE user_id = ?
+ORDER BY rowid;
+
+-- name: GetIntensityProfile :one
+SELECT *
+FROM intensity_profiles
+WHERE id = ? AND user_id = ?;
+
+-- name: CreateIntensityProfile :one
+INSERT INTO intensity_profiles (
+    id,
+    user_id,
+    name,
+    max_new_spots,
+    max_interleave_spots,
+    max_infrequent_spots,
+    max_sight_reading
+) VALUES (?, ?, ?, ?, ?, ?, ?)
+RETURNING *;
+
+-- name: CreateDefaultIntensityProfile :exec
+INSERT INTO intensity_profiles (
+    id,
+    user_id,
+    name,
+    max_new_spots,
+    max_interleave_spots,
+    max_infrequent_spots,
+    max_sight_reading
+) VALUES (?, ?, ?, ?, ?, ?, ?)
+ON CONFLICT (user_id, name) DO NOTHING;
+
+-- name: UpdateIntensityProfile :one
+UPDATE intensity_profiles
+SET
+    name = ?,
+    max_new_spots = ?,
+    max_interleave_spots = ?,
+    max_infrequent_spots = ?,
+    max_sight_reading = ?
+WHERE id = ? AND user_id = ?
+RETURNING *;
+
+-- name: DeleteIntensityProfile :exec
+DELETE FROM intensity_profiles
+WHERE id = ? AND user_id = ?;
@@ -35,7 +35,9 @@ SELECT
     spots.text_prompt AS spot_text_prompt,
     spots.current_tempo AS spot_current_tempo,
     spots.measures AS spot_measures,
-    spots.last_practiced AS spot_last_practiced
+    spots.last_practiced AS spot_last_practiced,
+    spots.measures_start AS spot_measures_start,
+    spots.measures_end AS spot_measures_end
 FROM pieces
 LEFT JOIN spots ON pieces.id = spots.piece_id
 LEFT JOIN scale_keys ON pieces.key_id = scale_keys.id
@@ -105,7 +107,8 @@ SELECT
     spots.name AS spot_name,
     spots.stage AS spot_stage,
     spots.last_practiced AS spot_last_practiced,
-    spots.skip_days AS spot_skip_days
+    spots.skip_days AS spot_skip_days,
+    spots.priority AS spot_priority
 FROM pieces
 LEFT JOIN spots ON pieces.id = spots.piece_id
 WHERE pieces.id = :piece_id AND pieces.user_id = :user_id;
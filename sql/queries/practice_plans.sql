@@ -86,6 +86,7 @@ SELECT
     spots.stage AS spot_stage,
     spots.skip_days AS spot_skip_days,
     spots.stage_started AS spot_stage_started,
+    spots.priority AS spot_priority,
     (SELECT pieces.title FROM pieces WHERE pieces.id = spots.piece_id LIMIT 1) AS spot_piece_title
 FROM practice_plans
 INNER JOIN practice_plan_spots ON practice_plans.id = practice_plan_spots.practice_plan_id
@@ -350,7 +351,11 @@ AND practice_plan_spots.practice_plan_id = (SELECT practice_plans.id FROM practi
 ORDER BY practice_plan_spots.idx;
 
 -- name: GetPracticePlanFailedNewSpots :many
-SELECT practice_plan_spots.*
+SELECT practice_plan_spots.*,
+    spots.name AS spot_name,
+    (SELECT COUNT(spot_events.id) FROM spot_events WHERE spot_events.spot_id = spots.id AND spot_events.event_type = 'repeat' AND spot_events.success = 0) AS repeat_failures,
+    CAST(COALESCE((SELECT SUM(spot_events.attempts) FROM spot_events WHERE spot_events.spot_id = spots.id AND spot_events.event_type = 'repeat'), 0) AS INTEGER) AS repeat_attempts,
+    CAST(COALESCE((SELECT SUM(spot_events.resets) FROM spot_events WHERE spot_events.spot_id = spots.id AND spot_events.event_type = 'repeat'), 0) AS INTEGER) AS repeat_resets
 FROM practice_plan_spots
 INNER JOIN spots ON practice_plan_spots.spot_id = spots.id
 WHERE practice_plan_spots.practice_type = 'new'
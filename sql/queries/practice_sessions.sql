@@ -0,0 +1,56 @@
+-- name: CreatePracticeSession :exec
+INSERT INTO practice_sessions (
+    id,
+    user_id,
+    practice_type,
+    duration_minutes,
+    date,
+    spot_id,
+    piece_id,
+    user_scale_id,
+    reading_id,
+    practice_plan_id
+) VALUES (?, ?, ?, ?, unixepoch('now'), ?, ?, ?, ?, ?);
+
+-- name: ListRecentUserPracticeSessions :many
+SELECT *
+FROM practice_sessions
+WHERE user_id = ?
+ORDER BY date DESC
+LIMIT ?;
+
+
+-- name: ListUserPracticeSessionAverages :many
+SELECT
+    practice_type,
+    CAST(AVG(duration_minutes) AS REAL) AS average_minutes,
+    COUNT(id) AS session_count
+FROM practice_sessions
+WHERE user_id = ? AND date > ?
+GROUP BY practice_type;
+
+-- name: ListUserPracticeMinutes :many
+SELECT
+    date,
+    duration_minutes
+FROM practice_sessions
+WHERE user_id = ?
+ORDER BY date;
+
+-- name: ListUserPracticeTimes :many
+SELECT practice_plans.date AS practiced_at
+FROM practice_plans
+WHERE practice_plans.user_id = :user_id AND practice_plans.last_practiced IS NOT NULL
+UNION ALL
+SELECT practice_plans.last_practiced
+FROM practice_plans
+WHERE practice_plans.user_id = :user_id AND practice_plans.last_practiced IS NOT NULL
+UNION ALL
+SELECT pieces.last_practiced
+FROM pieces
+WHERE pieces.user_id = :user_id AND pieces.last_practiced IS NOT NULL
+UNION ALL
+SELECT spots.last_practiced
+FROM spots
+INNER JOIN pieces ON pieces.id = spots.piece_id
+WHERE pieces.user_id = :user_id AND spots.last_practiced IS NOT NULL;
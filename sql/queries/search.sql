@@ -0,0 +1,51 @@
+-- name: SearchPieces :many
+SELECT
+    pieces.id,
+    pieces.title,
+    pieces.composer,
+    CAST(SNIPPET(pieces_search, -1, CHAR(2), CHAR(3), '…', 12) AS TEXT) AS highlight
+FROM pieces_search
+INNER JOIN pieces ON pieces.id = pieces_search.piece_id
+WHERE pieces_search MATCH :query AND pieces.user_id = :user_id
+ORDER BY pieces_search.rank
+LIMIT :limit;
+
+-- name: SearchSpots :many
+SELECT
+    spots.id,
+    spots.piece_id,
+    spots.name,
+    spots.stage,
+    pieces.title AS piece_title,
+    CAST(SNIPPET(spots_search, -1, CHAR(2), CHAR(3), '…', 12) AS TEXT) AS highlight
+FROM spots_search
+INNER JOIN spots ON spots.id = spots_search.spot_id
+INNER JOIN pieces ON pieces.id = spots.piece_id
+WHERE spots_search MATCH :query AND pieces.user_id = :user_id
+ORDER BY spots_search.rank
+LIMIT :limit;
+
+-- name: SearchReadingItems :many
+SELECT
+    reading.id,
+    reading.title,
+    reading.composer,
+    reading.completed,
+    CAST(SNIPPET(reading_search, -1, CHAR(2), CHAR(3), '…', 12) AS TEXT) AS highlight
+FROM reading_search
+INNER JOIN reading ON reading.id = reading_search.reading_id
+WHERE reading_search MATCH :query AND reading.user_id = :user_id
+ORDER BY reading_search.rank
+LIMIT :limit;
+
+-- name: SearchPracticeNotes :many
+SELECT
+    practice_plans.id,
+    practice_plans.date,
+    practice_plans.intensity,
+    CAST(SNIPPET(practice_notes_search, -1, CHAR(2), CHAR(3), '…', 16) AS TEXT) AS highlight
+FROM practice_notes_search
+INNER JOIN practice_plans ON practice_plans.id = practice_notes_search.practice_plan_id
+WHERE practice_notes_search MATCH :query AND practice_plans.user_id = :user_id
+ORDER BY practice_notes_search.rank
+LIMIT :limit;
@@ -0,0 +1,69 @@
+-- name: CreateSection :one
+INSERT INTO sections (
+    id,
+    name,
+    description,
+    piece_id,
+    start_measure,
+    end_measure
+) VALUES (
+    :id,
+    :name,
+    :description,
+    (SELECT pieces.id FROM pieces WHERE pieces.user_id = :user_id AND pieces.id = :piece_id LIMIT 1),
+    :start_measure,
+    :end_measure
+)
+RETURNING *;
+
+-- name: ListPieceSections :many
+SELECT sections.*
+FROM sections
+WHERE sections.piece_id = (SELECT pieces.id FROM pieces WHERE pieces.user_id = :user_id AND pieces.id = :piece_id LIMIT 1)
+ORDER BY sections.start_measure IS NULL, sections.start_measure, sections.name;
+
+-- name: GetSection :one
+SELECT sections.*
+FROM sections
+WHERE sections.id = :section_id
+AND sections.piece_id = (SELECT pieces.id FROM pieces WHERE pieces.user_id = :user_id AND pieces.id = :piece_id LIMIT 1);
+
+-- name: UpdateSection :one
+UPDATE sections
+SET
+    name = :name,
+    description = :description,
+    start_measure = :start_measure,
+    end_measure = :end_measure
+WHERE sections.id = :section_id
+AND sections.piece_id = (SELECT pieces.id FROM pieces WHERE pieces.user_id = :user_id AND pieces.id = :piece_id LIMIT 1)
+RETURNING *;
+
+-- name: DeleteSection :exec
+DELETE FROM sections
+WHERE sections.id = :section_id
+AND sections.piece_id = (SELECT pieces.id FROM pieces WHERE pieces.user_id = :user_id AND pieces.id = :piece_id LIMIT 1);
+
+-- name: ListPieceSpotsSections :many
+SELECT spots_sections.spot_id, spots_sections.section_id
+FROM spots_sections
+WHERE spots_sections.piece_id = (SELECT pieces.id FROM pieces WHERE pieces.user_id = :user_id AND pieces.id = :piece_id LIMIT 1);
+
+-- name: ListSectionSpotIDs :many
+SELECT spots_sections.spot_id
+FROM spots_sections
+WHERE spots_sections.section_id = :section_id
+AND spots_sections.piece_id = (SELECT pieces.id FROM pieces WHERE pieces.user_id = :user_id AND pieces.id = :piece_id LIMIT 1);
+
+-- name: AddSpotToSection :exec
+INSERT OR IGNORE INTO spots_sections (spot_id, section_id, piece_id)
+SELECT spots.id, sections.id, spots.piece_id
+FROM spots
+INNER JOIN sections ON sections.piece_id = spots.piece_id
+WHERE spots.id = :spot_id AND sections.id = :section_id
+AND spots.piece_id = (SELECT pieces.id FROM pieces WHERE pieces.user_id = :user_id AND pieces.id = :piece_id LIMIT 1);
+
+-- name: ClearSectionSpots :exec
+DELETE FROM spots_sections
+WHERE spots_sections.section_id = :section_id
+AND spots_sections.piece_id = (SELECT pieces.id FROM pieces WHERE pieces.user_id = :user_id AND pieces.id = :piece_id LIMIT 1);
@@ -0,0 +1,112 @@
+-- name: CreateSpotEvent :exec
+INSERT INTO spot_events (
+    id,
+    spot_id,
+    user_id,
+    event_type,
+    practice_type,
+    evaluation,
+    success,
+    attempts,
+    successes,
+    resets,
+    practice_plan_id,
+    date
+) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, unixepoch('now'));
+
+-- name: GetSpotStage :one
+SELECT stage
+FROM spots
+WHERE spots.id = :spot_id AND spots.piece_id IN (SELECT pieces.id FROM pieces WHERE pieces.user_id = :user_id);
+
+-- name: CreateSpotStageChangeEvent :exec
+INSERT INTO spot_events (
+    id,
+    spot_id,
+    user_id,
+    event_type,
+    from_stage,
+    to_stage,
+    practice_plan_id,
+    date
+)
+SELECT
+    :id,
+    spots.id,
+    :user_id,
+    'stage_change',
+    :from_stage,
+    spots.stage,
+    :practice_plan_id,
+    unixepoch('now')
+FROM spots
+WHERE spots.id = :spot_id AND spots.stage != :from_stage;
+
+-- name: ListSpotEvents :many
+SELECT *
+FROM spot_events
+WHERE spot_events.spot_id = :spot_id AND spot_events.user_id = :user_id
+ORDER BY spot_events.date DESC, spot_events.rowid DESC;
+
+-- name: ListUserInfrequentEvaluations :many
+SELECT
+    spot_events.spot_id,
+    spot_events.evaluation,
+    spot_events.date
+FROM spot_events
+INNER JOIN spots ON spots.id = spot_events.spot_id
+WHERE spot_events.user_id = :user_id
+    AND spot_events.event_type = 'evaluation'
+    AND spot_events.practice_type = 'interleave_days'
+    AND spots.stage = 'interleave_days'
+    AND spot_events.date >= COALESCE(spots.stage_started, 0)
+ORDER BY spot_events.spot_id, spot_events.date, spot_events.rowid;
+
+-- name: ListSpotStageEvaluations :many
+SELECT
+    spot_events.evaluation,
+    spot_events.date
+FROM spot_events
+INNER JOIN spots ON spots.id = spot_events.spot_id
+WHERE spot_events.spot_id = :spot_id
+    AND spot_events.user_id = :user_id
+    AND spot_events.event_type = 'evaluation'
+    AND spot_events.practice_type = :practice_type
+    AND spot_events.date >= COALESCE(spots.stage_started, 0)
+ORDER BY spot_events.date, spot_events.rowid;
+
+-- name: ListPieceRandomEvaluations :many
+SELECT
+    spot_events.spot_id,
+    spot_events.evaluation,
+    spot_events.date,
+    spots.stage_started AS spot_stage_started
+FROM spot_events
+INNER JOIN spots ON spots.id = spot_events.spot_id
+WHERE spot_events.user_id = :user_id
+    AND spots.piece_id = :piece_id
+    AND spot_events.event_type = 'evaluation'
+    AND spot_events.practice_type = 'random_spots'
+ORDER BY spot_events.spot_id, spot_events.date, spot_events.rowid;
+
+-- name: ListPieceStageChanges :many
+SELECT
+    spot_events.spot_id,
+    spot_events.from_stage,
+    spot_events.to_stage,
+    spot_events.date
+FROM spot_events
+INNER JOIN spots ON spots.id = spot_events.spot_id
+WHERE spot_events.user_id = :user_id
+    AND spots.piece_id = :piece_id
+    AND spot_events.event_type = 'stage_change'
+ORDER BY spot_events.date, spot_events.rowid;
+
+-- name: ListPieceSpotStages :many
+SELECT
+    spots.id,
+    spots.stage,
+    spots.stage_started
+FROM spots
+INNER JOIN pieces ON pieces.id = spots.piece_id
+WHERE pieces.user_id = :user_id AND spots.piece_id = :piece_id;
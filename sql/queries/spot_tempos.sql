@@ -0,0 +1,56 @@
+-- name: CreateSpotTempoChange :exec
+INSERT INTO spot_tempos (
+    id,
+    spot_id,
+    user_id,
+    tempo,
+    source,
+    date
+)
+SELECT
+    :id,
+    spots.id,
+    :user_id,
+    spots.current_tempo,
+    'edit',
+    unixepoch('now')
+FROM spots
+WHERE spots.id = :spot_id
+    AND spots.piece_id IN (SELECT pieces.id FROM pieces WHERE pieces.user_id = :user_id)
+    AND spots.current_tempo IS NOT NULL
+    AND spots.current_tempo IS NOT (
+        SELECT spot_tempos.tempo
+        FROM spot_tempos
+        WHERE spot_tempos.spot_id = spots.id AND spot_tempos.success IS NOT 0
+        ORDER BY spot_tempos.date DESC, spot_tempos.rowid DESC
+        LIMIT 1
+    );
+
+-- name: CreateSpotLadderTempo :exec
+INSERT INTO spot_tempos (
+    id,
+    spot_id,
+    user_id,
+    tempo,
+    source,
+    success,
+    date
+) VALUES (?, ?, ?, ?, 'ladder', ?, unixepoch('now'));
+
+-- name: ListSpotTempos :many
+SELECT *
+FROM spot_tempos
+WHERE spot_id = :spot_id AND user_id = :user_id
+ORDER BY date, rowid;
+
+-- name: ListPieceSpotTempos :many
+SELECT
+    spot_tempos.spot_id,
+    spot_tempos.tempo,
+    spot_tempos.date
+FROM spot_tempos
+INNER JOIN spots ON spots.id = spot_tempos.spot_id
+WHERE spots.piece_id = :piece_id
+    AND spot_tempos.user_id = :user_id
+    AND spot_tempos.success IS NOT 0
+ORDER BY spot_tempos.spot_id, spot_tempos.date, spot_tempos.rowid;
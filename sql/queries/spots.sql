@@ -10,10 +10,12 @@ INSERT INTO spots (
     text_prompt,
     current_tempo,
     measures,
+    measures_start,
+    measures_end,
     stage_started
 ) VALUES (
     (SELECT pieces.id FROM pieces WHERE pieces.user_id = :user_id AND pieces.id = :piece_id LIMIT 1),
-    ?, ?, ?, ?, ?, ?, ?, ?, ?,
+    ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?,
     unixepoch('now')
 )
 RETURNING *;
@@ -67,11 +69,16 @@ WHERE pieces.user_id = :user_id AND spots.stage = :stage
 AND spots.id NOT IN (SELECT practice_plan_spots.spot_id FROM practice_plan_spots WHERE practice_plan_spots.practice_plan_id = :plan_id)
 ORDER BY spots.last_practiced DESC;
 
+-- name: ListSpotsWithoutMeasureRange :many
+SELECT id, measures
+FROM spots
+WHERE measures IS NOT NULL AND measures != '' AND measures_start IS NULL;
 
 -- name: GetSpot :one
 SELECT
     spots.*,
-    pieces.title AS piece_title
+    pieces.title AS piece_title,
+    pieces.goal_tempo AS piece_goal_tempo
 FROM spots
 INNER JOIN pieces ON pieces.id = spots.piece_id
 WHERE spots.id = :spot_id AND spots.piece_id = (SELECT pieces.id FROM pieces WHERE pieces.user_id = :user_id AND pieces.id = :piece_id LIMIT 1);
@@ -96,15 +103,26 @@ SET
     notes_prompt = ?,
     text_prompt = ?,
     current_tempo = ?,
-    measures = ?
+    measures = ?,
+    measures_start = ?,
+    measures_end = ?
 WHERE spots.id = :spot_id AND piece_id = (SELECT pieces.id FROM pieces WHERE pieces.user_id = :user_id AND pieces.id = :piece_id LIMIT 1);
 
+-- name: UpdateSpotMeasureRange :exec
+UPDATE spots
+SET
+    measures_start = :measures_start,
+    measures_end = :measures_end
+WHERE spots.id = :spot_id;
+
 -- name: UpdatePartialSpot :one
 UPDATE spots
 SET
     name = ?,
     current_tempo = ?,
-    measures = ?
+    measures = ?,
+    measures_start = ?,
+    measures_end = ?
 WHERE spots.id = :spot_id AND piece_id = (SELECT pieces.id FROM pieces WHERE pieces.user_id = :user_id AND pieces.id = :piece_id LIMIT 1)
 RETURNING *;
 
@@ -146,74 +164,15 @@ SET
     stage_started = unixepoch('now')
 WHERE spots.id = :spot_id AND piece_id IN (SELECT pieces.id FROM pieces WHERE pieces.user_id = :user_id);
 
--- name: PromoteSpotToRandom :exec
-UPDATE spots
-SET
-    stage = CASE WHEN stage = 'repeat' OR stage = 'extra_repeat' THEN 'random' ELSE stage END,
-    stage_started = CASE WHEN stage = 'repeat' OR stage = 'extra_repeat' THEN unixepoch('now') ELSE stage_started END,
-    last_practiced = unixepoch('now')
-WHERE spots.id = :spot_id AND piece_id = (SELECT pieces.id FROM pieces WHERE pieces.user_id = :user_id AND pieces.id = :piece_id LIMIT 1);
-
--- name: PromoteSpotToExtraRepeat :exec
-UPDATE spots
-SET
-    stage = CASE WHEN stage = 'repeat' THEN 'extra_repeat' ELSE stage END,
-    stage_started = CASE WHEN stage = 'repeat' THEN unixepoch('now') ELSE stage_started END,
-    last_practiced = unixepoch('now')
-WHERE spots.id = :spot_id AND piece_id = (SELECT pieces.id FROM pieces WHERE pieces.user_id = :user_id AND pieces.id = :piece_id LIMIT 1);
-
--- name: DemoteSpotToExtraRepeat :exec
-UPDATE spots
-SET
-    stage = CASE WHEN stage != 'extra_repeat' AND stage != 'repeat' THEN 'extra_repeat' ELSE stage END,
-    stage_started = CASE WHEN stage != 'extra_repeat' AND stage != 'repeat' THEN unixepoch('now') ELSE stage_started END,
-    last_practiced = unixepoch('now')
-WHERE spots.id = :spot_id AND piece_id = (SELECT pieces.id FROM pieces WHERE pieces.user_id = :user_id AND pieces.id = :piece_id LIMIT 1);
-
--- name: PromoteSpotToInterleave :exec
-UPDATE spots
-SET
-    stage = CASE WHEN stage = 'random' THEN 'interleave' ELSE stage END,
-    stage_started = CASE WHEN stage = 'random' THEN unixepoch('now') ELSE stage_started END,
-    last_practiced = unixepoch('now')
-WHERE spots.id = :spot_id AND piece_id = (SELECT pieces.id FROM pieces WHERE pieces.user_id = :user_id AND pieces.id = :piece_id LIMIT 1);
-
--- name: PromoteSpotToInterleaveDays :exec
-UPDATE spots
-SET
-    stage = CASE WHEN stage = 'interleave' THEN 'interleave_days' ELSE stage END,
-    stage_started = CASE WHEN stage = 'interleave' THEN unixepoch('now') ELSE stage_started END,
-    skip_days = 1,
-    last_practiced = unixepoch('now')
-WHERE spots.id = :spot_id AND piece_id IN (SELECT pieces.id FROM pieces WHERE pieces.user_id = :user_id);
-
--- name: DemoteSpotToRandom :exec
+-- name: ChangeSpotStage :exec
 UPDATE spots
 SET
-    stage = CASE WHEN stage = 'interleave' THEN 'random' ELSE stage END,
-    stage_started = CASE WHEN stage = 'interleave' THEN unixepoch('now') ELSE stage_started END,
+    stage = CASE WHEN stage = :from_stage THEN :to_stage ELSE stage END,
+    stage_started = CASE WHEN stage = :from_stage THEN unixepoch('now') ELSE stage_started END,
+    skip_days = CASE WHEN stage = :from_stage THEN 1 ELSE skip_days END,
     last_practiced = unixepoch('now')
 WHERE spots.id = :spot_id AND piece_id IN (SELECT pieces.id FROM pieces WHERE pieces.user_id = :user_id);
 
--- name: DemoteSpotToInterleave :exec
-UPDATE spots
-SET
-    stage = CASE WHEN stage = 'interleave_days' THEN 'interleave' ELSE stage END,
-    stage_started = CASE WHEN stage = 'interleave_days' THEN unixepoch('now') ELSE stage_started END,
-    skip_days = 1,
-    last_practiced = unixepoch('now')
-WHERE spots.id = :spot_id AND piece_id IN (SELECT pieces.id FROM pieces WHERE pieces.user_id = :user_id);
-
--- name: PromoteSpotToCompleted :one
-UPDATE spots
-SET
-    stage = 'completed',
-    stage_started = unixepoch('now'),
-    skip_days = 1,
-    last_practiced = unixepoch('now')
-WHERE spots.id = :spot_id AND piece_id IN (SELECT pieces.id FROM pieces WHERE pieces.user_id = :user_id)
-RETURNING *;
-
 -- name: UpdateSpotSkipDays :exec
 UPDATE spots
 SET
@@ -232,6 +191,13 @@ UPDATE spots
 SET last_practiced = unixepoch('now')
 WHERE spots.id = :spot_id AND spots.piece_id IN (SELECT pieces.id FROM pieces WHERE pieces.user_id = :user_id);
 
+-- name: RaiseSpotTempo :exec
+UPDATE spots
+SET current_tempo = :tempo
+WHERE spots.id = :spot_id
+    AND spots.piece_id = (SELECT pieces.id FROM pieces WHERE pieces.user_id = :user_id AND pieces.id = :piece_id LIMIT 1)
+    AND (spots.current_tempo IS NULL OR spots.current_tempo < :tempo);
+
 -- name: DeleteSpot :exec
 DELETE FROM spots
 WHERE spots.id = :spot_id AND spots.piece_id = (SELECT pieces.id FROM pieces WHERE pieces.user_id = :user_id AND pieces.id = :piece_id LIMIT 1);
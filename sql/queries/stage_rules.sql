@@ -0,0 +1,31 @@
+-- name: GetStageRules :one
+SELECT *
+FROM stage_rules
+WHERE user_id = ?;
+
+-- name: UpsertStageRules :one
+INSERT INTO stage_rules (
+    user_id,
+    random_promote_excellent,
+    random_promote_min_days,
+    random_demote_poor,
+    random_demote_stale_days,
+    random_demote_to,
+    interleave_promote_excellent,
+    interleave_promote_min_days,
+    interleave_stale_days,
+    interleave_demote_to,
+    infrequent_demote_to
+) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+ON CONFLICT (user_id) DO UPDATE SET
+    random_promote_excellent = excluded.random_promote_excellent,
+    random_promote_min_days = excluded.random_promote_min_days,
+    random_demote_poor = excluded.random_demote_poor,
+    random_demote_stale_days = excluded.random_demote_stale_days,
+    random_demote_to = excluded.random_demote_to,
+    interleave_promote_excellent = excluded.interleave_promote_excellent,
+    interleave_promote_min_days = excluded.interleave_promote_min_days,
+    interleave_stale_days = excluded.interleave_stale_days,
+    interleave_demote_to = excluded.interleave_demote_to,
+    infrequent_demote_to = excluded.infrequent_demote_to
+RETURNING *;
@@ -0,0 +1,42 @@
+-- name: CreateStartingPointSession :exec
+INSERT INTO starting_point_sessions (
+    id,
+    user_id,
+    piece_id,
+    date,
+    measures_practiced,
+    practice_plan_id
+)
+SELECT
+    :id,
+    pieces.user_id,
+    pieces.id,
+    unixepoch('now'),
+    :measures_practiced,
+    :practice_plan_id
+FROM pieces
+WHERE pieces.id = :piece_id AND pieces.user_id = :user_id;
+
+-- name: GetStartingPointSession :one
+SELECT *
+FROM starting_point_sessions
+WHERE id = :session_id AND user_id = :user_id AND piece_id = :piece_id;
+
+-- name: ListPieceStartingPointSessions :many
+SELECT *
+FROM starting_point_sessions
+WHERE user_id = :user_id AND piece_id = :piece_id AND date > :since
+ORDER BY date DESC;
+
+-- name: UpdateStartingPointSessionNotes :exec
+UPDATE starting_point_sessions
+SET
+    rating = :rating,
+    trouble_measures = :trouble_measures,
+    trouble_converted = 0
+WHERE id = :session_id AND user_id = :user_id AND piece_id = :piece_id;
+
+-- name: MarkStartingPointTroubleConverted :exec
+UPDATE starting_point_sessions
+SET trouble_converted = 1
+WHERE id = :session_id AND user_id = :user_id AND piece_id = :piece_id;
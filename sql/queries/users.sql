@@ -45,7 +45,10 @@ RETURNING *;
 UPDATE users
 SET
     config_default_plan_intensity = COALESCE(?, config_default_plan_intensity),
-    config_time_between_breaks = COALESCE(?, config_time_between_breaks)
+    config_time_between_breaks = COALESCE(?, config_time_between_breaks),
+    config_default_time_budget = COALESCE(?, config_default_time_budget),
+    config_scheduler = COALESCE(?, config_scheduler),
+    config_timezone = COALESCE(?, config_timezone)
 WHERE id = ?
 RETURNING *;
 
@@ -53,6 +56,16 @@ RETURNING *;
 UPDATE users SET email_verified = 1 WHERE id = ?
 RETURNING *;
 
+-- name: SetUserDefaultPlanIntensity :exec
+UPDATE users
+SET config_default_plan_intensity = ?
+WHERE id = :user_id AND config_default_plan_intensity = :previous_intensity;
+
+-- name: SetUserTimezone :exec
+UPDATE users
+SET config_timezone = ?
+WHERE id = :user_id AND config_timezone = '';
+
 -- name: DeleteUser :exec
 DELETE FROM users WHERE id = ?;
 
@@ -9,7 +9,9 @@ CREATE TABLE users (
     active_practice_plan_started INTEGER,
     config_default_plan_intensity TEXT NOT NULL DEFAULT 'medium',
     config_time_between_breaks INTEGER NOT NULL DEFAULT 30,
-    CHECK (config_default_plan_intensity IN ('light', 'medium', 'heavy')),
+    config_default_time_budget INTEGER NOT NULL DEFAULT 45,
+    config_scheduler TEXT NOT NULL DEFAULT 'classic',
+    config_timezone TEXT NOT NULL DEFAULT '',
     CHECK (config_time_between_breaks > 5),
     CHECK (config_time_between_breaks < 100),
     PRIMARY KEY (id),
@@ -85,6 +87,8 @@ CREATE TABLE spots (
     skip_days INTEGER NOT NULL DEFAULT 1,
     priority INTEGER NOT NULL DEFAULT 0,
     section_id TEXT,
+    measures_start INTEGER,
+    measures_end INTEGER,
     CHECK(stage IN ('repeat', 'extra_repeat', 'random', 'interleave', 'interleave_days', 'completed')),
     CHECK(LENGTH(name) > 0),
     CHECK(priority > -3),
@@ -111,7 +115,6 @@ CREATE TABLE practice_plans (
     completed BOOLEAN NOT NULL DEFAULT 0,
     practice_notes TEXT,
     last_practiced INTEGER,
-    CHECK (intensity IN ('light', 'medium', 'heavy')),
     PRIMARY KEY (id),
     CONSTRAINT user FOREIGN KEY (user_id) REFERENCES users (
         id
@@ -209,20 +212,38 @@ CREATE TABLE practice_plan_scales (
 CREATE TABLE sections (
     id TEXT PRIMARY KEY NOT NULL,
     name TEXT NOT NULL,
-    description TEXT NOT NULL,
+    description TEXT NOT NULL DEFAULT '',
     piece_id TEXT NOT NULL,
-    FOREIGN KEY (piece_id) REFERENCES pieces(id)
+    start_measure INTEGER,
+    end_measure INTEGER,
+    CHECK (start_measure IS NULL OR start_measure > 0),
+    CHECK (end_measure IS NULL OR start_measure IS NULL OR end_measure >= start_measure),
+    CONSTRAINT piece FOREIGN KEY (piece_id) REFERENCES pieces (
+        id
+    ) ON UPDATE NO ACTION ON DELETE CASCADE
 );
 
+CREATE INDEX sections_piece_id ON sections (piece_id);
+
 CREATE TABLE spots_sections (
     spot_id TEXT NOT NULL,
     section_id TEXT NOT NULL,
     piece_id TEXT NOT NULL,
     PRIMARY KEY (spot_id, section_id),
-    FOREIGN KEY (spot_id) REFERENCES spots(id),
-    FOREIGN KEY (section_id) REFERENCES sections(id)
+    CONSTRAINT spot FOREIGN KEY (spot_id) REFERENCES spots (
+        id
+    ) ON UPDATE NO ACTION ON DELETE CASCADE,
+    CONSTRAINT section FOREIGN KEY (section_id) REFERENCES sections (
+        id
+    ) ON UPDATE NO ACTION ON DELETE CASCADE,
+    CONSTRAINT piece FOREIGN KEY (piece_id) REFERENCES pieces (
+        id
+    ) ON UPDATE NO ACTION ON DELETE CASCADE
 );
 
+CREATE INDEX spots_sections_section_id ON spots_sections (section_id);
+CREATE INDEX spots_sections_piece_id ON spots_sections (piece_id);
+
 CREATE TABLE reading (
     id TEXT NOT NULL,
     title TEXT NOT NULL,
@@ -251,3 +272,260 @@ CREATE TABLE practice_plan_reading (
         id
     ) ON UPDATE NO ACTION ON DELETE CASCADE
 );
+
+CREATE TABLE practice_sessions (
+    id TEXT NOT NULL,
+    user_id TEXT NOT NULL,
+    practice_type TEXT NOT NULL,
+    duration_minutes INTEGER NOT NULL,
+    date INTEGER NOT NULL,
+    spot_id TEXT,
+    piece_id TEXT,
+    user_scale_id TEXT,
+    reading_id TEXT,
+    practice_plan_id TEXT,
+    PRIMARY KEY (id),
+    CHECK (practice_type IN ('repeat', 'random_spots', 'starting_point', 'interleave', 'infrequent', 'scale', 'reading')),
+    CHECK (duration_minutes >= 0),
+    CONSTRAINT user FOREIGN KEY (user_id) REFERENCES users (
+        id
+    ) ON UPDATE NO ACTION ON DELETE CASCADE,
+    CONSTRAINT spot FOREIGN KEY (spot_id) REFERENCES spots (
+        id
+    ) ON UPDATE NO ACTION ON DELETE SET NULL,
+    CONSTRAINT piece FOREIGN KEY (piece_id) REFERENCES pieces (
+        id
+    ) ON UPDATE NO ACTION ON DELETE SET NULL,
+    CONSTRAINT scale FOREIGN KEY (user_scale_id) REFERENCES user_scales (
+        id
+    ) ON UPDATE NO ACTION ON DELETE SET NULL,
+    CONSTRAINT reading FOREIGN KEY (reading_id) REFERENCES reading (
+        id
+    ) ON UPDATE NO ACTION ON DELETE SET NULL,
+    CONSTRAINT plan FOREIGN KEY (practice_plan_id) REFERENCES practice_plans (
+        id
+    ) ON UPDATE NO ACTION ON DELETE SET NULL
+);
+
+CREATE INDEX practice_sessions_user_date ON practice_sessions (user_id, date);
+CREATE INDEX practice_sessions_spot_id ON practice_sessions (spot_id);
+CREATE INDEX practice_sessions_piece_id ON practice_sessions (piece_id);
+
+CREATE TABLE spot_events (
+    id TEXT NOT NULL,
+    spot_id TEXT NOT NULL,
+    user_id TEXT NOT NULL,
+    event_type TEXT NOT NULL,
+    practice_type TEXT,
+    evaluation TEXT,
+    success BOOLEAN,
+    from_stage TEXT,
+    to_stage TEXT,
+    practice_plan_id TEXT,
+    date INTEGER NOT NULL,
+    attempts INTEGER,
+    successes INTEGER,
+    resets INTEGER,
+    PRIMARY KEY (id),
+    CHECK (event_type IN ('evaluation', 'repeat', 'stage_change')),
+    CHECK (evaluation IS NULL OR evaluation IN ('poor', 'fine', 'excellent')),
+    CHECK (success IS NULL OR success IN (0, 1)),
+    CONSTRAINT spot FOREIGN KEY (spot_id) REFERENCES spots (
+        id
+    ) ON UPDATE NO ACTION ON DELETE CASCADE,
+    CONSTRAINT user FOREIGN KEY (user_id) REFERENCES users (
+        id
+    ) ON UPDATE NO ACTION ON DELETE CASCADE,
+    CONSTRAINT plan FOREIGN KEY (practice_plan_id) REFERENCES practice_plans (
+        id
+    ) ON UPDATE NO ACTION ON DELETE SET NULL
+);
+
+CREATE INDEX spot_events_spot_id_date ON spot_events (spot_id, date);
+
+CREATE TABLE starting_point_sessions (
+    id TEXT NOT NULL,
+    user_id TEXT NOT NULL,
+    piece_id TEXT NOT NULL,
+    date INTEGER NOT NULL,
+    measures_practiced TEXT NOT NULL,
+    rating TEXT,
+    trouble_measures TEXT NOT NULL DEFAULT '',
+    trouble_converted BOOLEAN NOT NULL DEFAULT 0,
+    practice_plan_id TEXT,
+    PRIMARY KEY (id),
+    CHECK (rating IS NULL OR rating IN ('poor', 'fine', 'excellent')),
+    CONSTRAINT user FOREIGN KEY (user_id) REFERENCES users (
+        id
+    ) ON UPDATE NO ACTION ON DELETE CASCADE,
+    CONSTRAINT piece FOREIGN KEY (piece_id) REFERENCES pieces (
+        id
+    ) ON UPDATE NO ACTION ON DELETE CASCADE,
+    CONSTRAINT plan FOREIGN KEY (practice_plan_id) REFERENCES practice_plans (
+        id
+    ) ON UPDATE NO ACTION ON DELETE SET NULL
+);
+
+CREATE INDEX starting_point_sessions_piece_id_date ON starting_point_sessions (piece_id, date);
+
+CREATE TABLE spot_tempos (
+    id TEXT NOT NULL,
+    spot_id TEXT NOT NULL,
+    user_id TEXT NOT NULL,
+    tempo INTEGER NOT NULL,
+    source TEXT NOT NULL,
+    success BOOLEAN,
+    date INTEGER NOT NULL,
+    PRIMARY KEY (id),
+    CHECK (source IN ('edit', 'ladder')),
+    CHECK (success IS NULL OR success IN (0, 1)),
+    CONSTRAINT spot FOREIGN KEY (spot_id) REFERENCES spots (
+        id
+    ) ON UPDATE NO ACTION ON DELETE CASCADE,
+    CONSTRAINT user FOREIGN KEY (user_id) REFERENCES users (
+        id
+    ) ON UPDATE NO ACTION ON DELETE CASCADE
+);
+
+CREATE INDEX spot_tempos_spot_id_date ON spot_tempos (spot_id, date);
+
+CREATE TABLE intensity_profiles (
+    id TEXT NOT NULL,
+    user_id TEXT NOT NULL,
+    name TEXT NOT NULL,
+    max_new_spots INTEGER NOT NULL,
+    max_interleave_spots INTEGER NOT NULL,
+    max_infrequent_spots INTEGER NOT NULL,
+    max_sight_reading INTEGER NOT NULL,
+    PRIMARY KEY (id),
+    CHECK (LENGTH(name) > 0),
+    CHECK (max_new_spots >= 0 AND max_new_spots <= 50),
+    CHECK (max_interleave_spots >= 0 AND max_interleave_spots <= 100),
+    CHECK (max_infrequent_spots >= 0 AND max_infrequent_spots <= 100),
+    CHECK (max_sight_reading >= 0 AND max_sight_reading <= 20),
+    CONSTRAINT user FOREIGN KEY (user_id) REFERENCES users (
+        id
+    ) ON UPDATE NO ACTION ON DELETE CASCADE
+);
+
+CREATE UNIQUE INDEX intensity_profiles_user_id_name ON intensity_profiles (user_id, name);
+
+CREATE TABLE stage_rules (
+    user_id TEXT NOT NULL,
+    random_promote_excellent INTEGER NOT NULL DEFAULT 3,
+    random_promote_min_days INTEGER NOT NULL DEFAULT 6,
+    random_demote_poor INTEGER NOT NULL DEFAULT 3,
+    random_demote_stale_days INTEGER NOT NULL DEFAULT 7,
+    random_demote_to TEXT NOT NULL DEFAULT 'extra_repeat',
+    interleave_promote_excellent INTEGER NOT NULL DEFAULT 1,
+    interleave_promote_min_days INTEGER NOT NULL DEFAULT 5,
+    interleave_stale_days INTEGER NOT NULL DEFAULT 12,
+    interleave_demote_to TEXT NOT NULL DEFAULT 'random',
+    infrequent_demote_to TEXT NOT NULL DEFAULT 'interleave',
+    PRIMARY KEY (user_id),
+    CHECK (random_promote_excellent > 0 AND random_promote_excellent <= 20),
+    CHECK (random_promote_min_days >= 0 AND random_promote_min_days <= 60),
+    CHECK (random_demote_poor > 0 AND random_demote_poor <= 20),
+    CHECK (random_demote_stale_days >= 0 AND random_demote_stale_days <= 60),
+    CHECK (random_demote_to IN ('repeat', 'extra_repeat')),
+    CHECK (interleave_promote_excellent > 0 AND interleave_promote_excellent <= 10),
+    CHECK (interleave_promote_min_days >= 0 AND interleave_promote_min_days <= 60),
+    CHECK (interleave_stale_days >= 0 AND interleave_stale_days <= 60),
+    CHECK (interleave_demote_to IN ('repeat', 'extra_repeat', 'random')),
+    CHECK (infrequent_demote_to IN ('repeat', 'extra_repeat', 'random', 'interleave')),
+    CONSTRAINT user FOREIGN KEY (user_id) REFERENCES users (
+        id
+    ) ON UPDATE NO ACTION ON DELETE CASCADE
+);
+
+-- data fixes that have to run in go, each one is recorded so it only runs once
+CREATE TABLE data_migrations (
+    name TEXT NOT NULL,
+    applied INTEGER NOT NULL,
+    PRIMARY KEY (name)
+);
+
+-- Full text search, the triggers keep the search tables in sync with the tables they index
+CREATE VIRTUAL TABLE pieces_search USING fts5 (
+    piece_id UNINDEXED,
+    title,
+    composer
+);
+
+CREATE TRIGGER pieces_search_insert AFTER INSERT ON pieces BEGIN
+    INSERT INTO pieces_search (piece_id, title, composer)
+    VALUES (new.id, new.title, COALESCE(new.composer, ''));
+END;
+
+CREATE TRIGGER pieces_search_update AFTER UPDATE OF title, composer ON pieces BEGIN
+    UPDATE pieces_search
+    SET title = new.title, composer = COALESCE(new.composer, '')
+    WHERE piece_id = old.id;
+END;
+
+CREATE TRIGGER pieces_search_delete AFTER DELETE ON pieces BEGIN
+    DELETE FROM pieces_search WHERE piece_id = old.id;
+END;
+
+CREATE VIRTUAL TABLE spots_search USING fts5 (
+    spot_id UNINDEXED,
+    name,
+    text_prompt
+);
+
+CREATE TRIGGER spots_search_insert AFTER INSERT ON spots BEGIN
+    INSERT INTO spots_search (spot_id, name, text_prompt)
+    VALUES (new.id, new.name, new.text_prompt);
+END;
+
+CREATE TRIGGER spots_search_update AFTER UPDATE OF name, text_prompt ON spots BEGIN
+    UPDATE spots_search
+    SET name = new.name, text_prompt = new.text_prompt
+    WHERE spot_id = old.id;
+END;
+
+CREATE TRIGGER spots_search_delete AFTER DELETE ON spots BEGIN
+    DELETE FROM spots_search WHERE spot_id = old.id;
+END;
+
+CREATE VIRTUAL TABLE reading_search USING fts5 (
+    reading_id UNINDEXED,
+    title,
+    composer,
+    info
+);
+
+CREATE TRIGGER reading_search_insert AFTER INSERT ON reading BEGIN
+    INSERT INTO reading_search (reading_id, title, composer, info)
+    VALUES (new.id, new.title, COALESCE(new.composer, ''), COALESCE(new.info, ''));
+END;
+
+CREATE TRIGGER reading_search_update AFTER UPDATE OF title, composer, info ON reading BEGIN
+    UPDATE reading_search
+    SET title = new.title, composer = COALESCE(new.composer, ''), info = COALESCE(new.info, '')
+    WHERE reading_id = old.id;
+END;
+
+CREATE TRIGGER reading_search_delete AFTER DELETE ON reading BEGIN
+    DELETE FROM reading_search WHERE reading_id = old.id;
+END;
+
+CREATE VIRTUAL TABLE practice_notes_search USING fts5 (
+    practice_plan_id UNINDEXED,
+    practice_notes
+);
+
+CREATE TRIGGER practice_notes_search_insert AFTER INSERT ON practice_plans BEGIN
+    INSERT INTO practice_notes_search (practice_plan_id, practice_notes)
+    VALUES (new.id, COALESCE(new.practice_notes, ''));
+END;
+
+CREATE TRIGGER practice_notes_search_update AFTER UPDATE OF practice_notes ON practice_plans BEGIN
+    UPDATE practice_notes_search
+    SET practice_notes = COALESCE(new.practice_notes, '')
+    WHERE practice_plan_id = old.id;
+END;
+
+CREATE TRIGGER practice_notes_search_delete AFTER DELETE ON practice_plans BEGIN
+    DELETE FROM practice_notes_search WHERE practice_plan_id = old.id;
+END;